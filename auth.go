@@ -0,0 +1,69 @@
+package lumberjack
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// apiKeySource supplies the API key for exporter requests: the static
+// Config.APIKey, or a token from Config.TokenProvider cached for TokenTTL.
+// A log request for a ContextWithProject route uses that route's key instead.
+type apiKeySource struct {
+	config *Config
+
+	mu      sync.Mutex
+	token   string
+	expires time.Time
+}
+
+func newAPIKeySource(config *Config) *apiKeySource {
+	return &apiKeySource{config: config}
+}
+
+func (s *apiKeySource) key(ctx context.Context) (string, error) {
+	if route, _ := ctx.Value(requestRouteKey{}).(*projectRoute); route != nil && route.key != "" {
+		return route.key, nil
+	}
+	if s.config.TokenProvider == nil {
+		return s.config.APIKey, nil
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.token != "" && time.Now().Before(s.expires) {
+		return s.token, nil
+	}
+	token, err := s.config.TokenProvider(ctx)
+	if err != nil {
+		return "", fmt.Errorf("token provider: %w", err)
+	}
+	s.token = token
+	s.expires = time.Now().Add(s.config.TokenTTL)
+	return token, nil
+}
+
+// setHeader sets the API key on req using the configured header name and
+// scheme, defaulting to "Authorization: Bearer <key>". With RawAPIKey the
+// key is sent without a scheme.
+func (s *apiKeySource) setHeader(req *http.Request) error {
+	key, err := s.key(req.Context())
+	if err != nil {
+		return err
+	}
+	name := s.config.AuthHeaderName
+	if name == "" {
+		name = "Authorization"
+	}
+	if !s.config.RawAPIKey {
+		scheme := s.config.AuthScheme
+		if scheme == "" {
+			scheme = "Bearer"
+		}
+		key = scheme + " " + key
+	}
+	req.Header.Set(name, key)
+	return nil
+}
@@ -0,0 +1,62 @@
+package lumberjack
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestTokenProviderCachesUntilTTL(t *testing.T) {
+	server := newCaptureServer(t)
+
+	var calls atomic.Int32
+	provider := func(ctx context.Context) (string, error) {
+		return fmt.Sprintf("token-%d", calls.Add(1)), nil
+	}
+	exporter := NewLogsExporter(testConfig(server.URL).WithTokenProvider(provider, 50*time.Millisecond))
+	defer exporter.Shutdown(context.Background())
+
+	send := func() string {
+		t.Helper()
+		if err := exporter.sendWithRetry(context.Background(), []byte(`{"logs":[]}`)); err != nil {
+			t.Fatalf("sendWithRetry() error = %v", err)
+		}
+		requests := server.requestsFor("/logs/batch")
+		return requests[len(requests)-1].Header.Get("Authorization")
+	}
+
+	if got := send(); got != "Bearer token-1" {
+		t.Errorf("first request Authorization = %q, want %q", got, "Bearer token-1")
+	}
+	if got := send(); got != "Bearer token-1" {
+		t.Errorf("cached request Authorization = %q, want %q", got, "Bearer token-1")
+	}
+	time.Sleep(60 * time.Millisecond)
+	if got := send(); got != "Bearer token-2" {
+		t.Errorf("request after TTL Authorization = %q, want %q", got, "Bearer token-2")
+	}
+	if calls.Load() != 2 {
+		t.Errorf("provider called %d times, want 2", calls.Load())
+	}
+}
+
+func TestTokenProviderError(t *testing.T) {
+	server := newCaptureServer(t)
+
+	errRotating := errors.New("secret is rotating")
+	provider := func(ctx context.Context) (string, error) {
+		return "", errRotating
+	}
+	exporter := NewLogsExporter(testConfig(server.URL).WithTokenProvider(provider, time.Minute))
+	defer exporter.Shutdown(context.Background())
+
+	if err := exporter.sendWithRetry(context.Background(), []byte(`{"logs":[]}`)); !errors.Is(err, errRotating) {
+		t.Errorf("sendWithRetry() error = %v, want the provider error", err)
+	}
+	if got := len(server.requestsFor("/logs/batch")); got != 0 {
+		t.Errorf("server received %d requests, want none without a token", got)
+	}
+}
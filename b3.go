@@ -0,0 +1,100 @@
+package lumberjack
+
+import (
+	"context"
+	"net/http"
+	"strings"
+
+	"go.opentelemetry.io/otel/trace"
+)
+
+// B3 headers, as emitted by Zipkin-instrumented services and Istio/Envoy.
+const (
+	b3SingleHeader  = "b3"
+	b3TraceIDHeader = "X-B3-TraceId"
+	b3SpanIDHeader  = "X-B3-SpanId"
+	b3SampledHeader = "X-B3-Sampled"
+	b3FlagsHeader   = "X-B3-Flags"
+)
+
+// ContextWithB3 returns a copy of ctx carrying the remote span context encoded
+// in B3 headers, either the single "b3: {traceid}-{spanid}-{sampled}" header
+// or the X-B3-TraceId, X-B3-SpanId and X-B3-Sampled multi-header form. The
+// single header wins when both are present. 64-bit trace IDs are left-padded
+// to 128 bits. It returns ctx and false if the headers carry no valid span
+// context.
+func ContextWithB3(ctx context.Context, headers http.Header) (context.Context, bool) {
+	var spanCtx trace.SpanContext
+	var ok bool
+	if single := headers.Get(b3SingleHeader); single != "" {
+		spanCtx, ok = parseB3Single(single)
+	} else {
+		spanCtx, ok = parseB3Multi(headers)
+	}
+	if !ok {
+		return ctx, false
+	}
+	return trace.ContextWithRemoteSpanContext(ctx, spanCtx), true
+}
+
+// parseB3Single parses "{traceid}-{spanid}[-{sampled}[-{parentspanid}]]".
+// A sampling-only value such as "0" carries no span context.
+func parseB3Single(value string) (trace.SpanContext, bool) {
+	parts := strings.Split(value, "-")
+	if len(parts) < 2 || len(parts) > 4 {
+		return trace.SpanContext{}, false
+	}
+	sampled := ""
+	if len(parts) > 2 {
+		sampled = parts[2]
+	}
+	return newB3SpanContext(parts[0], parts[1], sampled, false)
+}
+
+func parseB3Multi(headers http.Header) (trace.SpanContext, bool) {
+	return newB3SpanContext(
+		headers.Get(b3TraceIDHeader),
+		headers.Get(b3SpanIDHeader),
+		headers.Get(b3SampledHeader),
+		headers.Get(b3FlagsHeader) == "1",
+	)
+}
+
+// newB3SpanContext builds a remote span context from B3 fields. Debug, in
+// either encoding, implies sampled.
+func newB3SpanContext(traceIDHex, spanIDHex, sampled string, debug bool) (trace.SpanContext, bool) {
+	if len(traceIDHex) == 16 {
+		traceIDHex = strings.Repeat("0", 16) + traceIDHex
+	}
+	if len(traceIDHex) != 32 || len(spanIDHex) != 16 {
+		return trace.SpanContext{}, false
+	}
+	traceID, err := trace.TraceIDFromHex(traceIDHex)
+	if err != nil {
+		return trace.SpanContext{}, false
+	}
+	spanID, err := trace.SpanIDFromHex(spanIDHex)
+	if err != nil {
+		return trace.SpanContext{}, false
+	}
+
+	var flags trace.TraceFlags
+	switch sampled {
+	case "1", "true", "d":
+		flags = trace.FlagsSampled
+	case "", "0", "false":
+	default:
+		return trace.SpanContext{}, false
+	}
+	if debug {
+		flags = trace.FlagsSampled
+	}
+
+	spanCtx := trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID:    traceID,
+		SpanID:     spanID,
+		TraceFlags: flags,
+		Remote:     true,
+	})
+	return spanCtx, spanCtx.IsValid()
+}
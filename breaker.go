@@ -0,0 +1,136 @@
+package lumberjack
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+// errCircuitOpen is returned instead of sending while the circuit breaker is
+// open. It is not permanent, so the batch is spooled if spooling is enabled.
+var errCircuitOpen = errors.New("circuit breaker open")
+
+// defaultCircuitBreakerCooldown is how long the circuit stays open when
+// Config.CircuitBreakerCooldown is unset.
+const defaultCircuitBreakerCooldown = 30 * time.Second
+
+// CircuitState is the state of the circuit breaker the default exporters
+// share.
+type CircuitState int
+
+const (
+	// CircuitClosed sends batches normally.
+	CircuitClosed CircuitState = iota
+	// CircuitOpen fails batches immediately, without any HTTP request.
+	CircuitOpen
+	// CircuitHalfOpen lets a single probe batch through after the cooldown;
+	// its outcome closes or reopens the circuit.
+	CircuitHalfOpen
+)
+
+func (s CircuitState) String() string {
+	switch s {
+	case CircuitClosed:
+		return "closed"
+	case CircuitOpen:
+		return "open"
+	case CircuitHalfOpen:
+		return "half-open"
+	default:
+		return "CircuitState(unknown)"
+	}
+}
+
+// circuitBreaker stops the exporters from sending after threshold
+// consecutive failed batches until cooldown has passed. A nil breaker always
+// allows sending.
+type circuitBreaker struct {
+	threshold int
+	cooldown  time.Duration
+
+	mu       sync.Mutex
+	state    CircuitState
+	failures int
+	openedAt time.Time
+	probing  bool
+}
+
+// newCircuitBreaker returns the breaker configured by config, or nil when
+// Config.CircuitBreakerThreshold is not positive.
+func newCircuitBreaker(config *Config) *circuitBreaker {
+	if config.CircuitBreakerThreshold <= 0 {
+		return nil
+	}
+	cooldown := config.CircuitBreakerCooldown
+	if cooldown <= 0 {
+		cooldown = defaultCircuitBreakerCooldown
+	}
+	return &circuitBreaker{threshold: config.CircuitBreakerThreshold, cooldown: cooldown}
+}
+
+// allow reports whether a batch may be sent now. Once the cooldown has
+// passed it lets exactly one probe through until that probe is recorded.
+func (b *circuitBreaker) allow() bool {
+	if b == nil {
+		return true
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.state == CircuitOpen && time.Since(b.openedAt) >= b.cooldown {
+		b.state = CircuitHalfOpen
+	}
+	switch b.state {
+	case CircuitClosed:
+		return true
+	case CircuitHalfOpen:
+		if b.probing {
+			return false
+		}
+		b.probing = true
+		return true
+	default:
+		return false
+	}
+}
+
+// record updates the breaker with the outcome of a send allowed by allow.
+// Any response from the endpoint, even a permanent 4xx, counts as success;
+// a send abandoned by its caller's context counts as neither.
+func (b *circuitBreaker) record(err error) {
+	if b == nil {
+		return
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	halfOpen := b.state == CircuitHalfOpen
+	b.probing = false
+	switch {
+	case err == nil || errors.Is(err, errPermanent):
+		b.state = CircuitClosed
+		b.failures = 0
+	case errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded):
+	default:
+		b.failures++
+		if halfOpen || b.failures >= b.threshold {
+			b.state = CircuitOpen
+			b.openedAt = time.Now()
+		}
+	}
+}
+
+// currentState returns the breaker's state, reporting an open circuit whose
+// cooldown has passed as half-open.
+func (b *circuitBreaker) currentState() CircuitState {
+	if b == nil {
+		return CircuitClosed
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.state == CircuitOpen && time.Since(b.openedAt) >= b.cooldown {
+		return CircuitHalfOpen
+	}
+	return b.state
+}
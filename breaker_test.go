@@ -0,0 +1,88 @@
+package lumberjack
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestCircuitBreaker(t *testing.T) {
+	server := newCaptureServer(t)
+	server.setStatus(http.StatusServiceUnavailable)
+
+	config := testConfig(server.URL).
+		WithMaxRetries(0).
+		WithCircuitBreaker(2, 100*time.Millisecond)
+	sdk := newSDK(config)
+	defer sdk.Shutdown(context.Background())
+	logs, spans := sdk.defaultLogsExporter, sdk.defaultSpanExporter
+	requests := func() int {
+		return len(server.requestsFor("/logs/batch")) + len(server.requestsFor("/spans/batch"))
+	}
+
+	// Two failed batches open the circuit
+	logs.sendBatch(context.Background(), []LogEntry{{Msg: "first"}})
+	logs.sendBatch(context.Background(), []LogEntry{{Msg: "second"}})
+	if got := sdk.Stats().Circuit; got != CircuitOpen {
+		t.Fatalf("Stats().Circuit = %v after 2 failures, want %v", got, CircuitOpen)
+	}
+
+	// While open, no exporter makes an HTTP request
+	logs.sendBatch(context.Background(), []LogEntry{{Msg: "third"}})
+	spans.sendBatch(context.Background(), []InternalSpan{{Name: "work"}})
+	if got := requests(); got != 2 {
+		t.Errorf("got %d requests, want 2: none while the circuit is open", got)
+	}
+	if err := logs.sendWithRetry(context.Background(), []byte(`{"logs":[]}`)); !errors.Is(err, errCircuitOpen) {
+		t.Errorf("sendWithRetry() error = %v, want errCircuitOpen", err)
+	}
+
+	// After the cooldown a successful probe closes the circuit
+	server.setStatus(http.StatusOK)
+	time.Sleep(150 * time.Millisecond)
+	if got := sdk.Stats().Circuit; got != CircuitHalfOpen {
+		t.Errorf("Stats().Circuit = %v after the cooldown, want %v", got, CircuitHalfOpen)
+	}
+	spans.sendBatch(context.Background(), []InternalSpan{{Name: "probe"}})
+	logs.sendBatch(context.Background(), []LogEntry{{Msg: "recovered"}})
+	if got := sdk.Stats().Circuit; got != CircuitClosed {
+		t.Errorf("Stats().Circuit = %v after a successful probe, want %v", got, CircuitClosed)
+	}
+	if got := requests(); got != 4 {
+		t.Errorf("got %d requests, want 4 once the circuit closes", got)
+	}
+}
+
+func TestCircuitBreakerProbeFailureReopens(t *testing.T) {
+	breaker := newCircuitBreaker(testConfig("").WithCircuitBreaker(1, 50*time.Millisecond))
+	failure := errors.New("unavailable")
+
+	breaker.record(failure)
+	if breaker.allow() {
+		t.Fatal("allow() = true with the circuit open")
+	}
+
+	time.Sleep(60 * time.Millisecond)
+	if !breaker.allow() {
+		t.Fatal("allow() = false after the cooldown, want a probe")
+	}
+	if breaker.allow() {
+		t.Error("allow() = true for a second probe while the first is in flight")
+	}
+	breaker.record(failure)
+	if got := breaker.currentState(); got != CircuitOpen {
+		t.Errorf("state = %v after a failed probe, want %v", got, CircuitOpen)
+	}
+}
+
+func TestCircuitBreakerDisabled(t *testing.T) {
+	breaker := newCircuitBreaker(testConfig(""))
+	for i := 0; i < 10; i++ {
+		breaker.record(errors.New("unavailable"))
+	}
+	if !breaker.allow() || breaker.currentState() != CircuitClosed {
+		t.Error("a disabled breaker should always allow sending")
+	}
+}
@@ -0,0 +1,151 @@
+package lumberjack
+
+import (
+	"sync"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+)
+
+// overflowAttributes replaces the attributes of every series a metric records
+// beyond Config.MaxMetricSeries.
+var overflowAttributes = attribute.NewSet(attribute.String("overflow", "true"))
+
+// seriesLimiter tracks the distinct attribute sets seen per metric name and
+// admits at most max of them. A nil limiter admits everything.
+type seriesLimiter struct {
+	config *Config
+	max    int
+
+	mu     sync.Mutex
+	seen   map[string]map[attribute.Distinct]struct{}
+	warned map[string]bool
+}
+
+func newSeriesLimiter(config *Config) *seriesLimiter {
+	if config.MaxMetricSeries <= 0 {
+		return nil
+	}
+	return &seriesLimiter{
+		config: config,
+		max:    config.MaxMetricSeries,
+		seen:   make(map[string]map[attribute.Distinct]struct{}),
+		warned: make(map[string]bool),
+	}
+}
+
+// admit reports whether attrs is, or can become, one of name's tracked
+// series. The first time name overflows a warning is printed.
+func (l *seriesLimiter) admit(name string, attrs attribute.Set) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	series, ok := l.seen[name]
+	if !ok {
+		series = make(map[attribute.Distinct]struct{})
+		l.seen[name] = series
+	}
+	key := attrs.Equivalent()
+	if _, ok := series[key]; ok {
+		return true
+	}
+	if len(series) < l.max {
+		series[key] = struct{}{}
+		return true
+	}
+	if !l.warned[name] {
+		l.warned[name] = true
+		l.config.warnf("metric %q exceeded %d attribute sets; further ones are reported as {overflow: true}\n", name, l.max)
+	}
+	return false
+}
+
+// limitDataPoints collapses the counter or gauge points of series that l
+// doesn't admit into a single overflow point: summed for counters, the
+// latest value for gauges.
+func limitDataPoints[N int64 | float64](l *seriesLimiter, name string, dps []metricdata.DataPoint[N], gauge bool) []metricdata.DataPoint[N] {
+	if l == nil {
+		return dps
+	}
+	kept := make([]metricdata.DataPoint[N], 0, len(dps))
+	var overflow *metricdata.DataPoint[N]
+	for _, dp := range dps {
+		if l.admit(name, dp.Attributes) {
+			kept = append(kept, dp)
+			continue
+		}
+		if overflow == nil {
+			overflow = &metricdata.DataPoint[N]{Attributes: overflowAttributes, StartTime: dp.StartTime, Time: dp.Time, Value: dp.Value}
+			continue
+		}
+		if gauge {
+			if !dp.Time.Before(overflow.Time) {
+				overflow.Value = dp.Value
+			}
+		} else {
+			overflow.Value += dp.Value
+		}
+		if dp.StartTime.Before(overflow.StartTime) {
+			overflow.StartTime = dp.StartTime
+		}
+		if dp.Time.After(overflow.Time) {
+			overflow.Time = dp.Time
+		}
+	}
+	if overflow != nil {
+		kept = append(kept, *overflow)
+	}
+	return kept
+}
+
+// limitHistogramPoints collapses the histogram points of series that l
+// doesn't admit into a single overflow point merging their counts, sums,
+// extremes and buckets.
+func limitHistogramPoints[N int64 | float64](l *seriesLimiter, name string, dps []metricdata.HistogramDataPoint[N]) []metricdata.HistogramDataPoint[N] {
+	if l == nil {
+		return dps
+	}
+	kept := make([]metricdata.HistogramDataPoint[N], 0, len(dps))
+	var overflow *metricdata.HistogramDataPoint[N]
+	for _, dp := range dps {
+		if l.admit(name, dp.Attributes) {
+			kept = append(kept, dp)
+			continue
+		}
+		if overflow == nil {
+			merged := dp
+			merged.Attributes = overflowAttributes
+			merged.BucketCounts = append([]uint64(nil), dp.BucketCounts...)
+			merged.Exemplars = nil
+			overflow = &merged
+			continue
+		}
+		overflow.Count += dp.Count
+		overflow.Sum += dp.Sum
+		if min, ok := dp.Min.Value(); ok {
+			if current, ok := overflow.Min.Value(); !ok || min < current {
+				overflow.Min = metricdata.NewExtrema(min)
+			}
+		}
+		if max, ok := dp.Max.Value(); ok {
+			if current, ok := overflow.Max.Value(); !ok || max > current {
+				overflow.Max = metricdata.NewExtrema(max)
+			}
+		}
+		for i := range overflow.BucketCounts {
+			if i < len(dp.BucketCounts) {
+				overflow.BucketCounts[i] += dp.BucketCounts[i]
+			}
+		}
+		if dp.StartTime.Before(overflow.StartTime) {
+			overflow.StartTime = dp.StartTime
+		}
+		if dp.Time.After(overflow.Time) {
+			overflow.Time = dp.Time
+		}
+	}
+	if overflow != nil {
+		kept = append(kept, *overflow)
+	}
+	return kept
+}
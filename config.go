@@ -2,13 +2,22 @@ package lumberjack
 
 import (
 	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"io"
 	"log/slog"
+	"net/http"
+	"net/url"
 	"os"
+	"regexp"
 	"strconv"
 	"time"
 
+	"go.opentelemetry.io/otel/propagation"
 	sdklog "go.opentelemetry.io/otel/sdk/log"
 	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
 	sdktrace "go.opentelemetry.io/otel/sdk/trace"
 )
 
@@ -18,22 +27,350 @@ type LogsExporter interface {
 	Shutdown(ctx context.Context) error
 }
 
+// JitterStrategy decides how retry delays are randomized around the current
+// backoff, so clients that failed together don't retry in lockstep.
+type JitterStrategy int
+
+const (
+	// JitterFull waits the backoff plus up to as much again: [b, 2b).
+	JitterFull JitterStrategy = iota
+	// JitterNone waits exactly the backoff.
+	JitterNone
+	// JitterEqual waits half the backoff plus up to another half: [b/2, b).
+	JitterEqual
+)
+
+func (s JitterStrategy) String() string {
+	switch s {
+	case JitterFull:
+		return "JitterFull"
+	case JitterNone:
+		return "JitterNone"
+	case JitterEqual:
+		return "JitterEqual"
+	default:
+		return "JitterStrategy(unknown)"
+	}
+}
+
+// SpanProcessorKind selects how finished spans are handed to the exporter.
+type SpanProcessorKind int
+
+const (
+	// BatchSpanProcessor buffers finished spans and exports them in batches.
+	BatchSpanProcessor SpanProcessorKind = iota
+	// SimpleSpanProcessor exports each span synchronously when it ends, so
+	// none are lost if the process exits early. Suited to CLIs and tests, not
+	// to high-throughput services.
+	SimpleSpanProcessor
+)
+
+func (k SpanProcessorKind) String() string {
+	switch k {
+	case BatchSpanProcessor:
+		return "BatchSpanProcessor"
+	case SimpleSpanProcessor:
+		return "SimpleSpanProcessor"
+	default:
+		return "SpanProcessorKind(unknown)"
+	}
+}
+
 type Config struct {
 	APIKey      string
 	BaseURL     string
 	Debug       bool
 	ProjectName string
 	
+	// DebugWriter receives the SDK's own diagnostics: warnings, and with
+	// Debug on, export progress and failures. Defaults to os.Stderr.
+	DebugWriter io.Writer
+	
+	// AuthHeaderName and AuthScheme control how the API key is sent:
+	// "<AuthHeaderName>: <AuthScheme> <APIKey>". Empty values mean
+	// Authorization and Bearer. RawAPIKey sends just the key, with no scheme.
+	AuthHeaderName string
+	AuthScheme     string
+	RawAPIKey      bool
+	
+	// LogSource is sent as the "src" of every log entry, identifying the
+	// component that produced it. Defaults to lumberjack-go.
+	LogSource string
+	
+	// TokenProvider, if set, is called for the API key instead of using
+	// APIKey, e.g. to fetch a rotating key from a secrets manager. Its result
+	// is cached for TokenTTL; zero calls it for every request.
+	TokenProvider func(ctx context.Context) (string, error)
+	TokenTTL      time.Duration
+	
+	// ServiceVersion is reported as service.version and used as the release
+	// id when LUMBERJACK_RELEASE_ID is unset. Falls back to
+	// LUMBERJACK_SERVICE_VERSION when empty.
+	ServiceVersion string
+	
+	// ReleaseID and ReleaseType are sent with every batch. They fall back to
+	// LUMBERJACK_RELEASE_ID and LUMBERJACK_RELEASE_TYPE when empty.
+	ReleaseID   string
+	ReleaseType string
+	
+	// Environment is sent as the env of span and metric batches, e.g.
+	// "staging". Falls back to LUMBERJACK_ENVIRONMENT, then to "development"
+	// with Debug on and "production" otherwise.
+	Environment string
+	
+	// ResourceAttributes are attached to every span and metric, e.g.
+	// deployment.environment or host.name. They override the default
+	// service.name and service.version when they use the same key.
+	ResourceAttributes map[string]string
+	
+	// DetectResources adds host.name, process.pid and
+	// process.runtime.version to the resource. NewConfig enables it;
+	// ResourceAttributes override the detected values.
+	DetectResources bool
+	
+	// DetectK8s adds k8s.pod.name, k8s.namespace.name and k8s.node.name to
+	// the resource from the downward-API environment variables POD_NAME,
+	// POD_NAMESPACE and NODE_NAME, or the variables named by the K8s*Env
+	// fields. Unset variables are skipped.
+	DetectK8s       bool
+	K8sPodNameEnv   string
+	K8sNamespaceEnv string
+	K8sNodeNameEnv  string
+	
 	BatchSize     int
 	BatchTimeout  time.Duration
 	MaxRetries    int
 	RetryBackoff  time.Duration
 	
+	// JitterStrategy randomizes each retry delay (JitterFull by default) and
+	// MaxBackoff caps it, however many times the backoff has doubled. Zero
+	// MaxBackoff means no cap.
+	JitterStrategy JitterStrategy
+	MaxBackoff     time.Duration
+	
+	// MaxRetryElapsed caps the total time spent sending one batch, including
+	// backoff: no further attempt is made once it would be exceeded, whatever
+	// MaxRetries allows. Zero means only MaxRetries limits retrying.
+	MaxRetryElapsed time.Duration
+	
+	// HTTPClient, if set, is used by the default exporters as is. Otherwise
+	// they build a client with a 30s timeout whose transport uses TLSConfig,
+	// e.g. for client certificates or a private CA.
+	HTTPClient *http.Client
+	TLSConfig  *tls.Config
+	
+	// MaxQueueSize bounds the number of entries each exporter buffers in
+	// memory; OverflowPolicy decides what happens when it is reached. Zero
+	// means unbounded.
+	MaxQueueSize   int
+	OverflowPolicy OverflowPolicy
+	
+	// LogBatchShards splits the logs exporter's buffer into this many
+	// independently locked sub-batches, merged on flush, so many goroutines
+	// logging at once don't contend on a single lock. Entries are put back
+	// in timestamp order when the shards are merged. It is ignored when MaxQueueSize
+	// is set, since the queue bound applies to the whole buffer.
+	LogBatchShards int
+	
+	// MaxBatchBytes flushes a batch once the approximate size of its entries
+	// reaches this many bytes, even if BatchSize hasn't been reached. Zero
+	// disables the byte cap.
+	MaxBatchBytes int
+	
+	// MaxRequestBytes splits a flushed batch into several requests, sent one
+	// after another, so that none exceeds roughly this many bytes, e.g. the
+	// server's request size limit. Zero sends each batch in one request.
+	MaxRequestBytes int
+	
+	// MaxMessageBytes and MaxAttrValueBytes cap the size of a log message and
+	// of each string attribute value; longer ones are cut and the entry gets a
+	// "truncated" prop. Zero means unlimited.
+	MaxMessageBytes   int
+	MaxAttrValueBytes int
+	
+	// StreamThresholdBytes makes the default exporters encode batches whose
+	// approximate size reaches this many bytes straight into the request body
+	// instead of building the whole payload in memory first. Zero disables
+	// streaming.
+	StreamThresholdBytes int
+	
+	// SpoolDir enables on-disk buffering: batches that still fail after all
+	// retries are written there and replayed every SpoolReplayInterval. The
+	// spool is capped at SpoolMaxBytes per signal, evicting the oldest
+	// batches first.
+	SpoolDir            string
+	SpoolMaxBytes       int64
+	SpoolReplayInterval time.Duration
+	
+	// File rotation for NewFileLogsExporter - once the file would grow past
+	// MaxFileBytes it is renamed to <path>.1, shifting older backups up, and
+	// at most MaxBackups backups are kept. Zero MaxFileBytes never rotates.
+	MaxFileBytes int64
+	MaxBackups   int
+	
+	// ContinueExportOnCancel detaches exporter HTTP requests from the context
+	// passed to Export, so a canceled request context doesn't abort delivery.
+	ContinueExportOnCancel bool
+	
+	// ShutdownTimeout bounds how long SDK.Shutdown may take when its context
+	// has no deadline, e.g. ShutdownWithTimeout, so a hung flush can't block
+	// exit. A caller's own deadline, longer or shorter, is used instead.
+	// Zero means only the context bounds it.
+	ShutdownTimeout time.Duration
+	
+	// CircuitBreakerThreshold stops the default exporters from sending after
+	// this many consecutive batches fail, for CircuitBreakerCooldown (30s by
+	// default). Failed batches are spooled if SpoolDir is set and dropped
+	// otherwise. After the cooldown one batch probes the endpoint. Zero
+	// disables the breaker.
+	CircuitBreakerThreshold int
+	CircuitBreakerCooldown  time.Duration
+	
+	// UserAgent replaces the default "lumberjack-go/<version>" User-Agent
+	// header on export requests, e.g. to identify the application.
+	UserAgent string
+	
+	// MaxBufferAge bounds how long a buffered entry may wait before it is
+	// flushed, independently of BatchTimeout. Zero disables the check.
+	MaxBufferAge time.Duration
+	
+	// MinLogLevel is the lowest level exported to Lumberjack. It is read on
+	// every log call, so a *slog.LevelVar can change it at runtime, until
+	// SDK.SetLogLevel overrides it. It does not affect local console output.
+	// Nil means slog.LevelDebug.
+	MinLogLevel slog.Leveler
+	
+	// ContextAttrExtractor, if set, is called with the context of every
+	// exported log record and its attributes are added to the record, e.g. a
+	// tenant or request id carried in the context. It may return nil.
+	ContextAttrExtractor func(ctx context.Context) []slog.Attr
+	
 	// slog integration
 	ReplaceSlog         bool
 	PreviousSlogHandler slog.Handler
 	CaptureStdLog       bool // NEW – redirect log.Printf etc. to slog
 	
+	// Redaction - attributes whose key matches RedactKeys (case-insensitive,
+	// also matching the last segment of group-prefixed keys like "db.password")
+	// or whose value matches one of RedactValuePatterns are exported as
+	// "[REDACTED]"
+	RedactKeys          []string
+	RedactValuePatterns []*regexp.Regexp
+	
+	// Before-send hooks get each batch the default exporters are about to
+	// send, after redaction, and return the entries to send in its place; a
+	// nil or empty result drops the batch
+	BeforeSendLogs    func([]LogEntry) []LogEntry
+	BeforeSendSpans   func([]InternalSpan) []InternalSpan
+	BeforeSendMetrics func([]MetricPoint) []MetricPoint
+	
+	// OnExportError, if set, is called when the default exporters fail to
+	// deliver count entries of signal ("logs", "spans" or "metrics") with
+	// err. Failed batches are still spooled when spooling is on, unless err
+	// is permanent; log entries the server rejects individually (ErrRejected)
+	// are reported once they have been retried MaxRetries times
+	OnExportError func(signal string, count int, err error)
+	
+	// TypedAttributes exports log attribute values with their JSON types
+	// (numbers, booleans, arrays) and flattens slog groups into dotted keys
+	// such as "db.host". By default every value is exported as a string and
+	// a group as a single JSON-encoded object string
+	TypedAttributes bool
+	
+	// Key normalization - when NormalizeKeys is set, log and span attribute
+	// keys are rewritten with KeyNormalizer, or SnakeCaseKey if it is nil.
+	// FlattenKeys additionally replaces dots with underscores, so
+	// "http.statusCode" becomes "http_status_code" rather than the default
+	// "http.status_code"
+	NormalizeKeys bool
+	KeyNormalizer func(key string) string
+	FlattenKeys   bool
+	
+	// URL scrubbing - query parameter values in URL-valued span attributes are
+	// replaced with REDACTED unless the parameter is listed in SafeQueryParams,
+	// or KeepURLQueries opts out of scrubbing entirely
+	KeepURLQueries  bool
+	SafeQueryParams []string
+	
+	// Fingerprinting - when Fingerprint is set, ERROR and FATAL log entries
+	// carry a "fingerprint" grouping key computed by Fingerprinter, or
+	// DefaultFingerprint if it is nil
+	Fingerprint   bool
+	Fingerprinter func(entry LogEntry) string
+	
+	// MetricTemporality selects cumulative (the default) or delta temporality.
+	// With delta, counters and histograms report per-interval increments;
+	// up-down counters stay cumulative.
+	MetricTemporality metricdata.Temporality
+	
+	// Histogram buckets - HistogramBoundaries replaces the default OTEL bucket
+	// boundaries for every histogram; HistogramBoundariesByName overrides them
+	// for individual instruments
+	HistogramBoundaries       []float64
+	HistogramBoundariesByName map[string][]float64
+	
+	// MaxMetricSeries caps the distinct attribute sets the default metrics
+	// exporter reports per metric name; points of further sets are merged
+	// into one series with the attribute overflow=true. Zero means no cap.
+	MaxMetricSeries int
+	
+	// MetricReaders are registered on the meter provider alongside the
+	// Lumberjack periodic reader, e.g. a Prometheus exporter
+	MetricReaders []sdkmetric.Reader
+	
+	// Rate limiting - when RateLimitPerSecond is positive, logs sharing a
+	// message (or the value of the RateLimitKey attribute, if set) are limited
+	// to that rate, and the rest are reported in a "suppressed N similar
+	// logs" summary
+	RateLimitPerSecond float64
+	RateLimitKey       string
+	
+	// Deduplication - when DedupeWindow is positive, a log identical to one
+	// exported less than DedupeWindow ago (same level, message and values of
+	// the DedupeKeys attributes; none by default) is dropped, and the next
+	// copy exported carries the number dropped as suppressed.count
+	DedupeWindow time.Duration
+	DedupeKeys   []string
+	
+	// Span events - when LogsAsSpanEvents is set, logs at or above
+	// SpanEventMinLevel (INFO by default) emitted within a recording span are
+	// also added to it as events; SpanEventsOnly skips the normal export for
+	// those logs
+	LogsAsSpanEvents  bool
+	SpanEventMinLevel slog.Level
+	SpanEventsOnly    bool
+	
+	// AutoErrorMetric counts every log at ERROR or above in a
+	// "lumberjack.logs.errors" counter with a "level" attribute, giving an
+	// error-rate signal without instrumenting call sites
+	AutoErrorMetric bool
+	
+	// SemconvHTTPMetrics records request metrics with the OpenTelemetry
+	// semantic convention attributes http.request.method, http.route and
+	// http.response.status_code instead of the legacy method, path and
+	// status_code. These names come from semconv v1.26.0, while spans and
+	// the resource still use v1.17.0 (http.method, http.status_code), so
+	// existing span queries and the resource schema URL are unaffected
+	SemconvHTTPMetrics bool
+	
+	// SpanProcessor selects batched (the default) or synchronous span export
+	SpanProcessor SpanProcessorKind
+	
+	// Sampler decides which spans are recorded. Defaults to the OpenTelemetry
+	// default, parent-based always-on.
+	Sampler sdktrace.Sampler
+	
+	// SpanFilter, if set, is called for every ended span before the default
+	// span exporter queues it; spans it returns false for are dropped. Unlike
+	// Sampler it sees the finished span, e.g. its status and duration (see
+	// KeepErrorsAndSlowerThan). Dropped parents leave their kept children
+	// without a parent in the trace.
+	SpanFilter func(sdktrace.ReadOnlySpan) bool
+	
+	// Propagator is installed as the global OpenTelemetry text map
+	// propagator on Init. Defaults to W3C trace context plus baggage.
+	Propagator propagation.TextMapPropagator
+	
 	// Custom exporters - if provided, these will be used instead of the default ones
 	CustomSpanExporter    sdktrace.SpanExporter
 	CustomMetricsExporter sdkmetric.Exporter
@@ -65,11 +402,22 @@ func NewConfig() *Config {
 		BaseURL:      getEnvOrDefault("LUMBERJACK_BASE_URL", "https://api.trylumberjack.com"),
 		Debug:        debug,
 		ProjectName:  os.Getenv("LUMBERJACK_PROJECT_NAME"),
+		
+		AuthHeaderName: "Authorization",
+		AuthScheme:     "Bearer",
+		LogSource:      defaultLogSource,
+		
 		BatchSize:    batchSize,
 		BatchTimeout: 5 * time.Second,
 		MaxRetries:   3,
 		RetryBackoff: 250 * time.Millisecond,
 		ReplaceSlog:  replaceSlog,
+		MinLogLevel:  slog.LevelDebug,
+		
+		DetectResources: true,
+		
+		ContinueExportOnCancel: true,
+		ShutdownTimeout:        10 * time.Second,
 	}
 }
 
@@ -88,11 +436,433 @@ func (c *Config) WithDebug(debug bool) *Config {
 	return c
 }
 
+func (c *Config) WithDebugWriter(w io.Writer) *Config {
+	c.DebugWriter = w
+	return c
+}
+
 func (c *Config) WithProjectName(name string) *Config {
 	c.ProjectName = name
 	return c
 }
 
+// WithAuthHeader sets the header the API key is sent in and its scheme, e.g.
+// WithAuthHeader("X-API-Key", "") to send the raw key.
+func (c *Config) WithAuthHeader(name, scheme string) *Config {
+	c.AuthHeaderName = name
+	c.AuthScheme = scheme
+	c.RawAPIKey = scheme == ""
+	return c
+}
+
+// WithTokenProvider fetches the API key from provider, caching each token
+// for ttl.
+func (c *Config) WithTokenProvider(provider func(ctx context.Context) (string, error), ttl time.Duration) *Config {
+	c.TokenProvider = provider
+	c.TokenTTL = ttl
+	return c
+}
+
+func (c *Config) WithLogSource(source string) *Config {
+	c.LogSource = source
+	return c
+}
+
+func (c *Config) WithServiceVersion(version string) *Config {
+	c.ServiceVersion = version
+	return c
+}
+
+func (c *Config) WithReleaseID(id string) *Config {
+	c.ReleaseID = id
+	return c
+}
+
+func (c *Config) WithReleaseType(releaseType string) *Config {
+	c.ReleaseType = releaseType
+	return c
+}
+
+func (c *Config) WithEnvironment(environment string) *Config {
+	c.Environment = environment
+	return c
+}
+
+// WithResourceAttributes merges attrs into the resource attributes attached to
+// every span and metric.
+func (c *Config) WithResourceAttributes(attrs map[string]string) *Config {
+	if c.ResourceAttributes == nil {
+		c.ResourceAttributes = make(map[string]string, len(attrs))
+	}
+	for key, value := range attrs {
+		c.ResourceAttributes[key] = value
+	}
+	return c
+}
+
+func (c *Config) WithDetectResources(detect bool) *Config {
+	c.DetectResources = detect
+	return c
+}
+
+func (c *Config) WithDetectK8s(detect bool) *Config {
+	c.DetectK8s = detect
+	return c
+}
+
+// WithK8sEnvKeys sets the environment variables the Kubernetes detector
+// reads the pod name, namespace and node name from. Empty names keep the
+// defaults.
+func (c *Config) WithK8sEnvKeys(podName, namespace, nodeName string) *Config {
+	c.K8sPodNameEnv = podName
+	c.K8sNamespaceEnv = namespace
+	c.K8sNodeNameEnv = nodeName
+	return c
+}
+
+func (c *Config) WithMinLogLevel(level slog.Level) *Config {
+	c.MinLogLevel = level
+	return c
+}
+
+// WithBatchSize sets how many entries an exporter buffers before flushing.
+// Non-positive sizes are ignored.
+func (c *Config) WithBatchSize(size int) *Config {
+	if size > 0 {
+		c.BatchSize = size
+	}
+	return c
+}
+
+// WithBatchTimeout sets the interval between periodic flushes. Non-positive
+// durations are ignored.
+func (c *Config) WithBatchTimeout(timeout time.Duration) *Config {
+	if timeout > 0 {
+		c.BatchTimeout = timeout
+	}
+	return c
+}
+
+// WithMaxRetries sets how many times a failed send is retried. Negative
+// values are clamped to zero, which sends each batch once.
+func (c *Config) WithMaxRetries(retries int) *Config {
+	c.MaxRetries = max(retries, 0)
+	return c
+}
+
+// WithRetryBackoff sets the delay before the first retry; it doubles after
+// each attempt. Non-positive durations are ignored.
+func (c *Config) WithRetryBackoff(backoff time.Duration) *Config {
+	if backoff > 0 {
+		c.RetryBackoff = backoff
+	}
+	return c
+}
+
+func (c *Config) WithJitterStrategy(strategy JitterStrategy) *Config {
+	c.JitterStrategy = strategy
+	return c
+}
+
+func (c *Config) WithMaxBackoff(backoff time.Duration) *Config {
+	c.MaxBackoff = backoff
+	return c
+}
+
+func (c *Config) WithMaxRetryElapsed(elapsed time.Duration) *Config {
+	c.MaxRetryElapsed = elapsed
+	return c
+}
+
+// WithHTTPClient sets the client used by the default exporters. It takes
+// precedence over WithTLSConfig.
+func (c *Config) WithHTTPClient(client *http.Client) *Config {
+	c.HTTPClient = client
+	return c
+}
+
+// WithTLSConfig sets the TLS configuration of the default exporters' client.
+// It is ignored when WithHTTPClient is also used.
+func (c *Config) WithTLSConfig(tlsConfig *tls.Config) *Config {
+	c.TLSConfig = tlsConfig
+	return c
+}
+
+// WithRateLimit limits logs with the same message to perSecond.
+func (c *Config) WithRateLimit(perSecond float64) *Config {
+	c.RateLimitPerSecond = perSecond
+	return c
+}
+
+// WithRateLimitKey groups rate-limited logs by the value of the key
+// attribute instead of by message.
+func (c *Config) WithRateLimitKey(key string) *Config {
+	c.RateLimitKey = key
+	return c
+}
+
+// WithDedupeWindow drops logs identical to one exported within window.
+func (c *Config) WithDedupeWindow(window time.Duration) *Config {
+	c.DedupeWindow = window
+	return c
+}
+
+// WithDedupeKeys sets the attributes whose values, besides level and
+// message, tell two logs apart for deduplication.
+func (c *Config) WithDedupeKeys(keys ...string) *Config {
+	c.DedupeKeys = keys
+	return c
+}
+
+// WithLogsAsSpanEvents records logs at or above minLevel as events on the
+// enclosing span, in addition to exporting them.
+func (c *Config) WithLogsAsSpanEvents(minLevel slog.Level) *Config {
+	c.LogsAsSpanEvents = true
+	c.SpanEventMinLevel = minLevel
+	return c
+}
+
+// WithSpanEventsOnly skips the normal export for logs recorded as span events.
+func (c *Config) WithSpanEventsOnly(only bool) *Config {
+	c.SpanEventsOnly = only
+	return c
+}
+
+func (c *Config) WithMaxQueueSize(size int, policy OverflowPolicy) *Config {
+	c.MaxQueueSize = size
+	c.OverflowPolicy = policy
+	return c
+}
+
+func (c *Config) WithLogBatchShards(shards int) *Config {
+	c.LogBatchShards = shards
+	return c
+}
+
+func (c *Config) WithMaxBatchBytes(bytes int) *Config {
+	c.MaxBatchBytes = bytes
+	return c
+}
+
+func (c *Config) WithMaxRequestBytes(bytes int) *Config {
+	c.MaxRequestBytes = bytes
+	return c
+}
+
+func (c *Config) WithMaxMessageBytes(bytes int) *Config {
+	c.MaxMessageBytes = bytes
+	return c
+}
+
+func (c *Config) WithMaxAttrValueBytes(bytes int) *Config {
+	c.MaxAttrValueBytes = bytes
+	return c
+}
+
+func (c *Config) WithStreamThresholdBytes(bytes int) *Config {
+	c.StreamThresholdBytes = bytes
+	return c
+}
+
+func (c *Config) WithUserAgent(userAgent string) *Config {
+	c.UserAgent = userAgent
+	return c
+}
+
+func (c *Config) WithShutdownTimeout(timeout time.Duration) *Config {
+	c.ShutdownTimeout = timeout
+	return c
+}
+
+// WithCircuitBreaker stops sending for cooldown after threshold consecutive
+// failed batches.
+func (c *Config) WithCircuitBreaker(threshold int, cooldown time.Duration) *Config {
+	c.CircuitBreakerThreshold = threshold
+	c.CircuitBreakerCooldown = cooldown
+	return c
+}
+
+func (c *Config) WithSpoolDir(dir string) *Config {
+	c.SpoolDir = dir
+	return c
+}
+
+// WithFileRotation rotates NewFileLogsExporter's file at maxBytes, keeping
+// maxBackups old files.
+func (c *Config) WithFileRotation(maxBytes int64, maxBackups int) *Config {
+	c.MaxFileBytes = maxBytes
+	c.MaxBackups = maxBackups
+	return c
+}
+
+func (c *Config) WithContinueExportOnCancel(continueOnCancel bool) *Config {
+	c.ContinueExportOnCancel = continueOnCancel
+	return c
+}
+
+func (c *Config) WithMaxBufferAge(age time.Duration) *Config {
+	c.MaxBufferAge = age
+	return c
+}
+
+func (c *Config) WithContextAttrExtractor(extract func(ctx context.Context) []slog.Attr) *Config {
+	c.ContextAttrExtractor = extract
+	return c
+}
+
+func (c *Config) WithRedactKeys(keys ...string) *Config {
+	c.RedactKeys = keys
+	return c
+}
+
+func (c *Config) WithRedactValuePatterns(patterns ...*regexp.Regexp) *Config {
+	c.RedactValuePatterns = patterns
+	return c
+}
+
+func (c *Config) WithBeforeSendLogs(hook func([]LogEntry) []LogEntry) *Config {
+	c.BeforeSendLogs = hook
+	return c
+}
+
+func (c *Config) WithBeforeSendSpans(hook func([]InternalSpan) []InternalSpan) *Config {
+	c.BeforeSendSpans = hook
+	return c
+}
+
+func (c *Config) WithBeforeSendMetrics(hook func([]MetricPoint) []MetricPoint) *Config {
+	c.BeforeSendMetrics = hook
+	return c
+}
+
+func (c *Config) WithOnExportError(hook func(signal string, count int, err error)) *Config {
+	c.OnExportError = hook
+	return c
+}
+
+func (c *Config) WithTypedAttributes(typed bool) *Config {
+	c.TypedAttributes = typed
+	return c
+}
+
+func (c *Config) WithNormalizeKeys(normalize bool) *Config {
+	c.NormalizeKeys = normalize
+	return c
+}
+
+// WithKeyNormalizer enables key normalization using a custom function.
+func (c *Config) WithKeyNormalizer(normalizer func(key string) string) *Config {
+	c.NormalizeKeys = true
+	c.KeyNormalizer = normalizer
+	return c
+}
+
+// WithFlattenKeys enables key normalization and also replaces dots in keys
+// with underscores, for backends that expect flat snake_case keys.
+func (c *Config) WithFlattenKeys(flatten bool) *Config {
+	c.FlattenKeys = flatten
+	if flatten {
+		c.NormalizeKeys = true
+	}
+	return c
+}
+
+// WithKeepURLQueries exports URL-valued span attributes unchanged instead
+// of scrubbing their query parameter values.
+func (c *Config) WithKeepURLQueries(keep bool) *Config {
+	c.KeepURLQueries = keep
+	return c
+}
+
+// WithSafeQueryParams sets the query parameters whose values are kept when
+// scrubbing URL-valued span attributes. Matching is case-insensitive.
+func (c *Config) WithSafeQueryParams(params ...string) *Config {
+	c.SafeQueryParams = params
+	return c
+}
+
+func (c *Config) WithFingerprint(fingerprint bool) *Config {
+	c.Fingerprint = fingerprint
+	return c
+}
+
+// WithFingerprinter enables fingerprinting using a custom function.
+func (c *Config) WithFingerprinter(fingerprinter func(entry LogEntry) string) *Config {
+	c.Fingerprint = true
+	c.Fingerprinter = fingerprinter
+	return c
+}
+
+func (c *Config) WithMetricTemporality(temporality metricdata.Temporality) *Config {
+	c.MetricTemporality = temporality
+	return c
+}
+
+// WithHistogramBoundaries sets the bucket upper bounds used by all histograms.
+func (c *Config) WithHistogramBoundaries(boundaries ...float64) *Config {
+	c.HistogramBoundaries = boundaries
+	return c
+}
+
+// WithInstrumentHistogramBoundaries sets the bucket upper bounds for the
+// histogram named name, overriding HistogramBoundaries.
+func (c *Config) WithInstrumentHistogramBoundaries(name string, boundaries ...float64) *Config {
+	if c.HistogramBoundariesByName == nil {
+		c.HistogramBoundariesByName = make(map[string][]float64)
+	}
+	c.HistogramBoundariesByName[name] = boundaries
+	return c
+}
+
+// WithAutoErrorMetric records a "lumberjack.logs.errors" counter of logs at
+// ERROR or above.
+func (c *Config) WithAutoErrorMetric(enabled bool) *Config {
+	c.AutoErrorMetric = enabled
+	return c
+}
+
+func (c *Config) WithMaxMetricSeries(max int) *Config {
+	c.MaxMetricSeries = max
+	return c
+}
+
+// WithMetricReader registers an additional metric reader, so instruments
+// created through the SDK are exported to it as well.
+func (c *Config) WithMetricReader(reader sdkmetric.Reader) *Config {
+	c.MetricReaders = append(c.MetricReaders, reader)
+	return c
+}
+
+// WithSemconvHTTPMetrics records request metrics with semantic convention
+// attribute names instead of the legacy method, path and status_code. The
+// names follow semconv v1.26.0; span attributes keep following v1.17.0.
+func (c *Config) WithSemconvHTTPMetrics(enabled bool) *Config {
+	c.SemconvHTTPMetrics = enabled
+	return c
+}
+
+// WithSpanProcessor selects how finished spans are exported, e.g.
+// SimpleSpanProcessor to export each span as soon as it ends.
+func (c *Config) WithSpanProcessor(kind SpanProcessorKind) *Config {
+	c.SpanProcessor = kind
+	return c
+}
+
+func (c *Config) WithSampler(sampler sdktrace.Sampler) *Config {
+	c.Sampler = sampler
+	return c
+}
+
+func (c *Config) WithSpanFilter(filter func(sdktrace.ReadOnlySpan) bool) *Config {
+	c.SpanFilter = filter
+	return c
+}
+
+func (c *Config) WithPropagator(propagator propagation.TextMapPropagator) *Config {
+	c.Propagator = propagator
+	return c
+}
+
 func (c *Config) WithCustomSpanExporter(exporter sdktrace.SpanExporter) *Config {
 	c.CustomSpanExporter = exporter
 	return c
@@ -118,6 +888,113 @@ func (c *Config) WithCaptureStdLog(capture bool) *Config {
 	return c
 }
 
+// ErrInvalidConfig is wrapped by every error Config.Validate returns.
+var ErrInvalidConfig = errors.New("invalid lumberjack config")
+
+// Validate reports every problem with c that would leave the SDK unable to
+// export, joined into one error. Init proceeds regardless; InitE refuses to.
+func (c *Config) Validate() error {
+	var errs []error
+	if c.ProjectName == "" {
+		errs = append(errs, fmt.Errorf("%w: project name is empty", ErrInvalidConfig))
+	}
+	if c.BatchSize <= 0 {
+		errs = append(errs, fmt.Errorf("%w: batch size must be positive, got %d", ErrInvalidConfig, c.BatchSize))
+	}
+	if c.MaxRetries < 0 {
+		errs = append(errs, fmt.Errorf("%w: max retries must not be negative, got %d", ErrInvalidConfig, c.MaxRetries))
+	}
+	if u, err := url.Parse(c.BaseURL); err != nil {
+		errs = append(errs, fmt.Errorf("%w: base URL: %v", ErrInvalidConfig, err))
+	} else if u.Scheme == "" || u.Host == "" {
+		errs = append(errs, fmt.Errorf("%w: base URL %q must be absolute", ErrInvalidConfig, c.BaseURL))
+	}
+	return errors.Join(errs...)
+}
+
+// serviceVersion returns the configured service version, falling back to the
+// LUMBERJACK_SERVICE_VERSION environment variable.
+func (c *Config) serviceVersion() string {
+	if c.ServiceVersion != "" {
+		return c.ServiceVersion
+	}
+	return os.Getenv("LUMBERJACK_SERVICE_VERSION")
+}
+
+// releaseID returns the configured release id, falling back to
+// LUMBERJACK_RELEASE_ID and then the service version.
+func (c *Config) releaseID() string {
+	if c.ReleaseID != "" {
+		return c.ReleaseID
+	}
+	return getEnvOrDefault("LUMBERJACK_RELEASE_ID", c.serviceVersion())
+}
+
+// releaseType returns the configured release type, falling back to
+// LUMBERJACK_RELEASE_TYPE.
+func (c *Config) releaseType() string {
+	if c.ReleaseType != "" {
+		return c.ReleaseType
+	}
+	return os.Getenv("LUMBERJACK_RELEASE_TYPE")
+}
+
+// environment returns the configured environment, falling back to
+// LUMBERJACK_ENVIRONMENT and then to one derived from Debug.
+func (c *Config) environment() string {
+	if c.Environment != "" {
+		return c.Environment
+	}
+	if env := os.Getenv("LUMBERJACK_ENVIRONMENT"); env != "" {
+		return env
+	}
+	if c.Debug {
+		return "development"
+	}
+	return "production"
+}
+
+// propagator returns Propagator, or a W3C trace context and baggage
+// propagator when it is nil.
+func (c *Config) propagator() propagation.TextMapPropagator {
+	if c.Propagator != nil {
+		return c.Propagator
+	}
+	return propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{})
+}
+
+// minLogLevel returns the current MinLogLevel, or slog.LevelDebug when it is
+// nil.
+func (c *Config) minLogLevel() slog.Level {
+	if c.MinLogLevel == nil {
+		return slog.LevelDebug
+	}
+	return c.MinLogLevel.Level()
+}
+
+// logSource returns LogSource, or lumberjack-go when it is empty.
+func (c *Config) logSource() string {
+	if c.LogSource != "" {
+		return c.LogSource
+	}
+	return defaultLogSource
+}
+
+func (c *Config) userAgent() string {
+	if c.UserAgent != "" {
+		return c.UserAgent
+	}
+	return defaultUserAgent()
+}
+
+// logBatchShards returns how many sub-batches the logs exporter buffers in.
+func (c *Config) logBatchShards() int {
+	if c.LogBatchShards <= 1 || c.MaxQueueSize > 0 {
+		return 1
+	}
+	return c.LogBatchShards
+}
+
 func getEnvOrDefault(key, defaultValue string) string {
 	if value := os.Getenv(key); value != "" {
 		return value
@@ -0,0 +1,374 @@
+package lumberjack
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"go.opentelemetry.io/otel/log"
+	sdklog "go.opentelemetry.io/otel/sdk/log"
+)
+
+func TestReleaseFromConfig(t *testing.T) {
+	t.Setenv("LUMBERJACK_RELEASE_ID", "env-release")
+	t.Setenv("LUMBERJACK_RELEASE_TYPE", "random")
+
+	server := newCaptureServer(t)
+	config := testConfig(server.URL).WithReleaseID("abc123").WithReleaseType("commit")
+
+	logs := NewLogsExporter(config)
+	spans := NewSpanExporter(config)
+	metrics := NewMetricsExporter(config)
+	defer logs.Shutdown(context.Background())
+	defer spans.Shutdown(context.Background())
+	defer metrics.Shutdown(context.Background())
+
+	logs.sendBatch(context.Background(), []LogEntry{{Msg: "hello"}})
+	spans.sendBatch(context.Background(), []InternalSpan{{Name: "work"}})
+	metrics.sendBatch(context.Background(), []MetricPoint{{Name: "requests", Value: 1}})
+
+	var logReq LogRequest
+	var spanReq SpanBatchRequest
+	var metricReq MetricsBatchRequest
+	decoded := map[string]any{
+		"/logs/batch":    &logReq,
+		"/spans/batch":   &spanReq,
+		"/metrics/batch": &metricReq,
+	}
+	for path, v := range decoded {
+		requests := server.requestsFor(path)
+		if len(requests) != 1 {
+			t.Fatalf("expected 1 request to %s, got %d", path, len(requests))
+		}
+		if err := json.Unmarshal(requests[0].Body, v); err != nil {
+			t.Fatalf("failed to decode %s request: %v", path, err)
+		}
+	}
+
+	got := map[string][2]string{
+		"logs":    {logReq.ReleaseId, logReq.ReleaseType},
+		"spans":   {spanReq.Payload.ReleaseId, spanReq.Payload.ReleaseType},
+		"metrics": {metricReq.Payload.ReleaseId, metricReq.Payload.ReleaseType},
+	}
+	for signal, release := range got {
+		if release != [2]string{"abc123", "commit"} {
+			t.Errorf("%s release = %v, want the configured [abc123 commit]", signal, release)
+		}
+	}
+}
+
+func TestReleaseFallsBackToEnv(t *testing.T) {
+	t.Setenv("LUMBERJACK_RELEASE_ID", "env-release")
+	t.Setenv("LUMBERJACK_RELEASE_TYPE", "random")
+
+	config := testConfig("")
+	if got := config.releaseID(); got != "env-release" {
+		t.Errorf("releaseID() = %q, want %q", got, "env-release")
+	}
+	if got := config.releaseType(); got != "random" {
+		t.Errorf("releaseType() = %q, want %q", got, "random")
+	}
+}
+
+func TestEnvironmentFromConfig(t *testing.T) {
+	server := newCaptureServer(t)
+	config := testConfig(server.URL).WithDebug(true).WithEnvironment("staging")
+
+	spans := NewSpanExporter(config)
+	metrics := NewMetricsExporter(config)
+	defer spans.Shutdown(context.Background())
+	defer metrics.Shutdown(context.Background())
+
+	spans.sendBatch(context.Background(), []InternalSpan{{Name: "work"}})
+	metrics.sendBatch(context.Background(), []MetricPoint{{Name: "requests", Value: 1}})
+
+	var spanReq SpanBatchRequest
+	var metricReq MetricsBatchRequest
+	for path, v := range map[string]any{"/spans/batch": &spanReq, "/metrics/batch": &metricReq} {
+		requests := server.requestsFor(path)
+		if len(requests) != 1 {
+			t.Fatalf("expected 1 request to %s, got %d", path, len(requests))
+		}
+		if err := json.Unmarshal(requests[0].Body, v); err != nil {
+			t.Fatalf("failed to decode %s request: %v", path, err)
+		}
+	}
+	if spanReq.Env != "staging" || metricReq.Env != "staging" {
+		t.Errorf("env = %q (spans), %q (metrics), want the configured %q", spanReq.Env, metricReq.Env, "staging")
+	}
+}
+
+func TestEnvironmentFallback(t *testing.T) {
+	if got := testConfig("").WithDebug(true).environment(); got != "development" {
+		t.Errorf("environment() with Debug = %q, want %q", got, "development")
+	}
+	if got := testConfig("").WithDebug(false).environment(); got != "production" {
+		t.Errorf("environment() = %q, want %q", got, "production")
+	}
+
+	t.Setenv("LUMBERJACK_ENVIRONMENT", "qa")
+	if got := testConfig("").WithDebug(true).environment(); got != "qa" {
+		t.Errorf("environment() = %q, want LUMBERJACK_ENVIRONMENT %q", got, "qa")
+	}
+}
+
+func TestBatchTuningBuilders(t *testing.T) {
+	server := newCaptureServer(t)
+	server.setStatus(http.StatusServiceUnavailable)
+
+	config := testConfig(server.URL).
+		WithBatchSize(2).
+		WithBatchTimeout(time.Minute).
+		WithMaxRetries(1).
+		WithRetryBackoff(time.Millisecond)
+	if config.BatchSize != 2 || config.BatchTimeout != time.Minute || config.MaxRetries != 1 || config.RetryBackoff != time.Millisecond {
+		t.Fatalf("builders not applied: %+v", config)
+	}
+
+	exporter := NewLogsExporter(config)
+	defer exporter.Shutdown(context.Background())
+
+	// Two records fill the batch; one retry means two attempts in total.
+	records := []*sdklog.Record{
+		newTestRecord("first", log.SeverityInfo),
+		newTestRecord("second", log.SeverityInfo),
+	}
+	if err := exporter.Export(context.Background(), records); err != nil {
+		t.Fatalf("Export() unexpected error = %v", err)
+	}
+	server.waitForRequest(t, time.Second)
+	server.waitForRequest(t, time.Second)
+	time.Sleep(20 * time.Millisecond)
+	if got := len(server.requestsFor("/logs/batch")); got != 2 {
+		t.Errorf("got %d send attempts, want 2", got)
+	}
+}
+
+func TestBatchTuningBuildersIgnoreInvalidValues(t *testing.T) {
+	config := NewConfig().
+		WithBatchSize(0).
+		WithBatchTimeout(-time.Second).
+		WithMaxRetries(-1).
+		WithRetryBackoff(0)
+
+	defaults := NewConfig()
+	if config.BatchSize != defaults.BatchSize {
+		t.Errorf("BatchSize = %d, want default %d", config.BatchSize, defaults.BatchSize)
+	}
+	if config.BatchTimeout != defaults.BatchTimeout {
+		t.Errorf("BatchTimeout = %v, want default %v", config.BatchTimeout, defaults.BatchTimeout)
+	}
+	if config.MaxRetries != 0 {
+		t.Errorf("MaxRetries = %d, want 0", config.MaxRetries)
+	}
+	if config.RetryBackoff != defaults.RetryBackoff {
+		t.Errorf("RetryBackoff = %v, want default %v", config.RetryBackoff, defaults.RetryBackoff)
+	}
+}
+
+func TestTLSConfigTrustsCustomCA(t *testing.T) {
+	var received atomic.Int32
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received.Add(1)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	pool := x509.NewCertPool()
+	pool.AddCert(server.Certificate())
+
+	config := testConfig(server.URL).WithMaxRetries(0).WithTLSConfig(&tls.Config{RootCAs: pool})
+	exporter := NewLogsExporter(config)
+	defer exporter.Shutdown(context.Background())
+
+	if err := exporter.sendWithRetry(context.Background(), []byte(`{"logs":[]}`)); err != nil {
+		t.Fatalf("sendWithRetry() error = %v", err)
+	}
+	if received.Load() != 1 {
+		t.Errorf("server received %d requests, want 1", received.Load())
+	}
+
+	// Without the custom CA the server's certificate is untrusted.
+	untrusted := NewLogsExporter(testConfig(server.URL).WithMaxRetries(0))
+	defer untrusted.Shutdown(context.Background())
+	if err := untrusted.sendWithRetry(context.Background(), []byte(`{"logs":[]}`)); err == nil {
+		t.Error("sendWithRetry() without the custom CA succeeded, want a certificate error")
+	}
+}
+
+func TestHTTPClientTakesPrecedenceOverTLSConfig(t *testing.T) {
+	client := &http.Client{}
+	config := NewConfig().WithTLSConfig(&tls.Config{}).WithHTTPClient(client)
+	if got := newHTTPClient(config); got != client {
+		t.Errorf("newHTTPClient() = %p, want the configured client %p", got, client)
+	}
+}
+
+func TestAuthHeader(t *testing.T) {
+	tests := []struct {
+		name       string
+		config     func(*Config) *Config
+		wantHeader string
+		wantValue  string
+	}{
+		{
+			name:       "default",
+			config:     func(c *Config) *Config { return c },
+			wantHeader: "Authorization",
+			wantValue:  "Bearer test-key",
+		},
+		{
+			name:       "custom scheme",
+			config:     func(c *Config) *Config { return c.WithAuthHeader("Authorization", "Token") },
+			wantHeader: "Authorization",
+			wantValue:  "Token test-key",
+		},
+		{
+			name:       "raw key",
+			config:     func(c *Config) *Config { return c.WithAuthHeader("X-API-Key", "") },
+			wantHeader: "X-API-Key",
+			wantValue:  "test-key",
+		},
+		{
+			name: "literal config without a scheme",
+			config: func(c *Config) *Config {
+				return &Config{APIKey: c.APIKey, BaseURL: c.BaseURL, BatchSize: c.BatchSize, BatchTimeout: c.BatchTimeout}
+			},
+			wantHeader: "Authorization",
+			wantValue:  "Bearer test-key",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := newCaptureServer(t)
+			exporter := NewLogsExporter(tt.config(testConfig(server.URL)))
+			defer exporter.Shutdown(context.Background())
+
+			if err := exporter.sendWithRetry(context.Background(), []byte(`{"logs":[]}`)); err != nil {
+				t.Fatalf("sendWithRetry() error = %v", err)
+			}
+			requests := server.requestsFor("/logs/batch")
+			if len(requests) != 1 {
+				t.Fatalf("got %d requests, want 1", len(requests))
+			}
+			if got := requests[0].Header.Get(tt.wantHeader); got != tt.wantValue {
+				t.Errorf("%s header = %q, want %q", tt.wantHeader, got, tt.wantValue)
+			}
+			if tt.wantHeader != "Authorization" && requests[0].Header.Get("Authorization") != "" {
+				t.Errorf("Authorization header sent alongside %s", tt.wantHeader)
+			}
+		})
+	}
+}
+
+func TestLogSource(t *testing.T) {
+	tests := []struct {
+		name   string
+		config func(*Config) *Config
+		want   string
+	}{
+		{name: "default", config: func(c *Config) *Config { return c }, want: "lumberjack-go"},
+		{name: "custom", config: func(c *Config) *Config { return c.WithLogSource("edge-proxy") }, want: "edge-proxy"},
+		{name: "empty", config: func(c *Config) *Config { return c.WithLogSource("") }, want: "lumberjack-go"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := newCaptureServer(t)
+			sdk := newSDK(tt.config(testConfig(server.URL)))
+			sdk.Logger().Info("hello")
+			sdk.Shutdown(context.Background())
+
+			requests := server.decodeLogRequests(t)
+			if len(requests) != 1 || len(requests[0].Logs) != 1 {
+				t.Fatalf("expected a single exported log, got %+v", requests)
+			}
+			if got := requests[0].Logs[0].Src; got != tt.want {
+				t.Errorf("src = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestUserAgent(t *testing.T) {
+	tests := []struct {
+		name      string
+		userAgent string
+		want      string
+	}{
+		{"default", "", "lumberjack-go/" + Version},
+		{"override", "checkout-api/1.4.2", "checkout-api/1.4.2"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := newCaptureServer(t)
+			config := testConfig(server.URL).WithUserAgent(tt.userAgent)
+			logs, spans, metrics := NewLogsExporter(config), NewSpanExporter(config), NewMetricsExporter(config)
+			defer logs.Shutdown(context.Background())
+			defer spans.Shutdown(context.Background())
+			defer metrics.Shutdown(context.Background())
+
+			for _, send := range []func(context.Context, []byte) error{logs.sendWithRetry, spans.sendWithRetry, metrics.sendWithRetry} {
+				if err := send(context.Background(), []byte(`{}`)); err != nil {
+					t.Fatalf("sendWithRetry() error = %v", err)
+				}
+			}
+			for _, path := range []string{"/logs/batch", "/spans/batch", "/metrics/batch"} {
+				requests := server.requestsFor(path)
+				if len(requests) != 1 {
+					t.Fatalf("got %d requests to %s, want 1", len(requests), path)
+				}
+				if got := requests[0].Header.Get("User-Agent"); got != tt.want {
+					t.Errorf("%s User-Agent = %q, want %q", path, got, tt.want)
+				}
+			}
+		})
+	}
+}
+
+func TestConfigValidate(t *testing.T) {
+	tests := map[string]struct {
+		modify func(*Config)
+		want   string
+	}{
+		"empty project name":   {func(c *Config) { c.ProjectName = "" }, "project name is empty"},
+		"zero batch size":      {func(c *Config) { c.BatchSize = 0 }, "batch size must be positive"},
+		"negative max retries": {func(c *Config) { c.MaxRetries = -1 }, "max retries must not be negative"},
+		"unparseable base URL": {func(c *Config) { c.BaseURL = "http://[::1" }, "base URL"},
+		"relative base URL":    {func(c *Config) { c.BaseURL = "api.example.com" }, "must be absolute"},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			config := testConfig("https://api.example.com")
+			tt.modify(config)
+			err := config.Validate()
+			if !errors.Is(err, ErrInvalidConfig) {
+				t.Fatalf("Validate() error = %v, want ErrInvalidConfig", err)
+			}
+			if !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("Validate() error = %q, want it to mention %q", err, tt.want)
+			}
+		})
+	}
+
+	if err := testConfig("https://api.example.com").Validate(); err != nil {
+		t.Errorf("Validate() on a valid config = %v, want nil", err)
+	}
+}
+
+func TestInitE(t *testing.T) {
+	sdk, err := InitE(testConfig("https://api.example.com").WithProjectName(""))
+	if sdk != nil || !errors.Is(err, ErrInvalidConfig) {
+		t.Errorf("InitE() = %v, %v, want nil and ErrInvalidConfig", sdk, err)
+	}
+}
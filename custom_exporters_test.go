@@ -2,8 +2,10 @@ package lumberjack
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"os"
+	"sync"
 	"testing"
 	"time"
 
@@ -140,4 +142,72 @@ func TestAllCustomExporters(t *testing.T) {
 	// Force flush to see output
 	sdk.tracerProvider.ForceFlush(ctx)
 	sdk.meterProvider.ForceFlush(ctx)
-}
\ No newline at end of file
+}
+
+// memoryLogsExporter keeps every record body it is given and can be told to
+// fail each Export call.
+type memoryLogsExporter struct {
+	mu       sync.Mutex
+	bodies   []string
+	flushes  int
+	shutdown bool
+	err      error
+}
+
+func (e *memoryLogsExporter) Export(ctx context.Context, records []*sdklog.Record) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	for _, record := range records {
+		e.bodies = append(e.bodies, record.Body().String())
+	}
+	return e.err
+}
+
+func (e *memoryLogsExporter) ForceFlush(ctx context.Context) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.flushes++
+	return nil
+}
+
+func (e *memoryLogsExporter) Shutdown(ctx context.Context) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.shutdown = true
+	return nil
+}
+
+func TestMultiLogsExporter(t *testing.T) {
+	exportErr := errors.New("disk full")
+	failing := &memoryLogsExporter{err: exportErr}
+	healthy := &memoryLogsExporter{}
+
+	config := testConfig("http://127.0.0.1:0").
+		WithCustomLogsExporter(MultiLogsExporter(failing, healthy))
+	sdk := newSDK(config)
+
+	sdk.Logger().Info("first")
+	sdk.Logger().Warn("second")
+
+	if err := sdk.loggerProvider.ForceFlush(context.Background()); err != nil {
+		t.Fatalf("ForceFlush() unexpected error = %v", err)
+	}
+	sdk.Shutdown(context.Background())
+
+	for name, exporter := range map[string]*memoryLogsExporter{"failing": failing, "healthy": healthy} {
+		if len(exporter.bodies) != 2 || exporter.bodies[0] != "first" || exporter.bodies[1] != "second" {
+			t.Errorf("%s exporter received %v, want [first second]", name, exporter.bodies)
+		}
+		if exporter.flushes != 1 {
+			t.Errorf("%s exporter flushed %d times, want 1", name, exporter.flushes)
+		}
+		if !exporter.shutdown {
+			t.Errorf("%s exporter was not shut down", name)
+		}
+	}
+
+	record := newTestRecord("third", 0)
+	if err := MultiLogsExporter(failing, healthy).Export(context.Background(), []*sdklog.Record{record}); !errors.Is(err, exportErr) {
+		t.Errorf("Export() error = %v, want it to wrap %v", err, exportErr)
+	}
+}
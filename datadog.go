@@ -0,0 +1,70 @@
+package lumberjack
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Datadog trace headers, carrying 64-bit ids in decimal.
+const (
+	datadogTraceIDHeader          = "X-Datadog-Trace-Id"
+	datadogParentIDHeader         = "X-Datadog-Parent-Id"
+	datadogSamplingPriorityHeader = "X-Datadog-Sampling-Priority"
+)
+
+// ContextWithDatadogHeaders returns a copy of ctx carrying the remote span
+// context encoded in Datadog's x-datadog-trace-id and x-datadog-parent-id
+// headers, so spans started from it join the Datadog trace. The 64-bit
+// decimal trace id is zero-padded to 128 bits. A positive
+// x-datadog-sampling-priority (auto or user keep) marks the context sampled.
+// It returns ctx and false if the headers carry no valid span context.
+func ContextWithDatadogHeaders(ctx context.Context, headers http.Header) (context.Context, bool) {
+	traceID, ok := parseDatadogTraceID(headers.Get(datadogTraceIDHeader))
+	if !ok {
+		return ctx, false
+	}
+	spanID, ok := parseDatadogSpanID(headers.Get(datadogParentIDHeader))
+	if !ok {
+		return ctx, false
+	}
+
+	var flags trace.TraceFlags
+	if priority, err := strconv.Atoi(headers.Get(datadogSamplingPriorityHeader)); err == nil && priority > 0 {
+		flags = trace.FlagsSampled
+	}
+
+	spanCtx := trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID:    traceID,
+		SpanID:     spanID,
+		TraceFlags: flags,
+		Remote:     true,
+	})
+	if !spanCtx.IsValid() {
+		return ctx, false
+	}
+	return trace.ContextWithRemoteSpanContext(ctx, spanCtx), true
+}
+
+// parseDatadogTraceID converts a decimal 64-bit Datadog trace id into the low
+// half of a 128-bit trace ID.
+func parseDatadogTraceID(decimal string) (trace.TraceID, bool) {
+	id, err := strconv.ParseUint(decimal, 10, 64)
+	if err != nil || id == 0 {
+		return trace.TraceID{}, false
+	}
+	traceID, err := trace.TraceIDFromHex(fmt.Sprintf("%032x", id))
+	return traceID, err == nil
+}
+
+func parseDatadogSpanID(decimal string) (trace.SpanID, bool) {
+	id, err := strconv.ParseUint(decimal, 10, 64)
+	if err != nil || id == 0 {
+		return trace.SpanID{}, false
+	}
+	spanID, err := trace.SpanIDFromHex(fmt.Sprintf("%016x", id))
+	return spanID, err == nil
+}
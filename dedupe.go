@@ -0,0 +1,179 @@
+package lumberjack
+
+import (
+	"container/list"
+	"context"
+	"hash/fnv"
+	"slices"
+	"strconv"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/otel/log"
+	sdklog "go.opentelemetry.io/otel/sdk/log"
+)
+
+// maxDedupeKeys bounds how many distinct logs the deduplicator remembers; the
+// least recently seen is forgotten first.
+const maxDedupeKeys = 10000
+
+// dedupeLogProcessor drops logs identical to one exported within the last
+// window: same severity, message and values of the configured key
+// attributes; other attributes, such as request IDs or durations, are
+// ignored. The next occurrence after
+// the window carries a suppressed.count attribute with the number dropped;
+// counts still pending on flush, or when a log is evicted, are reported on a
+// copy of the suppressed log.
+type dedupeLogProcessor struct {
+	sdklog.Processor
+	window time.Duration
+	keys   []string
+
+	mu      sync.Mutex
+	entries map[uint64]*list.Element
+	lru     *list.List
+}
+
+type dedupeEntry struct {
+	hash       uint64
+	start      time.Time
+	suppressed int
+	// record is a copy of the first suppressed log, kept to report the
+	// count if the log never reappears.
+	record *sdklog.Record
+}
+
+func newDedupeLogProcessor(next sdklog.Processor, config *Config) *dedupeLogProcessor {
+	return &dedupeLogProcessor{
+		Processor: next,
+		window:    config.DedupeWindow,
+		keys:      config.DedupeKeys,
+		entries:   make(map[uint64]*list.Element),
+		lru:       list.New(),
+	}
+}
+
+func (p *dedupeLogProcessor) OnEmit(ctx context.Context, record *sdklog.Record) error {
+	emit, suppressed, evicted := p.check(dedupeHash(record, p.keys), time.Now(), record)
+	if evicted != nil {
+		if err := p.Processor.OnEmit(ctx, evicted); err != nil {
+			return err
+		}
+	}
+	if !emit {
+		return nil
+	}
+	if suppressed > 0 {
+		record.AddAttributes(log.Int("suppressed.count", suppressed))
+	}
+	return p.Processor.OnEmit(ctx, record)
+}
+
+// check records an occurrence of the log with hash at now. It reports whether
+// the log should be exported and, if so, how many duplicates were dropped
+// since it last was. If making room evicted a log with pending duplicates,
+// it also returns that log's summary.
+func (p *dedupeLogProcessor) check(hash uint64, now time.Time, record *sdklog.Record) (bool, int, *sdklog.Record) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if elem, ok := p.entries[hash]; ok {
+		p.lru.MoveToFront(elem)
+		entry := elem.Value.(*dedupeEntry)
+		if now.Sub(entry.start) < p.window {
+			if entry.suppressed == 0 && record != nil {
+				clone := record.Clone()
+				entry.record = &clone
+			}
+			entry.suppressed++
+			return false, 0, nil
+		}
+		suppressed := entry.suppressed
+		entry.start, entry.suppressed, entry.record = now, 0, nil
+		return true, suppressed, nil
+	}
+
+	var evicted *sdklog.Record
+	if p.lru.Len() >= maxDedupeKeys {
+		oldest := p.lru.Remove(p.lru.Back()).(*dedupeEntry)
+		delete(p.entries, oldest.hash)
+		evicted = oldest.summary()
+	}
+	p.entries[hash] = p.lru.PushFront(&dedupeEntry{hash: hash, start: now})
+	return true, 0, evicted
+}
+
+// summary returns the suppressed log annotated with its count, or nil if
+// there is nothing to report.
+func (e *dedupeEntry) summary() *sdklog.Record {
+	if e.suppressed == 0 || e.record == nil {
+		return nil
+	}
+	r := e.record.Clone()
+	r.SetTimestamp(time.Now())
+	r.SetObservedTimestamp(time.Now())
+	r.AddAttributes(log.Int("suppressed.count", e.suppressed))
+	return &r
+}
+
+// flushSummaries reports every log with pending duplicates.
+func (p *dedupeLogProcessor) flushSummaries(ctx context.Context) error {
+	p.mu.Lock()
+	var summaries []*sdklog.Record
+	for elem := p.lru.Front(); elem != nil; elem = elem.Next() {
+		entry := elem.Value.(*dedupeEntry)
+		if summary := entry.summary(); summary != nil {
+			summaries = append(summaries, summary)
+		}
+		entry.suppressed, entry.record = 0, nil
+	}
+	p.mu.Unlock()
+
+	for _, summary := range summaries {
+		if err := p.Processor.OnEmit(ctx, summary); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (p *dedupeLogProcessor) ForceFlush(ctx context.Context) error {
+	if err := p.flushSummaries(ctx); err != nil {
+		return err
+	}
+	return p.Processor.ForceFlush(ctx)
+}
+
+func (p *dedupeLogProcessor) Shutdown(ctx context.Context) error {
+	if err := p.flushSummaries(ctx); err != nil {
+		return err
+	}
+	return p.Processor.Shutdown(ctx)
+}
+
+// dedupeHash hashes what makes two logs identical: severity, message and
+// the values of keys, in that order. A missing key hashes as empty.
+func dedupeHash(record *sdklog.Record, keys []string) uint64 {
+	h := fnv.New64a()
+	h.Write([]byte(strconv.Itoa(int(record.Severity()))))
+	h.Write([]byte{0})
+	h.Write([]byte(record.Body().String()))
+	if len(keys) == 0 {
+		return h.Sum64()
+	}
+
+	values := make(map[string]string, len(keys))
+	record.WalkAttributes(func(kv log.KeyValue) bool {
+		if slices.Contains(keys, kv.Key) {
+			values[kv.Key] = kv.Value.String()
+		}
+		return true
+	})
+	for _, key := range keys {
+		h.Write([]byte{0})
+		h.Write([]byte(key))
+		h.Write([]byte{'='})
+		h.Write([]byte(values[key]))
+	}
+	return h.Sum64()
+}
@@ -0,0 +1,110 @@
+package lumberjack
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestDedupeSuppressesIdenticalLogs(t *testing.T) {
+	server := newCaptureServer(t)
+	sdk := newSDK(testConfig(server.URL).WithDedupeWindow(time.Hour).WithDedupeKeys("host"))
+
+	for i := 0; i < 100; i++ {
+		sdk.Logger().Error("db connection refused", "host", "db-1", "attempt", i)
+	}
+	sdk.Logger().Error("db connection refused", "host", "db-2")
+	sdk.Logger().Info("db connection refused", "host", "db-1")
+	sdk.Shutdown(context.Background())
+
+	var exported, suppressed int
+	for _, req := range server.decodeLogRequests(t) {
+		for _, entry := range req.Logs {
+			if entry.Msg != "db connection refused" {
+				continue
+			}
+			exported++
+			if raw, ok := entry.Props["suppressed.count"]; ok {
+				count, err := strconv.Atoi(fmt.Sprint(raw))
+				if err != nil {
+					t.Fatalf("suppressed.count = %v, want an integer", raw)
+				}
+				if entry.Props["host"] != "db-1" || entry.Lvl != "ERROR" {
+					t.Errorf("suppression count reported on %s %v, want the db-1 error", entry.Lvl, entry.Props)
+				}
+				suppressed += count
+			}
+		}
+	}
+	// The db-1 error once plus its summary, and the distinct db-2 and INFO logs
+	if exported != 4 {
+		t.Errorf("exported %d logs, want 4", exported)
+	}
+	if suppressed != 99 {
+		t.Errorf("suppressed.count = %d, want 99", suppressed)
+	}
+}
+
+func TestDedupeIgnoresNonKeyAttributes(t *testing.T) {
+	server := newCaptureServer(t)
+	sdk := newSDK(testConfig(server.URL).WithDedupeWindow(time.Hour))
+
+	for i := 0; i < 10; i++ {
+		sdk.Logger().Warn("upstream flapping", "request_id", fmt.Sprintf("req-%d", i), "duration_ms", i*10)
+	}
+	sdk.Shutdown(context.Background())
+
+	var exported int
+	for _, req := range server.decodeLogRequests(t) {
+		for _, entry := range req.Logs {
+			if entry.Msg == "upstream flapping" {
+				exported++
+			}
+		}
+	}
+	// The first copy, plus the summary flushed on shutdown
+	if exported != 2 {
+		t.Errorf("exported %d logs, want 2 when only request_id and duration_ms differ", exported)
+	}
+}
+
+func TestDedupeWindowExpires(t *testing.T) {
+	p := newDedupeLogProcessor(nil, testConfig("").WithDedupeWindow(time.Second))
+	now := time.Now()
+
+	if emit, _, _ := p.check(1, now, nil); !emit {
+		t.Fatal("first occurrence was dropped")
+	}
+	for i := 0; i < 3; i++ {
+		if emit, _, _ := p.check(1, now.Add(500*time.Millisecond), nil); emit {
+			t.Fatal("duplicate within the window was exported")
+		}
+	}
+	emit, suppressed, _ := p.check(1, now.Add(time.Second), nil)
+	if !emit || suppressed != 3 {
+		t.Errorf("check() after the window = %v, %d, want true, 3", emit, suppressed)
+	}
+}
+
+func TestDedupeEvictsLeastRecentlySeen(t *testing.T) {
+	p := newDedupeLogProcessor(nil, testConfig("").WithDedupeWindow(time.Hour))
+	now := time.Now()
+
+	for hash := uint64(0); hash < maxDedupeKeys; hash++ {
+		p.check(hash, now, nil)
+	}
+	p.check(0, now, nil) // now the most recently seen
+	p.check(maxDedupeKeys, now, nil)
+
+	if len(p.entries) != maxDedupeKeys || p.lru.Len() != maxDedupeKeys {
+		t.Fatalf("tracking %d/%d logs, want at most %d", len(p.entries), p.lru.Len(), maxDedupeKeys)
+	}
+	if _, ok := p.entries[1]; ok {
+		t.Error("least recently seen log was not evicted")
+	}
+	if emit, _, _ := p.check(0, now, nil); emit {
+		t.Error("recently seen log was evicted")
+	}
+}
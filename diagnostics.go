@@ -0,0 +1,27 @@
+package lumberjack
+
+import (
+	"fmt"
+	"io"
+	"os"
+)
+
+// debugWriter returns DebugWriter, or os.Stderr when it is nil.
+func (c *Config) debugWriter() io.Writer {
+	if c.DebugWriter != nil {
+		return c.DebugWriter
+	}
+	return os.Stderr
+}
+
+// debugf writes an internal diagnostic to the debug writer when Debug is on.
+func (c *Config) debugf(format string, args ...any) {
+	if c.Debug {
+		fmt.Fprintf(c.debugWriter(), format, args...)
+	}
+}
+
+// warnf writes a warning to the debug writer whether or not Debug is on.
+func (c *Config) warnf(format string, args ...any) {
+	fmt.Fprintf(c.debugWriter(), "Warning: "+format, args...)
+}
@@ -0,0 +1,42 @@
+package lumberjack
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/log"
+	"go.opentelemetry.io/otel/metric"
+	sdklog "go.opentelemetry.io/otel/sdk/log"
+)
+
+// errorLogsMetricName is the counter incremented for every ERROR or FATAL log
+// when Config.AutoErrorMetric is set.
+const errorLogsMetricName = "lumberjack.logs.errors"
+
+// errorMetricLogProcessor counts logs at ERROR and above, by level, before
+// handing every log to the next processor.
+type errorMetricLogProcessor struct {
+	sdklog.Processor
+	errors metric.Int64Counter
+}
+
+func newErrorMetricLogProcessor(next sdklog.Processor, meter metric.Meter) (*errorMetricLogProcessor, error) {
+	counter, err := meter.Int64Counter(
+		errorLogsMetricName,
+		metric.WithDescription("Number of logs emitted at ERROR or above"),
+		metric.WithUnit("1"),
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &errorMetricLogProcessor{Processor: next, errors: counter}, nil
+}
+
+func (p *errorMetricLogProcessor) OnEmit(ctx context.Context, record *sdklog.Record) error {
+	if record.Severity() >= log.SeverityError {
+		p.errors.Add(ctx, 1, metric.WithAttributes(
+			attribute.String("level", severityToString(record.Severity())),
+		))
+	}
+	return p.Processor.OnEmit(ctx, record)
+}
@@ -0,0 +1,63 @@
+package lumberjack
+
+import (
+	"context"
+	"testing"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+)
+
+// errorLogCounts collects the error log counter from reader, keyed by level.
+func errorLogCounts(t *testing.T, reader metric.Reader) map[string]int64 {
+	t.Helper()
+	var rm metricdata.ResourceMetrics
+	if err := reader.Collect(context.Background(), &rm); err != nil {
+		t.Fatalf("Collect() unexpected error = %v", err)
+	}
+	counts := make(map[string]int64)
+	for _, sm := range rm.ScopeMetrics {
+		for _, m := range sm.Metrics {
+			if m.Name != errorLogsMetricName {
+				continue
+			}
+			for _, dp := range m.Data.(metricdata.Sum[int64]).DataPoints {
+				level, _ := dp.Attributes.Value(attribute.Key("level"))
+				counts[level.AsString()] += dp.Value
+			}
+		}
+	}
+	return counts
+}
+
+func TestAutoErrorMetric(t *testing.T) {
+	server := newCaptureServer(t)
+	reader := metric.NewManualReader()
+	sdk := newSDK(testConfig(server.URL).WithMetricReader(reader).WithAutoErrorMetric(true))
+	defer sdk.Shutdown(context.Background())
+
+	logger := sdk.Logger()
+	logger.Info("fine")
+	logger.Warn("careful")
+	logger.Error("failed")
+	logger.Error("failed again")
+
+	counts := errorLogCounts(t, reader)
+	if len(counts) != 1 || counts["ERROR"] != 2 {
+		t.Errorf("error log counts = %v, want map[ERROR:2]", counts)
+	}
+}
+
+func TestAutoErrorMetricDisabled(t *testing.T) {
+	server := newCaptureServer(t)
+	reader := metric.NewManualReader()
+	sdk := newSDK(testConfig(server.URL).WithMetricReader(reader))
+	defer sdk.Shutdown(context.Background())
+
+	sdk.Logger().Error("failed")
+
+	if counts := errorLogCounts(t, reader); len(counts) != 0 {
+		t.Errorf("error log counts = %v, want none when AutoErrorMetric is off", counts)
+	}
+}
@@ -0,0 +1,336 @@
+package lumberjack
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// errPermanent marks a send failure that retrying can never fix, such as a
+// 4xx response.
+var errPermanent = errors.New("permanent export failure")
+
+// ErrRejected is reported to Config.OnExportError for log entries the server
+// rejected individually while accepting the rest of their batch.
+var ErrRejected = errors.New("entries rejected by server")
+
+// maxRejectionBody bounds how much of a response body is read looking for
+// rejected entries.
+const maxRejectionBody = 1 << 20
+
+// rejectionError is returned for a batch the server accepted except for the
+// entries at indices. Retrying the batch as a whole would duplicate the
+// accepted entries, so it is permanent.
+type rejectionError struct {
+	indices []int
+}
+
+func (e *rejectionError) Error() string {
+	return fmt.Sprintf("%v: %d entries", ErrRejected, len(e.indices))
+}
+
+func (e *rejectionError) Unwrap() []error {
+	return []error{ErrRejected, errPermanent}
+}
+
+// entries returns the rejected items of the batch, ignoring out-of-range and
+// repeated indices.
+func (e *rejectionError) entries(items []LogEntry) []LogEntry {
+	seen := make(map[int]bool, len(e.indices))
+	var rejected []LogEntry
+	for _, i := range e.indices {
+		if i < 0 || i >= len(items) || seen[i] {
+			continue
+		}
+		seen[i] = true
+		rejected = append(rejected, items[i])
+	}
+	return rejected
+}
+
+// parseRejected returns the indices in a {"rejected":[...]} response body,
+// or nil if body is not one.
+func parseRejected(body io.Reader) []int {
+	var response struct {
+		Rejected []int `json:"rejected"`
+	}
+	if err := json.NewDecoder(io.LimitReader(body, maxRejectionBody)).Decode(&response); err != nil {
+		return nil
+	}
+	return response.Rejected
+}
+
+// reportExportError passes a delivery failure to OnExportError, if set.
+func (c *Config) reportExportError(signal string, count int, err error) {
+	if c.OnExportError != nil {
+		c.OnExportError(signal, count, err)
+	}
+}
+
+// newHTTPClient returns the client the default exporters send with:
+// Config.HTTPClient if set, otherwise a client using Config.TLSConfig.
+func newHTTPClient(config *Config) *http.Client {
+	if config.HTTPClient != nil {
+		return config.HTTPClient
+	}
+	client := &http.Client{
+		Timeout: 30 * time.Second,
+	}
+	if config.TLSConfig != nil {
+		transport := http.DefaultTransport.(*http.Transport).Clone()
+		transport.TLSClientConfig = config.TLSConfig
+		client.Transport = transport
+	}
+	return client
+}
+
+// exportContext returns the context an Export-triggered flush should use.
+// Unless the config opts out, the flush is detached from the caller's
+// cancellation so that an aborted request doesn't discard a batch that also
+// holds other callers' entries.
+func exportContext(ctx context.Context, config *Config) context.Context {
+	if config.ContinueExportOnCancel {
+		return context.WithoutCancel(ctx)
+	}
+	return ctx
+}
+
+// sleepBackoff waits for backoff with jitter applied per
+// Config.JitterStrategy. It returns ctx.Err() early if ctx is canceled first.
+func sleepBackoff(ctx context.Context, config *Config, backoff time.Duration) error {
+	return sleepDelay(ctx, jitteredBackoff(config, backoff))
+}
+
+// jitteredBackoff returns the delay to actually wait for backoff.
+func jitteredBackoff(config *Config, backoff time.Duration) time.Duration {
+	return backoffDelay(config, backoff, rand.Float64)
+}
+
+// sleepDelay waits for delay, returning ctx.Err() early if ctx is canceled
+// first.
+func sleepDelay(ctx context.Context, delay time.Duration) error {
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// backoffDelay applies Config.JitterStrategy to backoff using random numbers
+// in [0, 1) from rnd, capped at Config.MaxBackoff.
+func backoffDelay(config *Config, backoff time.Duration, rnd func() float64) time.Duration {
+	var delay time.Duration
+	switch config.JitterStrategy {
+	case JitterNone:
+		delay = backoff
+	case JitterEqual:
+		delay = backoff/2 + time.Duration(rnd()*float64(backoff/2))
+	default: // JitterFull
+		delay = backoff + time.Duration(rnd()*float64(backoff))
+	}
+	if config.MaxBackoff > 0 && delay > config.MaxBackoff {
+		delay = config.MaxBackoff
+	}
+	return delay
+}
+
+// nextBackoff doubles backoff for the next retry, capped at Config.MaxBackoff.
+func nextBackoff(config *Config, backoff time.Duration) time.Duration {
+	backoff *= 2
+	if config.MaxBackoff > 0 && backoff > config.MaxBackoff {
+		backoff = config.MaxBackoff
+	}
+	return backoff
+}
+
+// maxPooledBufferBytes keeps unusually large batch buffers out of the pool so
+// one spike doesn't pin that much memory.
+const maxPooledBufferBytes = 4 << 20
+
+var jsonBufferPool = sync.Pool{
+	New: func() any { return new(bytes.Buffer) },
+}
+
+// encodeJSON encodes v into a pooled buffer, without the encoder's trailing
+// newline. Release the buffer with putJSONBuffer once its bytes are no longer
+// needed.
+func encodeJSON(v any) (*bytes.Buffer, error) {
+	buf := jsonBufferPool.Get().(*bytes.Buffer)
+	buf.Reset()
+	if err := json.NewEncoder(buf).Encode(v); err != nil {
+		putJSONBuffer(buf)
+		return nil, err
+	}
+	buf.Truncate(buf.Len() - 1)
+	return buf, nil
+}
+
+func putJSONBuffer(buf *bytes.Buffer) {
+	if buf.Cap() > maxPooledBufferBytes {
+		return
+	}
+	jsonBufferPool.Put(buf)
+}
+
+// splitBatch partitions items into consecutive sub-batches whose approximate
+// size stays under Config.MaxRequestBytes. An item that exceeds the limit on
+// its own is sent in a sub-batch of one, so it can only fail by itself.
+func splitBatch[T interface{ estimatedSize() int }](config *Config, items []T) [][]T {
+	if config.MaxRequestBytes <= 0 {
+		return [][]T{items}
+	}
+	var parts [][]T
+	start, size := 0, 0
+	for i, item := range items {
+		itemSize := item.estimatedSize()
+		if i > start && size+itemSize > config.MaxRequestBytes {
+			parts = append(parts, items[start:i])
+			start, size = i, 0
+		}
+		size += itemSize
+	}
+	return append(parts, items[start:])
+}
+
+// streamBatch reports whether a batch is large enough, by estimate, to be
+// stream-encoded into the request body rather than encoded up front.
+func streamBatch[T interface{ estimatedSize() int }](config *Config, items []T) bool {
+	if config.StreamThresholdBytes <= 0 {
+		return false
+	}
+	size := 0
+	for _, item := range items {
+		size += item.estimatedSize()
+		if size >= config.StreamThresholdBytes {
+			return true
+		}
+	}
+	return false
+}
+
+// streamJSONArray returns a reader producing the JSON encoding of head with
+// the empty array under key replaced by items, encoded one at a time as the
+// reader is consumed. Closing the reader stops the encoding.
+func streamJSONArray[T any](head any, key string, items []T) (io.ReadCloser, error) {
+	buf, err := encodeJSON(head)
+	if err != nil {
+		return nil, err
+	}
+	encoded := append([]byte(nil), buf.Bytes()...)
+	putJSONBuffer(buf)
+
+	marker := []byte(fmt.Sprintf("%q:[]", key))
+	i := bytes.Index(encoded, marker)
+	if i < 0 {
+		return nil, fmt.Errorf("no %q array in request", key)
+	}
+	prefix := encoded[:i+len(marker)-1]
+	suffix := encoded[i+len(marker)-1:]
+
+	pr, pw := io.Pipe()
+	go func() {
+		w := bufio.NewWriter(pw)
+		enc := json.NewEncoder(w)
+		_, err := w.Write(prefix)
+		for n, item := range items {
+			if err != nil {
+				break
+			}
+			if n > 0 {
+				err = w.WriteByte(',')
+			}
+			if err == nil {
+				err = enc.Encode(item)
+			}
+		}
+		if err == nil {
+			_, err = w.Write(suffix)
+		}
+		if err == nil {
+			err = w.Flush()
+		}
+		pw.CloseWithError(err)
+	}()
+	return pr, nil
+}
+
+// retryBudgetExceeded reports whether waiting delay, the jittered backoff,
+// for another attempt would take a send that started at start past
+// Config.MaxRetryElapsed.
+func retryBudgetExceeded(config *Config, start time.Time, delay time.Duration) bool {
+	return config.MaxRetryElapsed > 0 && time.Since(start)+delay > config.MaxRetryElapsed
+}
+
+// entryOverheadBytes approximates the fixed JSON cost of an entry (field names,
+// punctuation, timestamps) when estimating batch sizes.
+const entryOverheadBytes = 64
+
+// batchFull reports whether a batch with count entries totalling roughly
+// bytes should be flushed.
+func batchFull(config *Config, count, bytes int) bool {
+	if count >= config.BatchSize {
+		return true
+	}
+	return config.MaxBatchBytes > 0 && bytes >= config.MaxBatchBytes
+}
+
+// batchStale reports whether the last flush was at least BatchTimeout ago, so
+// an Export should flush now instead of waiting for the next tick.
+func batchStale(config *Config, lastFlush time.Time) bool {
+	return time.Since(lastFlush) >= config.BatchTimeout
+}
+
+// resetFlushTicker restarts ticker after a flush so the next periodic flush
+// comes a full BatchTimeout later, unless the exporter is shutting down.
+func resetFlushTicker(ticker *time.Ticker, config *Config, stopCh <-chan struct{}) {
+	select {
+	case <-stopCh:
+	default:
+		ticker.Reset(config.BatchTimeout)
+	}
+}
+
+// estimateStringMapSize approximates the encoded size of an attribute map.
+func estimateStringMapSize(m map[string]string) int {
+	size := 0
+	for k, v := range m {
+		size += len(k) + len(v) + 6
+	}
+	return size
+}
+
+// estimateValueSize approximates the encoded size of a props value without
+// marshaling it.
+func estimateValueSize(v interface{}) int {
+	switch val := v.(type) {
+	case string:
+		return len(val) + 2
+	case []interface{}:
+		size := 2
+		for _, item := range val {
+			size += estimateValueSize(item) + 1
+		}
+		return size
+	case map[string]interface{}:
+		size := 2
+		for k, item := range val {
+			size += len(k) + estimateValueSize(item) + 4
+		}
+		return size
+	case fmt.Stringer:
+		return len(val.String()) + 2
+	default:
+		return 8
+	}
+}
@@ -0,0 +1,141 @@
+package lumberjack
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/rand"
+	"testing"
+	"time"
+)
+
+func benchmarkLogRequest() LogRequest {
+	entries := make([]LogEntry, 100)
+	for i := range entries {
+		entries[i] = LogEntry{
+			Msg:   fmt.Sprintf("request %d handled", i),
+			Lvl:   "INFO",
+			Ts:    1700000000.5,
+			Props: map[string]interface{}{"route": "/items/{id}", "status": 200},
+			Src:   defaultLogSource,
+		}
+	}
+	return LogRequest{Logs: entries, ProjectName: "bench", SdkVersion: 2}
+}
+
+func TestEncodeJSONMatchesMarshal(t *testing.T) {
+	request := benchmarkLogRequest()
+	want, err := json.Marshal(request)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for i := 0; i < 2; i++ { // the second pass reuses a pooled buffer
+		buf, err := encodeJSON(request)
+		if err != nil {
+			t.Fatalf("encodeJSON() error = %v", err)
+		}
+		if !bytes.Equal(buf.Bytes(), want) {
+			t.Errorf("encodeJSON() = %s, want %s", buf.Bytes(), want)
+		}
+		putJSONBuffer(buf)
+	}
+}
+
+func TestStreamJSONArrayMatchesMarshal(t *testing.T) {
+	request := benchmarkLogRequest()
+	want, err := json.Marshal(request)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	head := request
+	head.Logs = []LogEntry{}
+	r, err := streamJSONArray(head, "logs", request.Logs)
+	if err != nil {
+		t.Fatalf("streamJSONArray() error = %v", err)
+	}
+	got, err := io.ReadAll(r)
+	if err != nil {
+		t.Fatalf("reading stream: %v", err)
+	}
+	var compacted bytes.Buffer
+	if err := json.Compact(&compacted, got); err != nil {
+		t.Fatalf("stream is not valid JSON: %v", err)
+	}
+	if !bytes.Equal(compacted.Bytes(), want) {
+		t.Errorf("streamJSONArray() = %s, want %s", compacted.Bytes(), want)
+	}
+}
+
+func TestStreamJSONArrayMissingKey(t *testing.T) {
+	if _, err := streamJSONArray(LogRequest{}, "spans", []LogEntry{}); err == nil {
+		t.Error("streamJSONArray() error = nil, want error for a missing array")
+	}
+}
+
+func TestBackoffDelay(t *testing.T) {
+	const backoff = 100 * time.Millisecond
+	tests := []struct {
+		strategy JitterStrategy
+		min, max time.Duration // max is exclusive unless min == max
+	}{
+		{JitterFull, backoff, 2 * backoff},
+		{JitterNone, backoff, backoff},
+		{JitterEqual, backoff / 2, backoff},
+	}
+	for _, tt := range tests {
+		t.Run(tt.strategy.String(), func(t *testing.T) {
+			config := testConfig("").WithJitterStrategy(tt.strategy)
+			rnd := rand.New(rand.NewSource(1)).Float64
+			for i := 0; i < 1000; i++ {
+				delay := backoffDelay(config, backoff, rnd)
+				if delay < tt.min || delay > tt.max || (delay == tt.max && tt.min != tt.max) {
+					t.Fatalf("backoffDelay() = %v, want in [%v, %v)", delay, tt.min, tt.max)
+				}
+			}
+		})
+	}
+}
+
+func TestBackoffDelayMaxBackoff(t *testing.T) {
+	config := testConfig("").WithMaxBackoff(time.Second)
+	rnd := rand.New(rand.NewSource(1)).Float64
+
+	backoff := 100 * time.Millisecond
+	for i := 0; i < 10; i++ {
+		backoff = nextBackoff(config, backoff)
+	}
+	if backoff != time.Second {
+		t.Errorf("backoff after 10 doublings = %v, want the 1s MaxBackoff", backoff)
+	}
+	for i := 0; i < 1000; i++ {
+		if delay := backoffDelay(config, backoff, rnd); delay > time.Second {
+			t.Fatalf("backoffDelay() = %v, want at most the 1s MaxBackoff", delay)
+		}
+	}
+}
+
+func BenchmarkEncodeLogBatch(b *testing.B) {
+	request := benchmarkLogRequest()
+
+	b.Run("Marshal", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			if _, err := json.Marshal(request); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+	b.Run("Pooled", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			buf, err := encodeJSON(request)
+			if err != nil {
+				b.Fatal(err)
+			}
+			putJSONBuffer(buf)
+		}
+	})
+}
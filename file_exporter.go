@@ -0,0 +1,188 @@
+package lumberjack
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+
+	sdklog "go.opentelemetry.io/otel/sdk/log"
+)
+
+// errFileExporterClosed is returned by Export after Shutdown.
+var errFileExporterClosed = errors.New("file logs exporter is shut down")
+
+// FileLogsExporter is a LogsExporter that appends each log entry to a local
+// file as a line of JSON (NDJSON), for environments where a sidecar ships
+// the file instead of the SDK sending over HTTP. Entries are converted as
+// for the default exporter, so redaction, truncation and the like apply. It
+// is safe for concurrent use; each Export writes whole lines to the file,
+// and ForceFlush and Shutdown also sync it to disk.
+//
+//	exporter, err := lumberjack.NewFileLogsExporter(config, "/var/log/app/lumberjack.ndjson")
+//	config.WithCustomLogsExporter(exporter)
+type FileLogsExporter struct {
+	logConverter
+	path       string
+	maxBytes   int64
+	maxBackups int
+
+	mu     sync.Mutex
+	file   *os.File
+	w      *bufio.Writer
+	size   int64
+	closed bool
+}
+
+// NewFileLogsExporter opens, or creates, the file at path for appending,
+// rotating it as set by config.MaxFileBytes and config.MaxBackups.
+func NewFileLogsExporter(config *Config, path string) (*FileLogsExporter, error) {
+	e := &FileLogsExporter{
+		logConverter: newLogConverter(config),
+		path:         path,
+		maxBytes:     config.MaxFileBytes,
+		maxBackups:   config.MaxBackups,
+	}
+	if err := e.open(); err != nil {
+		return nil, err
+	}
+	return e, nil
+}
+
+func (e *FileLogsExporter) open() error {
+	file, err := os.OpenFile(e.path, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0o644)
+	if err != nil {
+		return err
+	}
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return err
+	}
+	e.file = file
+	e.w = bufio.NewWriter(file)
+	e.size = info.Size()
+	return nil
+}
+
+func (e *FileLogsExporter) Export(ctx context.Context, records []*sdklog.Record) error {
+	lines := make([][]byte, 0, len(records))
+	for _, record := range records {
+		line, err := json.Marshal(e.convertRecordToEntry(record))
+		if err != nil {
+			e.config.debugf("Failed to marshal log entry: %v\n", err)
+			continue
+		}
+		lines = append(lines, append(line, '\n'))
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.closed {
+		return errFileExporterClosed
+	}
+	if e.file == nil {
+		// An earlier rotation failed to reopen the file
+		if err := e.open(); err != nil {
+			return err
+		}
+	}
+	for _, line := range lines {
+		if e.maxBytes > 0 && e.size > 0 && e.size+int64(len(line)) > e.maxBytes {
+			if err := e.rotateLocked(); err != nil {
+				return fmt.Errorf("rotating %s: %w", e.path, err)
+			}
+		}
+		n, err := e.w.Write(line)
+		e.size += int64(n)
+		if err != nil {
+			return err
+		}
+	}
+	// Hand the batch's lines to the file now, so a tailing shipper never
+	// waits on, or sees half of, a buffered line
+	return e.w.Flush()
+}
+
+// rotateLocked closes the current file, shifts the backups up by one,
+// dropping the oldest, and reopens an empty file. If that fails, the current
+// file is reopened for appending, and if that fails too, file is left nil
+// for the next Export to retry. Must be called with mu held.
+func (e *FileLogsExporter) rotateLocked() error {
+	if err := e.syncLocked(); err != nil {
+		return err
+	}
+	err := e.file.Close()
+	e.file = nil
+	if err == nil {
+		err = e.shiftBackups()
+	}
+	if err != nil {
+		return errors.Join(err, e.open())
+	}
+	return e.open()
+}
+
+// shiftBackups moves the current file to the first backup, shifting the
+// others up by one and dropping the oldest, or removes it if no backups are
+// kept.
+func (e *FileLogsExporter) shiftBackups() error {
+	if e.maxBackups <= 0 {
+		if err := os.Remove(e.path); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+		return nil
+	}
+	if err := os.Remove(e.backupPath(e.maxBackups)); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	for i := e.maxBackups - 1; i >= 1; i-- {
+		if err := os.Rename(e.backupPath(i), e.backupPath(i+1)); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+	}
+	return os.Rename(e.path, e.backupPath(1))
+}
+
+func (e *FileLogsExporter) backupPath(i int) string {
+	return fmt.Sprintf("%s.%d", e.path, i)
+}
+
+// syncLocked writes buffered lines to the file and fsyncs it. Must be called
+// with mu held.
+func (e *FileLogsExporter) syncLocked() error {
+	if e.file == nil {
+		return nil
+	}
+	if err := e.w.Flush(); err != nil {
+		return err
+	}
+	return e.file.Sync()
+}
+
+// ForceFlush syncs written entries to disk.
+func (e *FileLogsExporter) ForceFlush(ctx context.Context) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.closed {
+		return nil
+	}
+	return e.syncLocked()
+}
+
+// Shutdown syncs written entries to disk and closes the file.
+func (e *FileLogsExporter) Shutdown(ctx context.Context) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.closed {
+		return nil
+	}
+	e.closed = true
+	if e.file == nil {
+		return nil
+	}
+	return errors.Join(e.syncLocked(), e.file.Close())
+}
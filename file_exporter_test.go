@@ -0,0 +1,176 @@
+package lumberjack
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"go.opentelemetry.io/otel/log"
+	sdklog "go.opentelemetry.io/otel/sdk/log"
+)
+
+// readNDJSON decodes every line of the file at path as a LogEntry.
+func readNDJSON(t *testing.T, path string) []LogEntry {
+	t.Helper()
+	file, err := os.Open(path)
+	if err != nil {
+		t.Fatalf("opening %s: %v", path, err)
+	}
+	defer file.Close()
+
+	var entries []LogEntry
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		var entry LogEntry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			t.Fatalf("%s: invalid NDJSON line %q: %v", filepath.Base(path), scanner.Text(), err)
+		}
+		entries = append(entries, entry)
+	}
+	if err := scanner.Err(); err != nil {
+		t.Fatalf("reading %s: %v", path, err)
+	}
+	return entries
+}
+
+func TestFileLogsExporterRotation(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "logs.ndjson")
+	exporter, err := NewFileLogsExporter(testConfig("").WithFileRotation(1024, 2), path)
+	if err != nil {
+		t.Fatalf("NewFileLogsExporter: %v", err)
+	}
+
+	for i := 0; i < 50; i++ {
+		record := newTestRecord(fmt.Sprintf("entry %d", i), log.SeverityInfo)
+		if err := exporter.Export(context.Background(), []*sdklog.Record{record}); err != nil {
+			t.Fatalf("Export: %v", err)
+		}
+	}
+	if err := exporter.Shutdown(context.Background()); err != nil {
+		t.Fatalf("Shutdown: %v", err)
+	}
+
+	for _, name := range []string{path, path + ".1", path + ".2"} {
+		info, err := os.Stat(name)
+		if err != nil {
+			t.Fatalf("stat: %v", err)
+		}
+		if info.Size() > 1024 {
+			t.Errorf("%s is %d bytes, want at most 1024", filepath.Base(name), info.Size())
+		}
+		if len(readNDJSON(t, name)) == 0 {
+			t.Errorf("%s has no entries", filepath.Base(name))
+		}
+	}
+	if _, err := os.Stat(path + ".3"); !os.IsNotExist(err) {
+		t.Errorf("found a third backup, want at most 2: %v", err)
+	}
+
+	current := readNDJSON(t, path)
+	if last := current[len(current)-1].Msg; last != "entry 49" {
+		t.Errorf("last entry in the current file = %q, want entry 49", last)
+	}
+	backup := readNDJSON(t, path+".1")
+	if next := backup[len(backup)-1].Msg; next != fmt.Sprintf("entry %d", 49-len(current)) {
+		t.Errorf("last entry in the newest backup = %q, want the one before the current file's first", next)
+	}
+
+	if err := exporter.Export(context.Background(), []*sdklog.Record{newTestRecord("late", log.SeverityInfo)}); err == nil {
+		t.Error("Export after Shutdown succeeded, want an error")
+	}
+}
+
+func TestFileLogsExporterRecoversFromFailedRotation(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "logs.ndjson")
+	exporter, err := NewFileLogsExporter(testConfig("").WithFileRotation(256, 1), path)
+	if err != nil {
+		t.Fatalf("NewFileLogsExporter: %v", err)
+	}
+	defer exporter.Shutdown(context.Background())
+
+	// A non-empty directory where the backup goes makes rotation fail
+	if err := os.MkdirAll(filepath.Join(path+".1", "blocker"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	export := func(msg string) error {
+		return exporter.Export(context.Background(), []*sdklog.Record{newTestRecord(msg, log.SeverityInfo)})
+	}
+
+	var rotateErr error
+	for i := 0; i < 10 && rotateErr == nil; i++ {
+		rotateErr = export(fmt.Sprintf("entry %d", i))
+	}
+	if rotateErr == nil {
+		t.Fatal("Export never tried to rotate")
+	}
+
+	if err := os.RemoveAll(path + ".1"); err != nil {
+		t.Fatal(err)
+	}
+	if err := export("after failure"); err != nil {
+		t.Fatalf("Export after a failed rotation: %v", err)
+	}
+	if err := exporter.ForceFlush(context.Background()); err != nil {
+		t.Fatalf("ForceFlush: %v", err)
+	}
+
+	current := readNDJSON(t, path)
+	if len(current) == 0 || current[len(current)-1].Msg != "after failure" {
+		t.Errorf("current file = %+v, want it to end with the entry written after the failure", current)
+	}
+	if len(readNDJSON(t, path+".1")) == 0 {
+		t.Error("backup is empty, want the entries written before the failure")
+	}
+}
+
+func TestFileLogsExporterConcurrentWrites(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "logs.ndjson")
+	exporter, err := NewFileLogsExporter(testConfig(""), path)
+	if err != nil {
+		t.Fatalf("NewFileLogsExporter: %v", err)
+	}
+
+	var wg sync.WaitGroup
+	for g := 0; g < 8; g++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 50; i++ {
+				exporter.Export(context.Background(), []*sdklog.Record{newTestRecord("concurrent", log.SeverityInfo)})
+			}
+		}()
+	}
+	wg.Wait()
+	if err := exporter.ForceFlush(context.Background()); err != nil {
+		t.Fatalf("ForceFlush: %v", err)
+	}
+
+	if got := len(readNDJSON(t, path)); got != 400 {
+		t.Errorf("file has %d entries after ForceFlush, want 400", got)
+	}
+	exporter.Shutdown(context.Background())
+}
+
+func TestFileLogsExporterWritesLinesOnExport(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "logs.ndjson")
+	exporter, err := NewFileLogsExporter(testConfig(""), path)
+	if err != nil {
+		t.Fatalf("NewFileLogsExporter: %v", err)
+	}
+	defer exporter.Shutdown(context.Background())
+
+	record := newTestRecord("visible before flush", log.SeverityInfo)
+	if err := exporter.Export(context.Background(), []*sdklog.Record{record}); err != nil {
+		t.Fatalf("Export: %v", err)
+	}
+
+	entries := readNDJSON(t, path)
+	if len(entries) != 1 || entries[0].Msg != "visible before flush" {
+		t.Errorf("file holds %+v after Export, want the exported entry", entries)
+	}
+}
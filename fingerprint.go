@@ -0,0 +1,60 @@
+package lumberjack
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"regexp"
+	"strings"
+)
+
+var (
+	uuidPattern   = regexp.MustCompile(`(?i)\b[0-9a-f]{8}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{12}\b`)
+	hexIDPattern  = regexp.MustCompile(`(?i)\b(?:0x)?[0-9a-f]{8,}\b`)
+	numberPattern = regexp.MustCompile(`\d+(?:\.\d+)?`)
+)
+
+// MessageTemplate strips the variable parts of a log message so that messages
+// differing only by ids or counts share a template, e.g. "user 42 not found"
+// and "user 97 not found" both become "user <n> not found". UUIDs are
+// replaced with "<uuid>", hex ids with "<hex>" and numbers with "<n>".
+func MessageTemplate(msg string) string {
+	msg = uuidPattern.ReplaceAllString(msg, "<uuid>")
+	msg = hexIDPattern.ReplaceAllStringFunc(msg, func(s string) string {
+		// Require both digits and letters so plain numbers and words survive
+		if !strings.ContainsAny(s, "0123456789") || !strings.ContainsAny(strings.ToLower(s), "abcdef") {
+			return s
+		}
+		return "<hex>"
+	})
+	return numberPattern.ReplaceAllString(msg, "<n>")
+}
+
+// DefaultFingerprint hashes the entry's message template, level and top stack
+// frame into a stable grouping key. The frame is identified by file and
+// function rather than line, so unrelated edits don't split a group.
+func DefaultFingerprint(entry LogEntry) string {
+	h := sha256.New()
+	for _, part := range []string{MessageTemplate(entry.Msg), entry.Lvl, entry.Fl, entry.Fn} {
+		h.Write([]byte(part))
+		h.Write([]byte{0})
+	}
+	return hex.EncodeToString(h.Sum(nil)[:16])
+}
+
+// fingerprinter returns the fingerprint function configured on config, or nil
+// when fingerprinting is disabled.
+func fingerprinter(config *Config) func(LogEntry) string {
+	if !config.Fingerprint {
+		return nil
+	}
+	if config.Fingerprinter != nil {
+		return config.Fingerprinter
+	}
+	return DefaultFingerprint
+}
+
+// isErrorLevel reports whether lvl is one of the levels fingerprints are
+// attached to.
+func isErrorLevel(lvl string) bool {
+	return lvl == "ERROR" || lvl == "FATAL"
+}
@@ -0,0 +1,77 @@
+package lumberjack
+
+import (
+	"context"
+	"testing"
+)
+
+func TestMessageTemplate(t *testing.T) {
+	tests := []struct {
+		msg  string
+		want string
+	}{
+		{"user 42 not found", "user <n> not found"},
+		{"order 3fa85f64-5717-4562-b3fc-2c963f66afa6 failed", "order <uuid> failed"},
+		{"object 5f1d7a9c3b2e not found", "object <hex> not found"},
+		{"took 12.5ms", "took <n>ms"},
+		{"database unreachable", "database unreachable"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.msg, func(t *testing.T) {
+			if got := MessageTemplate(tt.msg); got != tt.want {
+				t.Errorf("MessageTemplate(%q) = %q, want %q", tt.msg, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFingerprintGroupsByTemplate(t *testing.T) {
+	server := newCaptureServer(t)
+	sdk := newSDK(testConfig(server.URL).WithFingerprint(true))
+
+	sdk.Logger().Error("payment 1001 declined")
+	sdk.Logger().Error("payment 2002 declined")
+	sdk.Logger().Error("refund 1001 declined")
+	sdk.Logger().Info("payment 1001 declined")
+	sdk.Shutdown(context.Background())
+
+	var logs []LogEntry
+	for _, req := range server.decodeLogRequests(t) {
+		logs = append(logs, req.Logs...)
+	}
+	if len(logs) != 4 {
+		t.Fatalf("expected 4 exported logs, got %d", len(logs))
+	}
+
+	if logs[0].Fp == "" {
+		t.Fatalf("error entry has no fingerprint")
+	}
+	if logs[0].Fp != logs[1].Fp {
+		t.Errorf("messages differing only by id have fingerprints %q and %q", logs[0].Fp, logs[1].Fp)
+	}
+	if logs[0].Fp == logs[2].Fp {
+		t.Errorf("different messages share fingerprint %q", logs[0].Fp)
+	}
+	if logs[3].Fp != "" {
+		t.Errorf("info entry has fingerprint %q, want none", logs[3].Fp)
+	}
+}
+
+func TestCustomFingerprinter(t *testing.T) {
+	server := newCaptureServer(t)
+	sdk := newSDK(testConfig(server.URL).WithFingerprinter(func(entry LogEntry) string {
+		return "custom:" + entry.Lvl
+	}))
+
+	sdk.Logger().Error("boom")
+	sdk.Shutdown(context.Background())
+
+	requests := server.decodeLogRequests(t)
+	if len(requests) != 1 || len(requests[0].Logs) != 1 {
+		t.Fatalf("expected a single exported log, got %+v", requests)
+	}
+	if got := requests[0].Logs[0].Fp; got != "custom:ERROR" {
+		t.Errorf("fingerprint = %q, want %q", got, "custom:ERROR")
+	}
+}
@@ -0,0 +1,228 @@
+// Package grpc provides gRPC client and server interceptors that trace calls
+// with the Lumberjack SDK and propagate W3C trace context and baggage through
+// gRPC metadata. It lives in its own package so the core SDK carries no gRPC
+// dependency.
+package grpc
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	lumberjack "github.com/TreebeardHQ/go-sdk"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+	gogrpc "google.golang.org/grpc"
+	grpccodes "google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+const instrumentationName = "github.com/TreebeardHQ/go-sdk/grpc"
+
+// Option configures the interceptors.
+type Option func(*options)
+
+type options struct {
+	tracerProvider trace.TracerProvider
+	propagator     propagation.TextMapPropagator
+	logger         *lumberjack.Logger
+}
+
+// WithTracerProvider sets the tracer provider used to start spans. Defaults to
+// the global provider installed by lumberjack.Init.
+func WithTracerProvider(tp trace.TracerProvider) Option {
+	return func(o *options) {
+		o.tracerProvider = tp
+	}
+}
+
+// WithPropagator sets the propagator used to read and write trace context in
+// gRPC metadata. Defaults to W3C trace context plus W3C baggage.
+func WithPropagator(p propagation.TextMapPropagator) Option {
+	return func(o *options) {
+		o.propagator = p
+	}
+}
+
+// WithLogger logs one line per completed call to logger, at ERROR for failed
+// calls and INFO otherwise.
+func WithLogger(logger *lumberjack.Logger) Option {
+	return func(o *options) {
+		o.logger = logger
+	}
+}
+
+func newOptions(opts []Option) *options {
+	o := &options{}
+	for _, opt := range opts {
+		opt(o)
+	}
+	if o.tracerProvider == nil {
+		o.tracerProvider = otel.GetTracerProvider()
+	}
+	if o.propagator == nil {
+		o.propagator = propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{})
+	}
+	return o
+}
+
+func (o *options) tracer() trace.Tracer {
+	return o.tracerProvider.Tracer(instrumentationName)
+}
+
+// UnaryServerInterceptor returns an interceptor that continues the caller's
+// trace from incoming metadata and runs the handler inside a server span.
+func UnaryServerInterceptor(opts ...Option) gogrpc.UnaryServerInterceptor {
+	o := newOptions(opts)
+	return func(ctx context.Context, req any, info *gogrpc.UnaryServerInfo, handler gogrpc.UnaryHandler) (any, error) {
+		ctx, span := o.startServerSpan(ctx, info.FullMethod)
+		start := time.Now()
+		resp, err := handler(ctx, req)
+		o.finish(ctx, span, info.FullMethod, start, err)
+		return resp, err
+	}
+}
+
+// StreamServerInterceptor is the streaming counterpart of UnaryServerInterceptor.
+func StreamServerInterceptor(opts ...Option) gogrpc.StreamServerInterceptor {
+	o := newOptions(opts)
+	return func(srv any, ss gogrpc.ServerStream, info *gogrpc.StreamServerInfo, handler gogrpc.StreamHandler) error {
+		ctx, span := o.startServerSpan(ss.Context(), info.FullMethod)
+		start := time.Now()
+		err := handler(srv, &serverStream{ServerStream: ss, ctx: ctx})
+		o.finish(ctx, span, info.FullMethod, start, err)
+		return err
+	}
+}
+
+// UnaryClientInterceptor returns an interceptor that runs each call inside a
+// client span and injects its trace context into outgoing metadata.
+func UnaryClientInterceptor(opts ...Option) gogrpc.UnaryClientInterceptor {
+	o := newOptions(opts)
+	return func(ctx context.Context, method string, req, reply any, cc *gogrpc.ClientConn, invoker gogrpc.UnaryInvoker, callOpts ...gogrpc.CallOption) error {
+		ctx, span := o.startClientSpan(ctx, method)
+		start := time.Now()
+		err := invoker(ctx, method, req, reply, cc, callOpts...)
+		o.finish(ctx, span, method, start, err)
+		return err
+	}
+}
+
+// StreamClientInterceptor is the streaming counterpart of UnaryClientInterceptor.
+// The span ends when the stream is established; it does not cover the
+// lifetime of the stream.
+func StreamClientInterceptor(opts ...Option) gogrpc.StreamClientInterceptor {
+	o := newOptions(opts)
+	return func(ctx context.Context, desc *gogrpc.StreamDesc, cc *gogrpc.ClientConn, method string, streamer gogrpc.Streamer, callOpts ...gogrpc.CallOption) (gogrpc.ClientStream, error) {
+		ctx, span := o.startClientSpan(ctx, method)
+		start := time.Now()
+		cs, err := streamer(ctx, desc, cc, method, callOpts...)
+		o.finish(ctx, span, method, start, err)
+		return cs, err
+	}
+}
+
+func (o *options) startServerSpan(ctx context.Context, method string) (context.Context, trace.Span) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	ctx = o.propagator.Extract(ctx, metadataCarrier(md))
+	return o.tracer().Start(ctx, method,
+		trace.WithSpanKind(trace.SpanKindServer),
+		trace.WithAttributes(rpcAttributes(method)...),
+	)
+}
+
+func (o *options) startClientSpan(ctx context.Context, method string) (context.Context, trace.Span) {
+	ctx, span := o.tracer().Start(ctx, method,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(rpcAttributes(method)...),
+	)
+
+	md, ok := metadata.FromOutgoingContext(ctx)
+	if ok {
+		md = md.Copy()
+	} else {
+		md = metadata.MD{}
+	}
+	o.propagator.Inject(ctx, metadataCarrier(md))
+	return metadata.NewOutgoingContext(ctx, md), span
+}
+
+// finish records the gRPC status on span, ends it and optionally logs the call.
+func (o *options) finish(ctx context.Context, span trace.Span, method string, start time.Time, err error) {
+	st, _ := status.FromError(err)
+	span.SetAttributes(attribute.Int("rpc.grpc.status_code", int(st.Code())))
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, st.Message())
+	}
+	span.End()
+
+	if o.logger == nil {
+		return
+	}
+	level := slog.LevelInfo
+	if st.Code() != grpccodes.OK {
+		level = slog.LevelError
+	}
+	o.logger.Log(ctx, level, "gRPC call completed",
+		"rpc.method", method,
+		"rpc.grpc.status_code", int(st.Code()),
+		"duration_ms", time.Since(start).Milliseconds(),
+	)
+}
+
+// rpcAttributes splits a full method name ("/pkg.Service/Method") into the
+// standard rpc.service and rpc.method attributes.
+func rpcAttributes(fullMethod string) []attribute.KeyValue {
+	attrs := []attribute.KeyValue{attribute.String("rpc.system", "grpc")}
+	if len(fullMethod) > 0 && fullMethod[0] == '/' {
+		fullMethod = fullMethod[1:]
+	}
+	for i := len(fullMethod) - 1; i >= 0; i-- {
+		if fullMethod[i] == '/' {
+			attrs = append(attrs,
+				attribute.String("rpc.service", fullMethod[:i]),
+				attribute.String("rpc.method", fullMethod[i+1:]),
+			)
+			break
+		}
+	}
+	return attrs
+}
+
+// metadataCarrier adapts gRPC metadata to a propagation.TextMapCarrier.
+type metadataCarrier metadata.MD
+
+func (c metadataCarrier) Get(key string) string {
+	values := metadata.MD(c).Get(key)
+	if len(values) == 0 {
+		return ""
+	}
+	return values[0]
+}
+
+func (c metadataCarrier) Set(key, value string) {
+	metadata.MD(c).Set(key, value)
+}
+
+func (c metadataCarrier) Keys() []string {
+	keys := make([]string, 0, len(c))
+	for k := range c {
+		keys = append(keys, k)
+	}
+	return keys
+}
+
+// serverStream overrides Context so handlers see the span context.
+type serverStream struct {
+	gogrpc.ServerStream
+	ctx context.Context
+}
+
+func (s *serverStream) Context() context.Context {
+	return s.ctx
+}
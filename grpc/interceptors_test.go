@@ -0,0 +1,265 @@
+package grpc
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	lumberjack "github.com/TreebeardHQ/go-sdk"
+	"go.opentelemetry.io/otel/baggage"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+	gogrpc "google.golang.org/grpc"
+	grpccodes "google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+// startHealthServer runs an in-process gRPC health server with the
+// interceptors installed and returns a client wired with the client
+// interceptors.
+func startHealthServer(t *testing.T, tp trace.TracerProvider) (healthpb.HealthClient, *health.Server) {
+	t.Helper()
+	listener := bufconn.Listen(1 << 20)
+
+	server := gogrpc.NewServer(
+		gogrpc.UnaryInterceptor(UnaryServerInterceptor(WithTracerProvider(tp))),
+		gogrpc.StreamInterceptor(StreamServerInterceptor(WithTracerProvider(tp))),
+	)
+	healthServer := health.NewServer()
+	healthpb.RegisterHealthServer(server, healthServer)
+	go server.Serve(listener)
+	t.Cleanup(server.Stop)
+
+	conn, err := gogrpc.NewClient("passthrough:///bufnet",
+		gogrpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return listener.DialContext(ctx)
+		}),
+		gogrpc.WithTransportCredentials(insecure.NewCredentials()),
+		gogrpc.WithUnaryInterceptor(UnaryClientInterceptor(WithTracerProvider(tp))),
+		gogrpc.WithStreamInterceptor(StreamClientInterceptor(WithTracerProvider(tp))),
+	)
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+
+	return healthpb.NewHealthClient(conn), healthServer
+}
+
+func spanByKind(spans tracetest.SpanStubs, kind trace.SpanKind) *tracetest.SpanStub {
+	for i := range spans {
+		if spans[i].SpanKind == kind {
+			return &spans[i]
+		}
+	}
+	return nil
+}
+
+func TestUnaryInterceptorsLinkSpans(t *testing.T) {
+	exporter := tracetest.NewInMemoryExporter()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+	defer tp.Shutdown(context.Background())
+
+	client, _ := startHealthServer(t, tp)
+
+	if _, err := client.Check(context.Background(), &healthpb.HealthCheckRequest{}); err != nil {
+		t.Fatalf("Check() unexpected error = %v", err)
+	}
+
+	spans := exporter.GetSpans()
+	clientSpan := spanByKind(spans, trace.SpanKindClient)
+	serverSpan := spanByKind(spans, trace.SpanKindServer)
+	if clientSpan == nil || serverSpan == nil {
+		t.Fatalf("expected a client and a server span, got %d spans", len(spans))
+	}
+
+	if serverSpan.SpanContext.TraceID() != clientSpan.SpanContext.TraceID() {
+		t.Errorf("server trace ID = %v, want client trace ID %v", serverSpan.SpanContext.TraceID(), clientSpan.SpanContext.TraceID())
+	}
+	if serverSpan.Parent.SpanID() != clientSpan.SpanContext.SpanID() {
+		t.Errorf("server parent span ID = %v, want client span ID %v", serverSpan.Parent.SpanID(), clientSpan.SpanContext.SpanID())
+	}
+	if !serverSpan.Parent.IsRemote() {
+		t.Errorf("server span parent should be remote")
+	}
+	if serverSpan.Name != "/grpc.health.v1.Health/Check" {
+		t.Errorf("server span name = %q", serverSpan.Name)
+	}
+}
+
+func TestUnaryInterceptorRecordsErrorStatus(t *testing.T) {
+	exporter := tracetest.NewInMemoryExporter()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+	defer tp.Shutdown(context.Background())
+
+	client, _ := startHealthServer(t, tp)
+
+	_, err := client.Check(context.Background(), &healthpb.HealthCheckRequest{Service: "unknown"})
+	if status.Code(err) != grpccodes.NotFound {
+		t.Fatalf("Check() error = %v, want NotFound", err)
+	}
+
+	serverSpan := spanByKind(exporter.GetSpans(), trace.SpanKindServer)
+	if serverSpan == nil {
+		t.Fatalf("expected a server span")
+	}
+	if serverSpan.Status.Code.String() != "Error" {
+		t.Errorf("server span status = %v, want Error", serverSpan.Status.Code)
+	}
+	var gotCode int64 = -1
+	for _, attr := range serverSpan.Attributes {
+		if attr.Key == "rpc.grpc.status_code" {
+			gotCode = attr.Value.AsInt64()
+		}
+	}
+	if gotCode != int64(grpccodes.NotFound) {
+		t.Errorf("rpc.grpc.status_code = %d, want %d", gotCode, grpccodes.NotFound)
+	}
+}
+
+func TestStatusCodeExportedAndLogged(t *testing.T) {
+	received := make(chan lumberjack.SpanBatchRequest, 10)
+	backend := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req lumberjack.SpanBatchRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Errorf("decoding span batch: %v", err)
+		}
+		received <- req
+	}))
+	defer backend.Close()
+
+	config := lumberjack.NewConfig().WithAPIKey("test-key").WithBaseURL(backend.URL).WithReplaceSlog(false)
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSyncer(lumberjack.NewSpanExporter(config)))
+	var logs bytes.Buffer
+	logger := lumberjack.NewLogger(slog.NewJSONHandler(&logs, nil))
+
+	listener := bufconn.Listen(1 << 20)
+	server := gogrpc.NewServer(gogrpc.UnaryInterceptor(UnaryServerInterceptor(WithTracerProvider(tp), WithLogger(logger))))
+	healthpb.RegisterHealthServer(server, health.NewServer())
+	go server.Serve(listener)
+	defer server.Stop()
+	conn, err := gogrpc.NewClient("passthrough:///bufnet",
+		gogrpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return listener.DialContext(ctx)
+		}),
+		gogrpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+	defer conn.Close()
+
+	_, err = healthpb.NewHealthClient(conn).Check(context.Background(), &healthpb.HealthCheckRequest{Service: "unknown"})
+	if status.Code(err) != grpccodes.NotFound {
+		t.Fatalf("Check() error = %v, want NotFound", err)
+	}
+	tp.Shutdown(context.Background())
+
+	want := strconv.Itoa(int(grpccodes.NotFound))
+	select {
+	case req := <-received:
+		if len(req.Payload.Spans) != 1 {
+			t.Fatalf("exported %d spans, want the server span", len(req.Payload.Spans))
+		}
+		if got := req.Payload.Spans[0].Attributes["rpc.grpc.status_code"]; got != want {
+			t.Errorf("exported rpc.grpc.status_code = %q, want %q", got, want)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("no span batch was exported")
+	}
+
+	var line map[string]any
+	if err := json.Unmarshal(logs.Bytes(), &line); err != nil {
+		t.Fatalf("decoding log line %q: %v", logs.String(), err)
+	}
+	if got := line["rpc.grpc.status_code"]; got != float64(grpccodes.NotFound) {
+		t.Errorf("logged rpc.grpc.status_code = %v, want %d as on the span", got, grpccodes.NotFound)
+	}
+}
+
+func TestStreamInterceptorsLinkSpans(t *testing.T) {
+	exporter := tracetest.NewInMemoryExporter()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+	defer tp.Shutdown(context.Background())
+
+	client, _ := startHealthServer(t, tp)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	stream, err := client.Watch(ctx, &healthpb.HealthCheckRequest{})
+	if err != nil {
+		t.Fatalf("Watch() unexpected error = %v", err)
+	}
+	if _, err := stream.Recv(); err != nil {
+		t.Fatalf("Recv() unexpected error = %v", err)
+	}
+	cancel()
+
+	// The server span ends once the handler observes the cancellation
+	var serverSpan *tracetest.SpanStub
+	for i := 0; i < 100 && serverSpan == nil; i++ {
+		serverSpan = spanByKind(exporter.GetSpans(), trace.SpanKindServer)
+		if serverSpan == nil {
+			time.Sleep(10 * time.Millisecond)
+		}
+	}
+	clientSpan := spanByKind(exporter.GetSpans(), trace.SpanKindClient)
+	if clientSpan == nil || serverSpan == nil {
+		t.Fatalf("expected a client and a server span")
+	}
+	if serverSpan.Parent.SpanID() != clientSpan.SpanContext.SpanID() {
+		t.Errorf("server parent span ID = %v, want client span ID %v", serverSpan.Parent.SpanID(), clientSpan.SpanContext.SpanID())
+	}
+}
+
+func TestUnaryInterceptorsPropagateBaggage(t *testing.T) {
+	tp := sdktrace.NewTracerProvider()
+	defer tp.Shutdown(context.Background())
+
+	// Capture the baggage the handler would see, after the server interceptor ran
+	var tenant string
+	capture := func(ctx context.Context, req any, info *gogrpc.UnaryServerInfo, handler gogrpc.UnaryHandler) (any, error) {
+		tenant = baggage.FromContext(ctx).Member("tenant").Value()
+		return handler(ctx, req)
+	}
+
+	listener := bufconn.Listen(1 << 20)
+	server := gogrpc.NewServer(gogrpc.ChainUnaryInterceptor(UnaryServerInterceptor(WithTracerProvider(tp)), capture))
+	healthpb.RegisterHealthServer(server, health.NewServer())
+	go server.Serve(listener)
+	defer server.Stop()
+
+	conn, err := gogrpc.NewClient("passthrough:///bufnet",
+		gogrpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return listener.DialContext(ctx)
+		}),
+		gogrpc.WithTransportCredentials(insecure.NewCredentials()),
+		gogrpc.WithUnaryInterceptor(UnaryClientInterceptor(WithTracerProvider(tp))),
+	)
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+	defer conn.Close()
+
+	member, _ := baggage.NewMember("tenant", "acme")
+	b, _ := baggage.New(member)
+	ctx := baggage.ContextWithBaggage(context.Background(), b)
+	if _, err := healthpb.NewHealthClient(conn).Check(ctx, &healthpb.HealthCheckRequest{}); err != nil {
+		t.Fatalf("Check() unexpected error = %v", err)
+	}
+
+	if tenant != "acme" {
+		t.Errorf("server saw tenant baggage %q, want %q", tenant, "acme")
+	}
+}
@@ -0,0 +1,40 @@
+package lumberjack
+
+import (
+	"os"
+
+	"go.opentelemetry.io/otel/attribute"
+	semconv "go.opentelemetry.io/otel/semconv/v1.17.0"
+)
+
+// Downward-API environment variables read by the Kubernetes resource
+// detector unless Config overrides their names.
+const (
+	defaultK8sPodNameEnv   = "POD_NAME"
+	defaultK8sNamespaceEnv = "POD_NAMESPACE"
+	defaultK8sNodeNameEnv  = "NODE_NAME"
+)
+
+// k8sResourceAttributes returns the k8s.pod.name, k8s.namespace.name and
+// k8s.node.name attributes from the environment variables configured for
+// them, skipping any that are unset.
+func k8sResourceAttributes(config *Config) []attribute.KeyValue {
+	var attrs []attribute.KeyValue
+	if value := os.Getenv(envKeyOrDefault(config.K8sPodNameEnv, defaultK8sPodNameEnv)); value != "" {
+		attrs = append(attrs, semconv.K8SPodName(value))
+	}
+	if value := os.Getenv(envKeyOrDefault(config.K8sNamespaceEnv, defaultK8sNamespaceEnv)); value != "" {
+		attrs = append(attrs, semconv.K8SNamespaceName(value))
+	}
+	if value := os.Getenv(envKeyOrDefault(config.K8sNodeNameEnv, defaultK8sNodeNameEnv)); value != "" {
+		attrs = append(attrs, semconv.K8SNodeName(value))
+	}
+	return attrs
+}
+
+func envKeyOrDefault(key, defaultKey string) string {
+	if key != "" {
+		return key
+	}
+	return defaultKey
+}
@@ -0,0 +1,87 @@
+package lumberjack
+
+import (
+	"strings"
+	"unicode"
+)
+
+// SnakeCaseKey converts an attribute key to snake_case, e.g. "userId" and
+// "UserID" both become "user_id". Dots are kept as namespace separators, so
+// "http.statusCode" becomes "http.status_code". Keys that are already
+// snake_case are returned unchanged.
+func SnakeCaseKey(key string) string {
+	runes := []rune(key)
+	var b strings.Builder
+	b.Grow(len(key) + 4)
+
+	for i, r := range runes {
+		switch {
+		case r == '-' || r == ' ':
+			b.WriteByte('_')
+		case unicode.IsUpper(r):
+			if i > 0 && needsUnderscore(runes, i) {
+				b.WriteByte('_')
+			}
+			b.WriteRune(unicode.ToLower(r))
+		default:
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// FlatSnakeCaseKey is SnakeCaseKey for backends that don't accept dotted
+// keys: dots become underscores too, so "http.statusCode" becomes
+// "http_status_code". It is the normalizer used when Config.FlattenKeys is
+// set.
+func FlatSnakeCaseKey(key string) string {
+	return strings.ReplaceAll(SnakeCaseKey(key), ".", "_")
+}
+
+// needsUnderscore reports whether the upper-case rune at i starts a new word:
+// either it follows a lower-case letter or digit ("userId"), or it ends an
+// acronym and is followed by a lower-case letter ("HTTPStatus").
+func needsUnderscore(runes []rune, i int) bool {
+	prev := runes[i-1]
+	if prev == '_' || prev == '.' || prev == '-' || prev == ' ' {
+		return false
+	}
+	if unicode.IsLower(prev) || unicode.IsDigit(prev) {
+		return true
+	}
+	return unicode.IsUpper(prev) && i+1 < len(runes) && unicode.IsLower(runes[i+1])
+}
+
+// keyNormalizer returns the key normalization function configured on config,
+// or nil when normalization is disabled.
+func keyNormalizer(config *Config) func(string) string {
+	if !config.NormalizeKeys {
+		return nil
+	}
+	normalize := config.KeyNormalizer
+	if normalize == nil {
+		if config.FlattenKeys {
+			return FlatSnakeCaseKey
+		}
+		return SnakeCaseKey
+	}
+	if config.FlattenKeys {
+		return func(key string) string {
+			return strings.ReplaceAll(normalize(key), ".", "_")
+		}
+	}
+	return normalize
+}
+
+// normalizeKeys rewrites the keys of m with normalize. When two keys
+// normalize to the same value, one of them wins arbitrarily.
+func normalizeKeys[V any](m map[string]V, normalize func(string) string) map[string]V {
+	if normalize == nil || len(m) == 0 {
+		return m
+	}
+	out := make(map[string]V, len(m))
+	for k, v := range m {
+		out[normalize(k)] = v
+	}
+	return out
+}
@@ -0,0 +1,173 @@
+package lumberjack
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"go.opentelemetry.io/otel/attribute"
+)
+
+func TestSnakeCaseKey(t *testing.T) {
+	tests := []struct {
+		key  string
+		want string
+	}{
+		{"user_id", "user_id"},
+		{"userId", "user_id"},
+		{"UserID", "user_id"},
+		{"userID", "user_id"},
+		{"HTTPStatus", "http_status"},
+		{"http.statusCode", "http.status_code"},
+		{"request-id", "request_id"},
+		{"retry2Count", "retry2_count"},
+		{"already.snake_case", "already.snake_case"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.key, func(t *testing.T) {
+			got := SnakeCaseKey(tt.key)
+			if got != tt.want {
+				t.Errorf("SnakeCaseKey(%q) = %q, want %q", tt.key, got, tt.want)
+			}
+			if again := SnakeCaseKey(got); again != got {
+				t.Errorf("SnakeCaseKey is not idempotent: %q -> %q", got, again)
+			}
+		})
+	}
+}
+
+func TestLogsKeyNormalization(t *testing.T) {
+	tests := []struct {
+		name   string
+		config func(*Config) *Config
+		want   []string
+	}{
+		{
+			name:   "disabled by default",
+			config: func(c *Config) *Config { return c },
+			want:   []string{"userId", "OrderID"},
+		},
+		{
+			name:   "built-in snake_case",
+			config: func(c *Config) *Config { return c.WithNormalizeKeys(true) },
+			want:   []string{"user_id", "order_id"},
+		},
+		{
+			name:   "custom normalizer",
+			config: func(c *Config) *Config { return c.WithKeyNormalizer(strings.ToUpper) },
+			want:   []string{"USERID", "ORDERID"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := newCaptureServer(t)
+			sdk := newSDK(tt.config(testConfig(server.URL)))
+
+			sdk.Logger().Info("order placed", "userId", 42, "OrderID", "A-1")
+			sdk.Shutdown(context.Background())
+
+			requests := server.decodeLogRequests(t)
+			if len(requests) != 1 || len(requests[0].Logs) != 1 {
+				t.Fatalf("expected a single exported log, got %+v", requests)
+			}
+			props := requests[0].Logs[0].Props
+			if len(props) != len(tt.want) {
+				t.Errorf("props = %v, want keys %v", props, tt.want)
+			}
+			for _, key := range tt.want {
+				if _, ok := props[key]; !ok {
+					t.Errorf("props = %v, missing key %q", props, key)
+				}
+			}
+		})
+	}
+}
+
+func TestFlatSnakeCaseKey(t *testing.T) {
+	tests := []struct {
+		key  string
+		want string
+	}{
+		{"user_id", "user_id"},
+		{"userID", "user_id"},
+		{"http.statusCode", "http_status_code"},
+		{"db.Query.rowCount", "db_query_row_count"},
+		{"already_flat_snake", "already_flat_snake"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.key, func(t *testing.T) {
+			got := FlatSnakeCaseKey(tt.key)
+			if got != tt.want {
+				t.Errorf("FlatSnakeCaseKey(%q) = %q, want %q", tt.key, got, tt.want)
+			}
+			if again := FlatSnakeCaseKey(got); again != got {
+				t.Errorf("FlatSnakeCaseKey is not idempotent: %q -> %q", got, again)
+			}
+		})
+	}
+}
+
+func TestLogsFlatKeyNormalization(t *testing.T) {
+	server := newCaptureServer(t)
+	sdk := newSDK(testConfig(server.URL).WithFlattenKeys(true))
+
+	sdk.Logger().Info("request served",
+		"userID", "u-1",
+		"http.statusCode", 200,
+		"order_id", "A-1",
+	)
+	sdk.Shutdown(context.Background())
+
+	requests := server.decodeLogRequests(t)
+	if len(requests) != 1 || len(requests[0].Logs) != 1 {
+		t.Fatalf("expected a single exported log, got %+v", requests)
+	}
+	props := requests[0].Logs[0].Props
+	want := map[string]string{
+		"user_id":          "u-1",
+		"http_status_code": "200",
+		"order_id":         "A-1",
+	}
+	if len(props) != len(want) {
+		t.Errorf("props = %v, want %v", props, want)
+	}
+	for key, value := range want {
+		if props[key] != value {
+			t.Errorf("props[%q] = %v, want %q", key, props[key], value)
+		}
+	}
+}
+
+func TestFlattenKeysWithCustomNormalizer(t *testing.T) {
+	config := NewConfig().WithKeyNormalizer(strings.ToLower).WithFlattenKeys(true)
+	if got := keyNormalizer(config)("HTTP.Status"); got != "http_status" {
+		t.Errorf("normalized key = %q, want %q", got, "http_status")
+	}
+}
+
+func TestSpansFlatKeyNormalization(t *testing.T) {
+	server := newCaptureServer(t)
+	sdk := newSDK(testConfig(server.URL).WithFlattenKeys(true))
+
+	_, span := sdk.StartSpan(context.Background(), "checkout")
+	span.SetAttributes(
+		attribute.Int("http.statusCode", 200),
+		attribute.String("userID", "u-1"),
+		attribute.String("order_id", "A-1"),
+	)
+	span.End()
+	sdk.Shutdown(context.Background())
+
+	spans := exportedSpans(t, server)
+	if len(spans) != 1 {
+		t.Fatalf("got %d spans, want 1", len(spans))
+	}
+	for _, key := range []string{"http_status_code", "user_id", "order_id"} {
+		if _, ok := spans[0].Attributes[key]; !ok {
+			t.Errorf("attributes = %v, missing key %q", spans[0].Attributes, key)
+		}
+	}
+}
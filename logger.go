@@ -2,44 +2,78 @@ package lumberjack
 
 import (
 	"context"
+	"fmt"
+	"io"
 	"log/slog"
+	"os"
 	"runtime"
+	"sync"
 	"time"
 )
 
+// Custom levels beyond slog's built-in ones. The slog bridge maps them to the
+// OpenTelemetry TRACE and FATAL severities, so they export as "TRACE" and
+// "FATAL".
+const (
+	LevelTrace = slog.LevelDebug - 4
+	LevelFatal = slog.LevelError + 4
+)
+
+// malformedArgsOnce limits the malformed-args warning to one per process
+var malformedArgsOnce sync.Once
+
 type Logger struct {
 	handler slog.Handler
 	attrs   []slog.Attr
+	// warnings receives the Logger's own warnings, such as malformed args
+	warnings io.Writer
 }
 
 func NewLogger(handler slog.Handler) *Logger {
 	return &Logger{
-		handler: handler,
+		handler:  handler,
+		warnings: os.Stderr,
 	}
 }
 
 func (l *Logger) With(args ...any) *Logger {
-	attrs := make([]slog.Attr, 0, len(args)/2)
-	for i := 0; i < len(args); i += 2 {
-		if i+1 < len(args) {
-			if key, ok := args[i].(string); ok {
-				attrs = append(attrs, slog.Any(key, args[i+1]))
-			}
-		}
-	}
+	attrs := argsToAttrs(args)
 	return &Logger{
-		handler: l.handler,
-		attrs:   append(l.attrs, attrs...),
+		handler:  l.handler,
+		attrs:    append(l.attrs, attrs...),
+		warnings: l.warnings,
 	}
 }
 
+// argsToAttrs converts key/value pairs and slog.Attr values to attrs using
+// the same rules as slog.Logger.With.
+func argsToAttrs(args []any) []slog.Attr {
+	var r slog.Record
+	r.Add(args...)
+	attrs := make([]slog.Attr, 0, r.NumAttrs())
+	r.Attrs(func(attr slog.Attr) bool {
+		attrs = append(attrs, attr)
+		return true
+	})
+	return attrs
+}
+
 func (l *Logger) WithGroup(name string) *Logger {
 	return &Logger{
-		handler: l.handler.WithGroup(name),
-		attrs:   l.attrs,
+		handler:  l.handler.WithGroup(name),
+		attrs:    l.attrs,
+		warnings: l.warnings,
 	}
 }
 
+func (l *Logger) Trace(msg string, args ...any) {
+	l.log(context.Background(), LevelTrace, msg, args...)
+}
+
+func (l *Logger) TraceContext(ctx context.Context, msg string, args ...any) {
+	l.log(ctx, LevelTrace, msg, args...)
+}
+
 func (l *Logger) Debug(msg string, args ...any) {
 	l.log(context.Background(), slog.LevelDebug, msg, args...)
 }
@@ -72,13 +106,94 @@ func (l *Logger) ErrorContext(ctx context.Context, msg string, args ...any) {
 	l.log(ctx, slog.LevelError, msg, args...)
 }
 
+// Fatal logs at LevelFatal. Unlike log.Fatal it does not exit the process.
+func (l *Logger) Fatal(msg string, args ...any) {
+	l.log(context.Background(), LevelFatal, msg, args...)
+}
+
+func (l *Logger) FatalContext(ctx context.Context, msg string, args ...any) {
+	l.log(ctx, LevelFatal, msg, args...)
+}
+
+// The *Attrs variants take typed attributes instead of key/value pairs, so
+// a mistyped key or a missing value is a compile error rather than a
+// !BADKEY attribute.
+
+func (l *Logger) TraceAttrs(msg string, attrs ...slog.Attr) {
+	l.logAttrsAt(context.Background(), 3, LevelTrace, msg, attrs...)
+}
+
+func (l *Logger) TraceAttrsContext(ctx context.Context, msg string, attrs ...slog.Attr) {
+	l.logAttrsAt(ctx, 3, LevelTrace, msg, attrs...)
+}
+
+func (l *Logger) DebugAttrs(msg string, attrs ...slog.Attr) {
+	l.logAttrsAt(context.Background(), 3, slog.LevelDebug, msg, attrs...)
+}
+
+func (l *Logger) DebugAttrsContext(ctx context.Context, msg string, attrs ...slog.Attr) {
+	l.logAttrsAt(ctx, 3, slog.LevelDebug, msg, attrs...)
+}
+
+func (l *Logger) InfoAttrs(msg string, attrs ...slog.Attr) {
+	l.logAttrsAt(context.Background(), 3, slog.LevelInfo, msg, attrs...)
+}
+
+func (l *Logger) InfoAttrsContext(ctx context.Context, msg string, attrs ...slog.Attr) {
+	l.logAttrsAt(ctx, 3, slog.LevelInfo, msg, attrs...)
+}
+
+func (l *Logger) WarnAttrs(msg string, attrs ...slog.Attr) {
+	l.logAttrsAt(context.Background(), 3, slog.LevelWarn, msg, attrs...)
+}
+
+func (l *Logger) WarnAttrsContext(ctx context.Context, msg string, attrs ...slog.Attr) {
+	l.logAttrsAt(ctx, 3, slog.LevelWarn, msg, attrs...)
+}
+
+func (l *Logger) ErrorAttrs(msg string, attrs ...slog.Attr) {
+	l.logAttrsAt(context.Background(), 3, slog.LevelError, msg, attrs...)
+}
+
+func (l *Logger) ErrorAttrsContext(ctx context.Context, msg string, attrs ...slog.Attr) {
+	l.logAttrsAt(ctx, 3, slog.LevelError, msg, attrs...)
+}
+
+func (l *Logger) FatalAttrs(msg string, attrs ...slog.Attr) {
+	l.logAttrsAt(context.Background(), 3, LevelFatal, msg, attrs...)
+}
+
+func (l *Logger) FatalAttrsContext(ctx context.Context, msg string, attrs ...slog.Attr) {
+	l.logAttrsAt(ctx, 3, LevelFatal, msg, attrs...)
+}
+
+// LogErr logs err at ERROR level under msg and returns err unchanged, so a
+// failure can be logged and propagated in one line:
+//
+//	return logger.LogErr(ctx, "failed to load user", err)
+//
+// A nil err is returned without logging.
+func (l *Logger) LogErr(ctx context.Context, msg string, err error) error {
+	if err == nil {
+		return nil
+	}
+	l.log(ctx, slog.LevelError, msg, "error", err)
+	return err
+}
+
 func (l *Logger) log(ctx context.Context, level slog.Level, msg string, args ...any) {
+	l.logAt(ctx, 4, level, msg, args...)
+}
+
+// logAt is log with an explicit runtime.Callers skip count, so every public
+// entry point records its caller's source location rather than its own.
+func (l *Logger) logAt(ctx context.Context, skip int, level slog.Level, msg string, args ...any) {
 	if !l.handler.Enabled(ctx, level) {
 		return
 	}
 	
 	var pcs [1]uintptr
-	runtime.Callers(3, pcs[:])
+	runtime.Callers(skip, pcs[:])
 	
 	r := slog.NewRecord(time.Now(), level, msg, pcs[0])
 	
@@ -86,26 +201,58 @@ func (l *Logger) log(ctx context.Context, level slog.Level, msg string, args ...
 		r.AddAttrs(attr)
 	}
 	
-	for i := 0; i < len(args); i += 2 {
-		if i+1 < len(args) {
-			if key, ok := args[i].(string); ok {
-				r.AddAttrs(slog.Any(key, args[i+1]))
-			}
-		}
+	if malformedArgs(args) {
+		malformedArgsOnce.Do(func() {
+			fmt.Fprintf(l.warnings, "Warning: lumberjack log %q has an odd number of args or a non-string key; use the *Attrs methods to catch this at compile time\n", msg)
+		})
 	}
+	r.Add(args...)
 	
 	_ = l.handler.Handle(ctx, r)
 }
 
+// malformedArgs reports whether args, as passed to the variadic logging
+// methods, has a key without a value or a key that is neither a string nor
+// a slog.Attr. slog keeps such args under "!BADKEY".
+func malformedArgs(args []any) bool {
+	for i := 0; i < len(args); {
+		switch args[i].(type) {
+		case slog.Attr:
+			i++
+		case string:
+			if i+1 == len(args) {
+				return true
+			}
+			i += 2
+		default:
+			return true
+		}
+	}
+	return false
+}
+
 func (l *Logger) LogAttrs(ctx context.Context, level slog.Level, msg string, attrs ...slog.Attr) {
+	l.logAttrsAt(ctx, 3, level, msg, attrs...)
+}
+
+func (l *Logger) logAttrsAt(ctx context.Context, skip int, level slog.Level, msg string, attrs ...slog.Attr) {
 	if !l.handler.Enabled(ctx, level) {
 		return
 	}
 	
 	var pcs [1]uintptr
-	runtime.Callers(2, pcs[:])
+	runtime.Callers(skip, pcs[:])
 	
-	r := slog.NewRecord(time.Now(), level, msg, pcs[0])
+	l.logAttrsPC(ctx, pcs[0], level, msg, attrs...)
+}
+
+// logAttrsPC logs with an explicit source program counter.
+func (l *Logger) logAttrsPC(ctx context.Context, pc uintptr, level slog.Level, msg string, attrs ...slog.Attr) {
+	if !l.handler.Enabled(ctx, level) {
+		return
+	}
+	
+	r := slog.NewRecord(time.Now(), level, msg, pc)
 	
 	for _, attr := range l.attrs {
 		r.AddAttrs(attr)
@@ -124,3 +271,11 @@ func (l *Logger) Handler() slog.Handler {
 	return l.handler
 }
 
+// discardHandler drops every record. It backs the no-op logger the
+// package-level accessors return before Init.
+type discardHandler struct{}
+
+func (discardHandler) Enabled(context.Context, slog.Level) bool  { return false }
+func (discardHandler) Handle(context.Context, slog.Record) error { return nil }
+func (d discardHandler) WithAttrs([]slog.Attr) slog.Handler      { return d }
+func (d discardHandler) WithGroup(string) slog.Handler           { return d }
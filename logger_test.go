@@ -0,0 +1,310 @@
+package lumberjack
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"runtime"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestLoggerLogErr(t *testing.T) {
+	handler := &recordingHandler{}
+	logger := NewLogger(handler)
+
+	wantErr := errors.New("connection refused")
+	gotErr := logger.LogErr(context.Background(), "failed to connect", wantErr)
+	if gotErr != wantErr {
+		t.Errorf("LogErr() returned %v, want the identical error value", gotErr)
+	}
+
+	records := handler.all()
+	if len(records) != 1 {
+		t.Fatalf("expected 1 log record, got %d", len(records))
+	}
+	if records[0].Level != slog.LevelError {
+		t.Errorf("level = %v, want ERROR", records[0].Level)
+	}
+	if records[0].Message != "failed to connect" {
+		t.Errorf("message = %q, want %q", records[0].Message, "failed to connect")
+	}
+	if got := recordAttrs(records[0])["error"].Any(); got != wantErr {
+		t.Errorf("error attribute = %v, want %v", got, wantErr)
+	}
+}
+
+func TestLoggerLogErrNil(t *testing.T) {
+	handler := &recordingHandler{}
+	logger := NewLogger(handler)
+
+	if err := logger.LogErr(context.Background(), "nothing to see", nil); err != nil {
+		t.Errorf("LogErr(nil) = %v, want nil", err)
+	}
+	if n := len(handler.all()); n != 0 {
+		t.Errorf("LogErr(nil) logged %d records, want 0", n)
+	}
+}
+
+func TestLoggerCustomLevels(t *testing.T) {
+	server := newCaptureServer(t)
+	sdk := newSDK(testConfig(server.URL).WithMinLogLevel(LevelTrace))
+
+	sdk.Logger().Trace("entering handler")
+	sdk.Logger().Fatal("cannot continue")
+	sdk.Shutdown(context.Background())
+
+	levels := make(map[string]string)
+	for _, req := range server.decodeLogRequests(t) {
+		for _, entry := range req.Logs {
+			levels[entry.Msg] = entry.Lvl
+		}
+	}
+	if got := levels["entering handler"]; got != "TRACE" {
+		t.Errorf("Trace() exported level = %q, want TRACE", got)
+	}
+	if got := levels["cannot continue"]; got != "FATAL" {
+		t.Errorf("Fatal() exported level = %q, want FATAL", got)
+	}
+}
+
+func TestLoggerSourceLocation(t *testing.T) {
+	server := newCaptureServer(t)
+	sdk := newSDK(testConfig(server.URL))
+
+	_, file, line, _ := runtime.Caller(0)
+	sdk.Logger().Info("from info")
+	sdk.Logger().LogAttrs(context.Background(), slog.LevelWarn, "from attrs")
+	sdk.Logger().InfoAttrs("from info attrs")
+	sdk.Logger().WarnAttrsContext(context.Background(), "from warn attrs")
+	sdk.Shutdown(context.Background())
+
+	want := map[string]int{"from info": line + 1, "from attrs": line + 2, "from info attrs": line + 3, "from warn attrs": line + 4}
+	var got int
+	for _, req := range server.decodeLogRequests(t) {
+		for _, entry := range req.Logs {
+			wantLine, ok := want[entry.Msg]
+			if !ok {
+				continue
+			}
+			got++
+			if entry.Fl != file || entry.Ln != wantLine {
+				t.Errorf("%q source = %s:%d, want %s:%d", entry.Msg, entry.Fl, entry.Ln, file, wantLine)
+			}
+			if !strings.HasSuffix(entry.Fn, "TestLoggerSourceLocation") {
+				t.Errorf("%q function = %q, want TestLoggerSourceLocation", entry.Msg, entry.Fn)
+			}
+			if _, ok := entry.Props[codeFilePathKey]; ok {
+				t.Errorf("%q props still carry %s: %v", entry.Msg, codeFilePathKey, entry.Props)
+			}
+		}
+	}
+	if got != len(want) {
+		t.Errorf("found %d of %d expected logs", got, len(want))
+	}
+}
+
+func TestLoggerAcceptsAttrArgs(t *testing.T) {
+	handler := &recordingHandler{}
+	logger := NewLogger(handler).With(slog.String("service", "billing"), "region", "eu")
+
+	logger.Info("charge", slog.Int("amount", 42), "currency", "EUR", slog.Group("card", slog.String("brand", "visa")))
+
+	records := handler.all()
+	if len(records) != 1 {
+		t.Fatalf("expected 1 log record, got %d", len(records))
+	}
+	attrs := recordAttrs(records[0])
+	want := map[string]string{
+		"service":  "billing",
+		"region":   "eu",
+		"amount":   "42",
+		"currency": "EUR",
+		"card":     "[brand=visa]",
+	}
+	for key, value := range want {
+		if got := attrs[key].String(); got != value {
+			t.Errorf("attribute %q = %q, want %q", key, got, value)
+		}
+	}
+}
+
+func TestPackageLoggingBeforeInit(t *testing.T) {
+	originalGlobalSDK := globalSDK
+	globalSDK = nil
+	defer func() { globalSDK = originalGlobalSDK }()
+
+	if sdk, ok := TryGet(); ok || sdk != nil {
+		t.Fatalf("TryGet() = %v, %v before Init, want nil, false", sdk, ok)
+	}
+
+	defer func() {
+		if r := recover(); r != nil {
+			t.Fatalf("package-level logging panicked before Init: %v", r)
+		}
+	}()
+	ctx := context.Background()
+	Trace("trace")
+	Debug("debug")
+	Info("info", "key", "value")
+	Warn("warn")
+	Error("error")
+	Fatal("fatal")
+	InfoContext(ctx, "info")
+	ErrorContext(ctx, "error")
+	Log(ctx, slog.LevelInfo, "log")
+	LogAttrs(ctx, slog.LevelInfo, "attrs", slog.String("key", "value"))
+
+	wantErr := errors.New("boom")
+	if err := LogErr(ctx, "failed", wantErr); err != wantErr {
+		t.Errorf("LogErr() = %v before Init, want the error passed in", err)
+	}
+}
+
+func TestPackageAccessorsBeforeInit(t *testing.T) {
+	originalGlobalSDK := globalSDK
+	globalSDK = nil
+	defer func() { globalSDK = originalGlobalSDK }()
+
+	ctx := context.Background()
+	SetLogLevel(slog.LevelDebug)
+	With("key", "value").Info("info")
+	WithGroup("group").Info("info")
+	NamedLogger("db").Info("info")
+	GetLogger().Info("info")
+
+	_, span := StartSpan(ctx, "work")
+	if span.IsRecording() {
+		t.Error("StartSpan() before Init returned a recording span")
+	}
+	span.End()
+	Tracer().Start(ctx, "work")
+	Meter().Int64Counter("requests")
+
+	if err := Ping(ctx); !errors.Is(err, ErrNotInitialized) {
+		t.Errorf("Ping() = %v before Init, want ErrNotInitialized", err)
+	}
+	if err := RegisterGauge("queue_depth", "1", "", func() float64 { return 0 }); !errors.Is(err, ErrNotInitialized) {
+		t.Errorf("RegisterGauge() = %v before Init, want ErrNotInitialized", err)
+	}
+
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusTeapot)
+	})
+	for name, handler := range map[string]http.Handler{
+		"RecoverMiddleware":   RecoverMiddleware(next),
+		"RequestIDMiddleware": RequestIDMiddleware("")(next),
+	} {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest("GET", "/", nil))
+		if rec.Code != http.StatusTeapot {
+			t.Errorf("%s before Init: status = %d, want %d", name, rec.Code, http.StatusTeapot)
+		}
+	}
+}
+
+func TestRecoverBeforeInitRepanics(t *testing.T) {
+	originalGlobalSDK := globalSDK
+	globalSDK = nil
+	defer func() { globalSDK = originalGlobalSDK }()
+
+	want := errors.New("boom")
+	defer func() {
+		if got := recover(); got != want {
+			t.Errorf("recovered %v, want the original panic value %v", got, want)
+		}
+	}()
+	func() {
+		defer Recover(context.Background())
+		panic(want)
+	}()
+}
+
+func TestNamedLogger(t *testing.T) {
+	server := newCaptureServer(t)
+	sdk := newSDK(testConfig(server.URL))
+
+	db := sdk.NamedLogger("db")
+	if again := sdk.NamedLogger("db"); again != db {
+		t.Error("NamedLogger(\"db\") returned a different logger on the second call")
+	}
+	if sdk.NamedLogger("cache") == db {
+		t.Error("NamedLogger returned the same logger for different names")
+	}
+	if allocs := testing.AllocsPerRun(100, func() { sdk.NamedLogger("db") }); allocs != 0 {
+		t.Errorf("cached NamedLogger() allocated %v times per call, want 0", allocs)
+	}
+
+	db.Info("query executed", "rows", 3)
+	sdk.NamedLogger("db").Info("query executed again")
+	sdk.Shutdown(context.Background())
+
+	var count int
+	for _, req := range server.decodeLogRequests(t) {
+		for _, entry := range req.Logs {
+			count++
+			if entry.Props["component"] != "db" {
+				t.Errorf("log %q props = %v, want component=db", entry.Msg, entry.Props)
+			}
+		}
+	}
+	if count != 2 {
+		t.Errorf("exported %d logs, want 2", count)
+	}
+}
+
+func TestMalformedArgsWarning(t *testing.T) {
+	var warnings strings.Builder
+	malformedArgsOnce = sync.Once{}
+	defer func() { malformedArgsOnce = sync.Once{} }()
+
+	server := newCaptureServer(t)
+	sdk := newSDK(testConfig(server.URL).WithDebugWriter(&warnings))
+	logger := sdk.Logger()
+
+	logger.Info("well formed", "user", "alice", slog.Int("attempt", 2))
+	if warnings.Len() != 0 {
+		t.Fatalf("well-formed args produced a warning: %q", warnings.String())
+	}
+	logger.Info("odd args", "user", "alice", "orphan")
+	logger.Info("bad key", 42, "user", "bob")
+	logger.InfoAttrs("typed", slog.String("user", "carol"))
+	logger.ErrorAttrsContext(context.Background(), "typed error", slog.Int("code", 7))
+	sdk.Shutdown(context.Background())
+
+	if got := strings.Count(warnings.String(), "Warning:"); got != 1 {
+		t.Errorf("got %d warnings, want exactly one: %q", got, warnings.String())
+	}
+	if !strings.Contains(warnings.String(), `"odd args"`) {
+		t.Errorf("warning %q does not name the offending log", warnings.String())
+	}
+
+	props := map[string]map[string]interface{}{}
+	lvls := map[string]string{}
+	for _, req := range server.decodeLogRequests(t) {
+		for _, entry := range req.Logs {
+			props[entry.Msg] = entry.Props
+			lvls[entry.Msg] = entry.Lvl
+		}
+	}
+	want := map[string]map[string]interface{}{
+		"well formed": {"user": "alice", "attempt": "2"},
+		"odd args":    {"user": "alice"},
+		"bad key":     {"user": "bob"},
+		"typed":       {"user": "carol"},
+		"typed error": {"code": "7"},
+	}
+	for msg, attrs := range want {
+		for key, value := range attrs {
+			if got := props[msg][key]; got != value {
+				t.Errorf("log %q props[%q] = %v, want %v", msg, key, got, value)
+			}
+		}
+	}
+	if lvls["typed error"] != "ERROR" {
+		t.Errorf("ErrorAttrsContext exported level %q, want ERROR", lvls["typed error"])
+	}
+}
@@ -4,12 +4,14 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"log/slog"
-	"math/rand"
 	"net/http"
-	"os"
+	"sort"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"go.opentelemetry.io/contrib/bridges/otelslog"
@@ -23,16 +25,33 @@ type LogEntry struct {
 	Ts    float64                `json:"ts"`
 	Props map[string]interface{} `json:"props,omitempty"`
 	Tid   string                 `json:"tid,omitempty"`
+	Sid   string                 `json:"sid,omitempty"`
 	Fl    string                 `json:"fl,omitempty"`
 	Tb    string                 `json:"tb,omitempty"`
 	Ln    int                    `json:"ln,omitempty"`
+	Fn    string                 `json:"fn,omitempty"`
 	Src   string                 `json:"src"`
+	Fp    string                 `json:"fingerprint,omitempty"`
+
+	// project is the route set with ContextWithProject, if any
+	project *projectRoute
 }
 
+// defaultLogSource is the "src" of log entries when Config.LogSource is empty.
+const defaultLogSource = "lumberjack-go"
+
+// Source location attribute keys set by the slog bridge when WithSource is on.
+const (
+	codeFilePathKey     = "code.file.path"
+	codeLineNumberKey   = "code.line.number"
+	codeFunctionNameKey = "code.function.name"
+)
+
 type LogRequest struct {
 	Logs        []LogEntry `json:"logs"`
 	ProjectName string     `json:"project_name,omitempty"`
 	SdkVersion  int        `json:"sdk_version"`
+	SdkSemver   string     `json:"sdk_semver,omitempty"`
 	ReleaseId   string     `json:"release_id,omitempty"`
 	ReleaseType string     `json:"release_type,omitempty"`
 }
@@ -40,69 +59,199 @@ type LogRequest struct {
 type DefaultLogsExporter struct {
 	config      *Config
 	client      *http.Client
-	batch       []LogEntry
-	batchMu     sync.Mutex
+	apiKey      *apiKeySource
+	breaker     *circuitBreaker
+	shards      []logShard
+	nextShard   atomic.Uint32
+	queued      atomic.Int64
+	queuedBytes atomic.Int64
+	batchMu     sync.Mutex // guards ageTimer and the shard swap in flush
 	stopCh      chan struct{}
 	wg          sync.WaitGroup
 	flushTicker *time.Ticker
+	ageTimer    *time.Timer
+	lastFlush   atomic.Int64 // unix nanoseconds
+	stats       exporterStats
+	spool       *spool
+
+	releaseID   string
+	releaseType string
+
+	logConverter
+}
+
+// logConverter turns log records into LogEntry values according to the
+// config's source, redaction, key normalization, truncation and
+// fingerprinting settings.
+type logConverter struct {
+	config       *Config
+	source       string
+	redactor     *redactor
+	normalizeKey func(string) string
+	fingerprint  func(LogEntry) string
+}
+
+func newLogConverter(config *Config) logConverter {
+	return logConverter{
+		config:       config,
+		source:       config.logSource(),
+		redactor:     newRedactor(config),
+		normalizeKey: keyNormalizer(config),
+		fingerprint:  fingerprinter(config),
+	}
+}
+
+// logShard is one of the sub-batches Export appends to, each behind its own
+// lock so concurrent producers rarely wait on each other. flush merges them.
+type logShard struct {
+	mu         sync.Mutex
+	batch      []LogEntry
+	spare      []LogEntry
+	batchBytes int
+	_          [64]byte // keep neighbouring shards' locks on separate cache lines
 }
 
 func NewLogsExporter(config *Config) *DefaultLogsExporter {
+	return newLogsExporter(config, newCircuitBreaker(config))
+}
+
+// newLogsExporter creates the exporter with a circuit breaker that may be shared
+// with the SDK's other exporters.
+func newLogsExporter(config *Config, breaker *circuitBreaker) *DefaultLogsExporter {
 	exporter := &DefaultLogsExporter{
-		config: config,
-		client: &http.Client{
-			Timeout: 30 * time.Second,
-		},
-		batch:  make([]LogEntry, 0, config.BatchSize),
-		stopCh: make(chan struct{}),
+		config:  config,
+		breaker: breaker,
+		client:  newHTTPClient(config),
+		apiKey:  newAPIKeySource(config),
+		shards:  make([]logShard, config.logBatchShards()),
+		stopCh:  make(chan struct{}),
+
+		releaseID:   config.releaseID(),
+		releaseType: config.releaseType(),
+
+		logConverter: newLogConverter(config),
 	}
 
+	for i := range exporter.shards {
+		exporter.shards[i].batch = make([]LogEntry, 0, config.BatchSize/len(exporter.shards))
+	}
+	exporter.lastFlush.Store(time.Now().UnixNano())
+
 	exporter.flushTicker = time.NewTicker(config.BatchTimeout)
 	exporter.wg.Add(1)
 	go exporter.runFlusher()
 
+	if exporter.spool = newExporterSpool(config, "logs"); exporter.spool != nil {
+		exporter.wg.Add(1)
+		go func() {
+			defer exporter.wg.Done()
+			exporter.spool.run(config.SpoolReplayInterval, exporter.stopCh, exporter.replaySpooled)
+		}()
+	}
+
 	return exporter
 }
 
 func (e *DefaultLogsExporter) Export(ctx context.Context, records []*sdklog.Record) error {
 	// Convert Record to LogEntry
 	entries := make([]LogEntry, 0, len(records))
+	project := projectFromContext(ctx)
 	for _, record := range records {
 		entry := e.convertRecordToEntry(record)
+		entry.project = project
 		entries = append(entries, entry)
 	}
 
-	e.batchMu.Lock()
-	e.batch = append(e.batch, entries...)
-	shouldFlush := len(e.batch) >= e.config.BatchSize
-	e.batchMu.Unlock()
+	shard := e.shard()
+	shard.mu.Lock()
+	for queueFull(e.config, len(shard.batch), len(entries)) {
+		shard.mu.Unlock()
+		e.flush(exportContext(ctx, e.config))
+		shard.mu.Lock()
+	}
+	evicted, accepted := applyOverflowPolicy(e.config, shard.batch, entries)
+	if dropped := evicted + len(entries) - len(accepted); dropped > 0 {
+		e.stats.dropped.Add(uint64(dropped))
+		evictedBytes := 0
+		for _, entry := range shard.batch[:evicted] {
+			evictedBytes += entry.estimatedSize()
+		}
+		shard.batchBytes -= evictedBytes
+		e.queued.Add(-int64(evicted))
+		e.queuedBytes.Add(-int64(evictedBytes))
+		shard.batch = append(shard.batch[:0], shard.batch[evicted:]...)
+	}
+	shard.batch = append(shard.batch, accepted...)
+	acceptedBytes := 0
+	for _, entry := range accepted {
+		acceptedBytes += entry.estimatedSize()
+	}
+	shard.batchBytes += acceptedBytes
+	shard.mu.Unlock()
+
+	e.stats.enqueued.Add(uint64(len(accepted)))
+	queued := e.queued.Add(int64(len(accepted)))
+	queuedBytes := e.queuedBytes.Add(int64(acceptedBytes))
+	if len(accepted) > 0 && e.config.MaxBufferAge > 0 {
+		e.batchMu.Lock()
+		e.armAgeTimerLocked()
+		e.batchMu.Unlock()
+	}
+	shouldFlush := batchFull(e.config, int(queued), int(queuedBytes)) ||
+		batchStale(e.config, time.Unix(0, e.lastFlush.Load()))
 
 	if shouldFlush {
-		e.flush()
+		e.flush(exportContext(ctx, e.config))
 	}
 
 	return nil
 }
 
-func (e *DefaultLogsExporter) convertRecordToEntry(record *sdklog.Record) LogEntry {
+func (e *logConverter) convertRecordToEntry(record *sdklog.Record) LogEntry {
 	entry := LogEntry{
 		Msg: record.Body().String(),
 		Lvl: severityToString(record.Severity()),
 		Ts:  float64(record.Timestamp().UnixNano()) / 1e9,
-		Src: "lumberjack-go",
+		Src: e.source,
 	}
 
 	// Extract trace context if available
 	if record.TraceID().IsValid() {
 		entry.Tid = record.TraceID().String()
 	}
+	if record.SpanID().IsValid() {
+		entry.Sid = record.SpanID().String()
+	}
 
 	// Convert attributes to props
 	props := make(map[string]interface{})
 	record.WalkAttributes(func(kv log.KeyValue) bool {
-		props[string(kv.Key)] = kv.Value.AsString()
+		// Source location added by the slog bridge
+		switch kv.Key {
+		case codeFilePathKey:
+			entry.Fl = kv.Value.AsString()
+			return true
+		case codeLineNumberKey:
+			entry.Ln = int(kv.Value.AsInt64())
+			return true
+		case codeFunctionNameKey:
+			entry.Fn = kv.Value.AsString()
+			return true
+		}
+		e.addProp(props, string(kv.Key), kv.Value)
 		return true
 	})
+	e.redactor.redactProps(props)
+	props = normalizeKeys(props, e.normalizeKey)
+
+	var msgTruncated bool
+	entry.Msg, msgTruncated = truncateString(entry.Msg, e.config.MaxMessageBytes)
+	if truncateProps(props, e.config.MaxAttrValueBytes) || msgTruncated {
+		props[truncatedKey] = "true"
+		if e.config.TypedAttributes {
+			props[truncatedKey] = true
+		}
+	}
 
 	if len(props) > 0 {
 		entry.Props = props
@@ -120,9 +269,111 @@ func (e *DefaultLogsExporter) convertRecordToEntry(record *sdklog.Record) LogEnt
 		}
 	}
 
+	if e.fingerprint != nil && isErrorLevel(entry.Lvl) {
+		entry.Fp = e.fingerprint(entry)
+	}
+
 	return entry
 }
 
+// estimatedSize cheaply approximates the encoded size of the entry.
+func (entry LogEntry) estimatedSize() int {
+	size := entryOverheadBytes + len(entry.Msg) + len(entry.Tid) + len(entry.Sid) + len(entry.Fl) + len(entry.Tb) + len(entry.Fn) + len(entry.Src) + len(entry.Fp)
+	for k, v := range entry.Props {
+		size += len(k) + estimateValueSize(v) + 4
+	}
+	return size
+}
+
+// addProp stores v under key: as a string, with a slog group encoded as a
+// JSON object string, or, with Config.TypedAttributes, as addLogProp does.
+func (e *logConverter) addProp(props map[string]interface{}, key string, v log.Value) {
+	if e.config.TypedAttributes {
+		addLogProp(props, key, v)
+		return
+	}
+	switch v.Kind() {
+	case log.KindString:
+		props[key] = v.AsString()
+	case log.KindBytes:
+		props[key] = string(v.AsBytes())
+	case log.KindEmpty:
+		props[key] = ""
+	case log.KindMap, log.KindSlice:
+		encoded, err := json.Marshal(e.redactedValue(key, v))
+		if err != nil {
+			encoded = []byte(v.String())
+		}
+		props[key] = string(encoded)
+	default:
+		props[key] = v.String()
+	}
+}
+
+// redactedValue is logValueToInterface with group members redacted, since
+// redactProps only sees the encoded group.
+func (e *logConverter) redactedValue(key string, v log.Value) interface{} {
+	if v.Kind() != log.KindMap {
+		return logValueToInterface(v)
+	}
+	out := make(map[string]interface{})
+	for _, kv := range v.AsMap() {
+		memberKey := key + "." + string(kv.Key)
+		member := e.redactedValue(memberKey, kv.Value)
+		if str, ok := member.(string); e.redactor.matchesKey(memberKey) || ok && e.redactor.matchesValue(str) {
+			member = redactedValue
+		}
+		out[string(kv.Key)] = member
+	}
+	return out
+}
+
+// addLogProp stores v under key, flattening map values (slog groups) into
+// "group.key" entries.
+func addLogProp(props map[string]interface{}, key string, v log.Value) {
+	if v.Kind() == log.KindMap {
+		for _, kv := range v.AsMap() {
+			addLogProp(props, key+"."+string(kv.Key), kv.Value)
+		}
+		return
+	}
+	props[key] = logValueToInterface(v)
+}
+
+// logValueToInterface converts an OpenTelemetry log value to its natural Go
+// representation for JSON encoding.
+func logValueToInterface(v log.Value) interface{} {
+	switch v.Kind() {
+	case log.KindString:
+		return v.AsString()
+	case log.KindInt64:
+		return v.AsInt64()
+	case log.KindFloat64:
+		return v.AsFloat64()
+	case log.KindBool:
+		return v.AsBool()
+	case log.KindBytes:
+		return string(v.AsBytes())
+	case log.KindSlice:
+		values := v.AsSlice()
+		out := make([]interface{}, len(values))
+		for i, item := range values {
+			out[i] = logValueToInterface(item)
+		}
+		return out
+	case log.KindMap:
+		out := make(map[string]interface{})
+		for _, kv := range v.AsMap() {
+			out[string(kv.Key)] = logValueToInterface(kv.Value)
+		}
+		return out
+	case log.KindEmpty:
+		return nil
+	default:
+		return v.String()
+	}
+}
+
 func severityToString(sev log.Severity) string {
 	switch {
 	case sev >= log.SeverityFatal:
@@ -163,115 +414,351 @@ func (e *DefaultLogsExporter) runFlusher() {
 	for {
 		select {
 		case <-e.flushTicker.C:
-			e.flush()
+			e.flush(context.Background())
 		case <-e.stopCh:
 			return
 		}
 	}
 }
 
-func (e *DefaultLogsExporter) flush() {
+// shard picks the sub-batch for the next Export, round-robin.
+func (e *DefaultLogsExporter) shard() *logShard {
+	if len(e.shards) == 1 {
+		return &e.shards[0]
+	}
+	return &e.shards[e.nextShard.Add(1)%uint32(len(e.shards))]
+}
+
+func (e *DefaultLogsExporter) flush(ctx context.Context) {
 	e.batchMu.Lock()
-	if len(e.batch) == 0 {
-		e.batchMu.Unlock()
+	if e.ageTimer != nil {
+		e.ageTimer.Stop()
+		e.ageTimer = nil
+	}
+	e.lastFlush.Store(time.Now().UnixNano())
+	resetFlushTicker(e.flushTicker, e.config, e.stopCh)
+
+	// Swap in each shard's spare buffer rather than copying, so Export
+	// callers only wait for a slice swap
+	batches := make([][]LogEntry, len(e.shards))
+	total := 0
+	for i := range e.shards {
+		shard := &e.shards[i]
+		shard.mu.Lock()
+		if len(shard.batch) > 0 {
+			batches[i] = shard.batch
+			total += len(shard.batch)
+			shard.batch = shard.spare
+			if shard.batch == nil {
+				shard.batch = make([]LogEntry, 0, cap(batches[i]))
+			}
+			shard.spare = nil
+			e.queued.Add(-int64(len(batches[i])))
+			e.queuedBytes.Add(-int64(shard.batchBytes))
+			shard.batchBytes = 0
+		}
+		shard.mu.Unlock()
+	}
+	e.batchMu.Unlock()
+	if total == 0 {
 		return
 	}
 
-	entries := make([]LogEntry, len(e.batch))
-	copy(entries, e.batch)
-	e.batch = e.batch[:0]
-	e.batchMu.Unlock()
+	var entries []LogEntry
+	for _, batch := range batches {
+		if len(batch) == total {
+			entries = batch
+			break
+		}
+	}
+	if entries == nil {
+		entries = make([]LogEntry, 0, total)
+		for _, batch := range batches {
+			entries = append(entries, batch...)
+		}
+		// Shards are filled round-robin, so restore emit order; entries
+		// with equal timestamps keep their order within a shard
+		sort.SliceStable(entries, func(i, j int) bool {
+			return entries[i].Ts < entries[j].Ts
+		})
+	}
+	e.sendBatch(ctx, entries)
+
+	// Drop references to the sent entries and keep the buffers for next time
+	for i, batch := range batches {
+		if batch == nil {
+			continue
+		}
+		clear(batch)
+		shard := &e.shards[i]
+		shard.mu.Lock()
+		if shard.spare == nil {
+			shard.spare = batch[:0]
+		}
+		shard.mu.Unlock()
+	}
+}
 
-	e.sendBatch(entries)
+// armAgeTimerLocked schedules a flush once the oldest buffered entry reaches
+// MaxBufferAge. Must be called with batchMu held.
+func (e *DefaultLogsExporter) armAgeTimerLocked() {
+	if e.config.MaxBufferAge > 0 && e.ageTimer == nil {
+		e.ageTimer = time.AfterFunc(e.config.MaxBufferAge, func() {
+			e.flush(context.Background())
+		})
+	}
 }
 
-func (e *DefaultLogsExporter) sendBatch(entries []LogEntry) {
+func (e *DefaultLogsExporter) sendBatch(ctx context.Context, entries []LogEntry) {
+	if e.config.BeforeSendLogs != nil {
+		if entries = e.config.BeforeSendLogs(entries); len(entries) == 0 {
+			return
+		}
+	}
+	for _, batch := range groupByProject(entries) {
+		for _, part := range splitBatch(e.config, batch.entries) {
+			e.sendRequest(ctx, batch.route, part)
+		}
+	}
+}
+
+// sendRequest sends entries in a single request to route, or the configured
+// project if route is nil, spooling them if it fails. Entries the server
+// rejects individually are re-sent on their own, up to MaxRetries times,
+// and then dropped.
+func (e *DefaultLogsExporter) sendRequest(ctx context.Context, route *projectRoute, entries []LogEntry) {
+	backoff := e.config.RetryBackoff
+	for attempt := 0; ; attempt++ {
+		rejected, err := e.sendEntries(ctx, route, entries)
+		if len(rejected) == 0 {
+			return
+		}
+		if attempt >= e.config.MaxRetries {
+			e.config.debugf("Dropping %d rejected log entries\n", len(rejected))
+			e.stats.dropped.Add(uint64(len(rejected)))
+			e.config.reportExportError("logs", len(rejected), err)
+			return
+		}
+		e.stats.retries.Add(1)
+		if sleepBackoff(ctx, e.config, backoff) != nil {
+			e.stats.dropped.Add(uint64(len(rejected)))
+			e.config.reportExportError("logs", len(rejected), ctx.Err())
+			return
+		}
+		backoff = nextBackoff(e.config, backoff)
+		entries = rejected
+	}
+}
+
+// sendEntries makes one sendRequest attempt, returning the entries the
+// server rejected and the rejection error, if any.
+func (e *DefaultLogsExporter) sendEntries(ctx context.Context, route *projectRoute, entries []LogEntry) ([]LogEntry, error) {
 	request := LogRequest{
 		Logs:        entries,
 		ProjectName: e.config.ProjectName,
-		SdkVersion:  2,
+		SdkVersion:  sdkVersion,
+		SdkSemver:   sdkRelease(),
+		ReleaseId:   e.releaseID,
+		ReleaseType: e.releaseType,
 	}
-
-	if releaseId := os.Getenv("LUMBERJACK_RELEASE_ID"); releaseId != "" {
-		request.ReleaseId = releaseId
+	if route != nil && route.name != "" {
+		request.ProjectName = route.name
 	}
-
-	if releaseType := os.Getenv("LUMBERJACK_RELEASE_TYPE"); releaseType != "" {
-		request.ReleaseType = releaseType
+	ctx = context.WithValue(ctx, requestRouteKey{}, route)
+
+	var data []byte
+	var err error
+	if streamBatch(e.config, entries) {
+		// Encode entry by entry into the request body instead of holding the
+		// whole payload in memory.
+		head := request
+		head.Logs = []LogEntry{}
+		err = e.send(ctx, func() (io.ReadCloser, error) {
+			return streamJSONArray(head, "logs", entries)
+		})
+	} else {
+		buf, encErr := encodeJSON(request)
+		if encErr != nil {
+			e.config.debugf("Failed to marshal logs: %v\n", encErr)
+			e.stats.dropped.Add(uint64(len(entries)))
+			return nil, nil
+		}
+		defer putJSONBuffer(buf)
+		data = buf.Bytes()
+		err = e.sendWithRetry(ctx, data)
 	}
 
-	data, err := json.Marshal(request)
+	var rejection *rejectionError
+	if errors.As(err, &rejection) {
+		rejected := rejection.entries(entries)
+		e.stats.recordFlushed(len(entries) - len(rejected))
+		e.config.debugf("Server rejected %d of %d log entries\n", len(rejected), len(entries))
+		return rejected, err
+	}
 	if err != nil {
-		if e.config.Debug {
-			fmt.Printf("Failed to marshal logs: %v\n", err)
+		e.config.reportExportError("logs", len(entries), err)
+		if route != nil {
+			e.config.debugf("Dropping %d log entries for project %q: %v\n", len(entries), request.ProjectName, err)
+			e.stats.dropped.Add(uint64(len(entries)))
+			return nil, nil
 		}
-		return
+		spooled := false
+		if data != nil {
+			spooled = e.spool.storeFailed(e.config, "logs", data, err)
+		} else {
+			spooled = e.spool.storeFailedJSON(e.config, "logs", request, err)
+		}
+		if !spooled {
+			e.stats.dropped.Add(uint64(len(entries)))
+		}
+		return nil, nil
 	}
+	e.stats.recordFlushed(len(entries))
+	e.config.debugf("Successfully sent %d log entries\n", len(entries))
+	return nil, nil
+}
 
-	e.sendWithRetry(data)
+// replaySpooled re-sends a spooled request. A partial rejection counts the
+// accepted entries as delivered and reports the rejected ones, rather than
+// keeping a request the server has already taken most of.
+func (e *DefaultLogsExporter) replaySpooled(data []byte) error {
+	err := e.sendWithRetry(context.Background(), data)
+	var rejection *rejectionError
+	if !errors.As(err, &rejection) {
+		return err
+	}
+	var request LogRequest
+	if jsonErr := json.Unmarshal(data, &request); jsonErr != nil {
+		e.stats.dropped.Add(uint64(len(rejection.indices)))
+		e.config.reportExportError("logs", len(rejection.indices), err)
+		return nil
+	}
+	rejected := rejection.entries(request.Logs)
+	e.stats.recordFlushed(len(request.Logs) - len(rejected))
+	e.stats.dropped.Add(uint64(len(rejected)))
+	e.config.debugf("Server rejected %d of %d replayed log entries\n", len(rejected), len(request.Logs))
+	e.config.reportExportError("logs", len(rejected), err)
+	return nil
 }
 
-func (e *DefaultLogsExporter) sendWithRetry(data []byte) {
+func (e *DefaultLogsExporter) sendWithRetry(ctx context.Context, data []byte) error {
+	return e.send(ctx, func() (io.ReadCloser, error) {
+		return io.NopCloser(bytes.NewReader(data)), nil
+	})
+}
+
+// send POSTs the body returned by body unless the circuit breaker is open,
+// and records the outcome with the breaker.
+func (e *DefaultLogsExporter) send(ctx context.Context, body func() (io.ReadCloser, error)) error {
+	if !e.breaker.allow() {
+		e.config.debugf("Circuit breaker open, not sending logs\n")
+		return errCircuitOpen
+	}
+	err := e.postWithRetry(ctx, body)
+	e.breaker.record(err)
+	return err
+}
+
+// postWithRetry POSTs the body returned by body, calling it again for every
+// attempt.
+func (e *DefaultLogsExporter) postWithRetry(ctx context.Context, body func() (io.ReadCloser, error)) error {
 	url := fmt.Sprintf("%s/logs/batch", e.config.BaseURL)
 	retries := 0
 	backoff := e.config.RetryBackoff
+	start := time.Now()
 
 	for retries <= e.config.MaxRetries {
-		req, err := http.NewRequest("POST", url, bytes.NewBuffer(data))
+		reqBody, err := body()
 		if err != nil {
-			if e.config.Debug {
-				fmt.Printf("Failed to create request: %v\n", err)
-			}
-			return
+			return err
+		}
+		req, err := http.NewRequestWithContext(ctx, "POST", url, reqBody)
+		if err != nil {
+			reqBody.Close()
+			e.config.debugf("Failed to create request: %v\n", err)
+			return err
 		}
 
 		req.Header.Set("Content-Type", "application/json")
-		req.Header.Set("Authorization", "Bearer "+e.config.APIKey)
+		req.Header.Set("User-Agent", e.config.userAgent())
+		if err := e.apiKey.setHeader(req); err != nil {
+			reqBody.Close()
+			e.config.debugf("Failed to authenticate request: %v\n", err)
+			return err
+		}
 
 		resp, err := e.client.Do(req)
 		if err != nil {
-			if e.config.Debug {
-				fmt.Printf("Failed to send logs (attempt %d): %v\n", retries+1, err)
+			if ctx.Err() != nil {
+				e.config.debugf("Aborted sending logs: %v\n", ctx.Err())
+				return ctx.Err()
 			}
+			e.config.debugf("Failed to send logs (attempt %d): %v\n", retries+1, err)
 			retries++
 			if retries <= e.config.MaxRetries {
-				jitter := time.Duration(rand.Float64() * float64(backoff))
-				time.Sleep(backoff + jitter)
-				backoff *= 2
+				delay := jitteredBackoff(e.config, backoff)
+				if retryBudgetExceeded(e.config, start, delay) {
+					break
+				}
+				e.stats.retries.Add(1)
+				if err := sleepDelay(ctx, delay); err != nil {
+					return err
+				}
+				backoff = nextBackoff(e.config, backoff)
 			}
 			continue
 		}
 
+		var rejected []int
+		if resp.StatusCode < http.StatusInternalServerError {
+			rejected = parseRejected(resp.Body)
+		}
 		resp.Body.Close()
 
+		if len(rejected) > 0 {
+			return &rejectionError{indices: rejected}
+		}
 		if resp.StatusCode == http.StatusOK {
-			if e.config.Debug {
-				var request LogRequest
-				json.Unmarshal(data, &request)
-				fmt.Printf("Successfully sent %d log entries\n", len(request.Logs))
-			}
-			return
+			return nil
 		}
 
-		if e.config.Debug {
-			fmt.Printf("Failed to send logs, status: %d\n", resp.StatusCode)
-		}
+		e.config.debugf("Failed to send logs, status: %d\n", resp.StatusCode)
 
 		if resp.StatusCode >= 500 {
 			retries++
 			if retries <= e.config.MaxRetries {
-				jitter := time.Duration(rand.Float64() * float64(backoff))
-				time.Sleep(backoff + jitter)
-				backoff *= 2
+				delay := jitteredBackoff(e.config, backoff)
+				if retryBudgetExceeded(e.config, start, delay) {
+					break
+				}
+				e.stats.retries.Add(1)
+				if err := sleepDelay(ctx, delay); err != nil {
+					return err
+				}
+				backoff = nextBackoff(e.config, backoff)
 			}
 		} else {
-			break
+			return fmt.Errorf("%w: status %d", errPermanent, resp.StatusCode)
 		}
 	}
 
-	if e.config.Debug && retries > e.config.MaxRetries {
-		fmt.Printf("Max retries exceeded for log batch\n")
+	if retries <= e.config.MaxRetries {
+		e.config.debugf("Retry time limit exceeded for log batch\n")
+		return fmt.Errorf("retry time limit of %v exceeded for log batch", e.config.MaxRetryElapsed)
 	}
+
+	e.config.debugf("Max retries exceeded for log batch\n")
+	return fmt.Errorf("max retries exceeded for log batch")
+}
+
+// Stats returns a snapshot of the exporter's counters.
+func (e *DefaultLogsExporter) Stats() ExporterStats {
+	return e.stats.snapshot()
+}
+
+func (e *DefaultLogsExporter) ForceFlush(ctx context.Context) error {
+	e.flush(exportContext(ctx, e.config))
+	return nil
 }
 
 func (e *DefaultLogsExporter) Shutdown(ctx context.Context) error {
@@ -284,7 +771,7 @@ func (e *DefaultLogsExporter) Shutdown(ctx context.Context) error {
 	}
 
 	e.flushTicker.Stop()
-	e.flush()
+	e.flush(ctx)
 
 	done := make(chan struct{})
 	go func() {
@@ -320,14 +807,33 @@ func (p *LumberjackLogProcessor) Shutdown(ctx context.Context) error {
 }
 
 func (p *LumberjackLogProcessor) ForceFlush(ctx context.Context) error {
-	// If the exporter supports force flush, call it here
+	if flusher, ok := p.exporter.(logsFlusher); ok {
+		return flusher.ForceFlush(ctx)
+	}
 	return nil
 }
 
 // CreateLumberjackSlogHandler creates a slog handler that uses OpenTelemetry logging
 func CreateLumberjackSlogHandler(loggerProvider *sdklog.LoggerProvider, previousHandler slog.Handler) slog.Handler {
+	return newLumberjackSlogHandler(loggerProvider, previousHandler, nil, nil)
+}
+
+// newLumberjackSlogHandler is CreateLumberjackSlogHandler with an optional
+// minimum level for exported records and an optional extractor of attributes
+// from each record's context. Both only apply to export; records still reach
+// previousHandler unchanged, according to its own level.
+func newLumberjackSlogHandler(loggerProvider *sdklog.LoggerProvider, previousHandler slog.Handler, minLevel slog.Leveler, extract func(context.Context) []slog.Attr) slog.Handler {
 	// Create an OpenTelemetry slog bridge handler
-	otelHandler := otelslog.NewHandler("lumberjack-go", otelslog.WithLoggerProvider(loggerProvider))
+	var otelHandler slog.Handler = otelslog.NewHandler("lumberjack-go",
+		otelslog.WithLoggerProvider(loggerProvider),
+		otelslog.WithSource(true),
+	)
+	if extract != nil {
+		otelHandler = &contextAttrHandler{extract: extract, handler: otelHandler}
+	}
+	if minLevel != nil {
+		otelHandler = &levelHandler{level: minLevel, handler: otelHandler}
+	}
 	
 	// If there's a previous handler, we need to chain them
 	if previousHandler != nil {
@@ -340,6 +846,74 @@ func CreateLumberjackSlogHandler(loggerProvider *sdklog.LoggerProvider, previous
 	return otelHandler
 }
 
+type levelOverrideKey struct{}
+
+// ContextWithLevel returns a copy of ctx in which logs at or above level are
+// exported, in place of the SDK's minimum level, e.g. DEBUG for a single
+// request flagged by a header. It applies only to logs made with ctx or a
+// context derived from it, and like MinLogLevel it doesn't affect console
+// output.
+func ContextWithLevel(ctx context.Context, level slog.Level) context.Context {
+	return context.WithValue(ctx, levelOverrideKey{}, level)
+}
+
+// levelHandler drops records below level, or below the level set on their
+// context with ContextWithLevel, before they reach handler.
+type levelHandler struct {
+	level   slog.Leveler
+	handler slog.Handler
+}
+
+func (h *levelHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	minLevel := h.level.Level()
+	if ctx != nil {
+		if override, ok := ctx.Value(levelOverrideKey{}).(slog.Level); ok {
+			minLevel = override
+		}
+	}
+	return level >= minLevel && h.handler.Enabled(ctx, level)
+}
+
+func (h *levelHandler) Handle(ctx context.Context, record slog.Record) error {
+	return h.handler.Handle(ctx, record)
+}
+
+func (h *levelHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &levelHandler{level: h.level, handler: h.handler.WithAttrs(attrs)}
+}
+
+func (h *levelHandler) WithGroup(name string) slog.Handler {
+	return &levelHandler{level: h.level, handler: h.handler.WithGroup(name)}
+}
+
+// contextAttrHandler adds the attributes extract returns for a record's
+// context to the record before it reaches handler.
+type contextAttrHandler struct {
+	extract func(context.Context) []slog.Attr
+	handler slog.Handler
+}
+
+func (h *contextAttrHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.handler.Enabled(ctx, level)
+}
+
+func (h *contextAttrHandler) Handle(ctx context.Context, record slog.Record) error {
+	if attrs := h.extract(ctx); len(attrs) > 0 {
+		// The record is shared with the chained local handler
+		record = record.Clone()
+		record.AddAttrs(attrs...)
+	}
+	return h.handler.Handle(ctx, record)
+}
+
+func (h *contextAttrHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &contextAttrHandler{extract: h.extract, handler: h.handler.WithAttrs(attrs)}
+}
+
+func (h *contextAttrHandler) WithGroup(name string) slog.Handler {
+	return &contextAttrHandler{extract: h.extract, handler: h.handler.WithGroup(name)}
+}
+
 type chainedHandler struct {
 	primary   slog.Handler
 	secondary slog.Handler
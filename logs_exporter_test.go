@@ -0,0 +1,852 @@
+package lumberjack
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"runtime"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"go.opentelemetry.io/otel/log"
+	sdklog "go.opentelemetry.io/otel/sdk/log"
+)
+
+func newTestRecord(body string, severity log.Severity) *sdklog.Record {
+	var r sdklog.Record
+	r.SetTimestamp(time.Now())
+	r.SetBody(log.StringValue(body))
+	r.SetSeverity(severity)
+	return &r
+}
+
+func TestLogsExporterMaxBufferAge(t *testing.T) {
+	server := newCaptureServer(t)
+
+	config := testConfig(server.URL).WithMaxBufferAge(50 * time.Millisecond)
+	exporter := NewLogsExporter(config)
+	defer exporter.Shutdown(context.Background())
+
+	start := time.Now()
+	if err := exporter.Export(context.Background(), []*sdklog.Record{newTestRecord("lonely log", log.SeverityInfo)}); err != nil {
+		t.Fatalf("Export() unexpected error = %v", err)
+	}
+
+	server.waitForRequest(t, time.Second)
+	if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+		t.Errorf("log delivered after %v, want within MaxBufferAge", elapsed)
+	}
+
+	requests := server.decodeLogRequests(t)
+	if len(requests) != 1 || len(requests[0].Logs) != 1 || requests[0].Logs[0].Msg != "lonely log" {
+		t.Errorf("unexpected log requests: %+v", requests)
+	}
+}
+
+func TestLogsExporterFlushesStaleBatchOnExport(t *testing.T) {
+	server := newCaptureServer(t)
+
+	exporter := NewLogsExporter(testConfig(server.URL))
+	defer exporter.Shutdown(context.Background())
+
+	// Simulate a ticker that has fallen behind: the last flush is older than
+	// BatchTimeout, so the next Export should flush instead of waiting.
+	exporter.lastFlush.Store(time.Now().Add(-2 * exporter.config.BatchTimeout).UnixNano())
+
+	if err := exporter.Export(context.Background(), []*sdklog.Record{newTestRecord("late log", log.SeverityInfo)}); err != nil {
+		t.Fatalf("Export() unexpected error = %v", err)
+	}
+	server.waitForRequest(t, time.Second)
+
+	requests := server.decodeLogRequests(t)
+	if len(requests) != 1 || len(requests[0].Logs) != 1 || requests[0].Logs[0].Msg != "late log" {
+		t.Errorf("unexpected log requests: %+v", requests)
+	}
+
+	// The flush restarted the age window, so a fresh entry waits again.
+	if err := exporter.Export(context.Background(), []*sdklog.Record{newTestRecord("fresh log", log.SeverityInfo)}); err != nil {
+		t.Fatalf("Export() unexpected error = %v", err)
+	}
+	time.Sleep(50 * time.Millisecond)
+	if got := len(server.decodeLogRequests(t)); got != 1 {
+		t.Errorf("got %d requests after a fresh Export, want 1", got)
+	}
+}
+
+func TestLogsExporterRetryHonorsContext(t *testing.T) {
+	server := newCaptureServer(t)
+	server.setStatus(http.StatusServiceUnavailable)
+
+	config := testConfig(server.URL).WithMaxRetries(5).WithRetryBackoff(time.Second)
+	exporter := NewLogsExporter(config)
+	defer exporter.Shutdown(context.Background())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		server.waitForRequest(t, time.Second)
+		cancel()
+	}()
+
+	start := time.Now()
+	err := exporter.sendWithRetry(ctx, []byte(`{"logs":[]}`))
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("sendWithRetry() error = %v, want context.Canceled", err)
+	}
+	if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+		t.Errorf("sendWithRetry() returned after %v, want prompt exit on cancel", elapsed)
+	}
+}
+
+func TestLogsExporterMaxRetryElapsed(t *testing.T) {
+	var attempts atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts.Add(1)
+		time.Sleep(50 * time.Millisecond)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	config := testConfig(server.URL).
+		WithMaxRetries(100).
+		WithRetryBackoff(20 * time.Millisecond).
+		WithMaxRetryElapsed(300 * time.Millisecond)
+	exporter := NewLogsExporter(config)
+	defer exporter.Shutdown(context.Background())
+
+	start := time.Now()
+	err := exporter.sendWithRetry(context.Background(), []byte(`{"logs":[]}`))
+	elapsed := time.Since(start)
+	if err == nil {
+		t.Fatal("sendWithRetry() error = nil, want the retry time limit error")
+	}
+	if elapsed > 300*time.Millisecond+100*time.Millisecond {
+		t.Errorf("sendWithRetry() gave up after %v, want within the 300ms MaxRetryElapsed", elapsed)
+	}
+	if got := attempts.Load(); got < 2 || got > 5 {
+		t.Errorf("server saw %d attempts, want a few retries before the limit", got)
+	}
+}
+
+func TestLogsExporterMaxRetryElapsedCountsJitter(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	// Full jitter waits 100-200ms, so the un-jittered 100ms fits the budget
+	// but most actual delays don't
+	config := testConfig(server.URL).
+		WithMaxRetries(1).
+		WithRetryBackoff(100 * time.Millisecond).
+		WithJitterStrategy(JitterFull).
+		WithMaxRetryElapsed(120 * time.Millisecond)
+	exporter := NewLogsExporter(config)
+	defer exporter.Shutdown(context.Background())
+
+	for i := 0; i < 5; i++ {
+		start := time.Now()
+		exporter.sendWithRetry(context.Background(), []byte(`{"logs":[]}`))
+		if elapsed := time.Since(start); elapsed > 120*time.Millisecond+30*time.Millisecond {
+			t.Fatalf("sendWithRetry() gave up after %v, want within the 120ms MaxRetryElapsed", elapsed)
+		}
+	}
+}
+
+func TestLogsExporterDebugWriter(t *testing.T) {
+	server := newCaptureServer(t)
+	server.setStatus(http.StatusServiceUnavailable)
+
+	var diagnostics strings.Builder
+	config := testConfig(server.URL).
+		WithDebug(true).
+		WithDebugWriter(&diagnostics).
+		WithMaxRetries(1).
+		WithRetryBackoff(time.Millisecond)
+	exporter := NewLogsExporter(config)
+	defer exporter.Shutdown(context.Background())
+
+	exporter.sendBatch(context.Background(), []LogEntry{{Msg: "lost"}})
+
+	for _, want := range []string{"Failed to send logs, status: 503", "Max retries exceeded for log batch"} {
+		if !strings.Contains(diagnostics.String(), want) {
+			t.Errorf("debug output %q does not contain %q", diagnostics.String(), want)
+		}
+	}
+}
+
+func TestLogsExporterShutdownHonorsDeadline(t *testing.T) {
+	server := newCaptureServer(t)
+	server.setStatus(http.StatusServiceUnavailable)
+
+	config := testConfig(server.URL).WithMaxRetries(5).WithRetryBackoff(time.Second)
+	exporter := NewLogsExporter(config)
+	if err := exporter.Export(context.Background(), []*sdklog.Record{newTestRecord("pending", log.SeverityInfo)}); err != nil {
+		t.Fatalf("Export() unexpected error = %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	exporter.Shutdown(ctx)
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("Shutdown() returned after %v, want it to honor the 100ms deadline", elapsed)
+	}
+}
+
+// maskedCard is a LogValuer that hides all but the last four digits.
+type maskedCard string
+
+func (c maskedCard) LogValue() slog.Value {
+	return slog.StringValue("****" + string(c)[len(c)-4:])
+}
+
+func TestLogValuerResolvedBeforeExport(t *testing.T) {
+	server := newCaptureServer(t)
+	sdk := newSDK(testConfig(server.URL))
+
+	card := maskedCard("4111111111111111")
+	sdk.Logger().With("default_card", card).Info("charge",
+		"card", card,
+		slog.Group("payment", slog.Any("card", card)),
+	)
+	sdk.Shutdown(context.Background())
+
+	requests := server.decodeLogRequests(t)
+	if len(requests) != 1 || len(requests[0].Logs) != 1 {
+		t.Fatalf("expected a single exported log, got %+v", requests)
+	}
+	props := requests[0].Logs[0].Props
+	for _, key := range []string{"card", "default_card"} {
+		if props[key] != "****1111" {
+			t.Errorf("props[%q] = %v, want the masked value %q", key, props[key], "****1111")
+		}
+	}
+	if want := `{"card":"****1111"}`; props["payment"] != want {
+		t.Errorf("props[%q] = %v, want the masked group %s", "payment", props["payment"], want)
+	}
+}
+
+func TestTypedAttributes(t *testing.T) {
+	server := newCaptureServer(t)
+	sdk := newSDK(testConfig(server.URL).WithTypedAttributes(true))
+
+	sdk.Logger().Info("charge",
+		"attempt", 2,
+		"retry", true,
+		slog.Group("payment", slog.String("currency", "EUR")),
+	)
+	sdk.Shutdown(context.Background())
+
+	requests := server.decodeLogRequests(t)
+	if len(requests) != 1 || len(requests[0].Logs) != 1 {
+		t.Fatalf("expected a single exported log, got %+v", requests)
+	}
+	props := requests[0].Logs[0].Props
+	want := map[string]any{"attempt": float64(2), "retry": true, "payment.currency": "EUR"}
+	for key, value := range want {
+		if props[key] != value {
+			t.Errorf("props[%q] = %#v, want %#v", key, props[key], value)
+		}
+	}
+}
+
+func TestLogsCarrySpanContext(t *testing.T) {
+	server := newCaptureServer(t)
+	sdk := newSDK(testConfig(server.URL))
+
+	ctx, span := sdk.StartSpan(context.Background(), "work")
+	sdk.Logger().InfoContext(ctx, "inside span")
+	span.End()
+	sdk.Logger().Info("outside span")
+	sdk.Shutdown(context.Background())
+
+	requests := server.decodeLogRequests(t)
+	if len(requests) != 1 || len(requests[0].Logs) != 2 {
+		t.Fatalf("expected two exported logs, got %+v", requests)
+	}
+	inside, outside := requests[0].Logs[0], requests[0].Logs[1]
+
+	sc := span.SpanContext()
+	if inside.Tid != sc.TraceID().String() || inside.Sid != sc.SpanID().String() {
+		t.Errorf("log inside span has tid=%q sid=%q, want %q %q", inside.Tid, inside.Sid, sc.TraceID(), sc.SpanID())
+	}
+	if outside.Tid != "" || outside.Sid != "" {
+		t.Errorf("log outside span has tid=%q sid=%q, want both empty", outside.Tid, outside.Sid)
+	}
+}
+
+func TestLogsExporterConcurrentExport(t *testing.T) {
+	for _, shards := range []int{1, 4} {
+		t.Run(fmt.Sprintf("shards=%d", shards), func(t *testing.T) {
+			server := newCaptureServer(t)
+			exporter := NewLogsExporter(testConfig(server.URL).WithBatchSize(7).WithLogBatchShards(shards))
+
+			const goroutines, perGoroutine = 8, 250
+			var wg sync.WaitGroup
+			for g := 0; g < goroutines; g++ {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					for i := 0; i < perGoroutine; i++ {
+						record := newTestRecord(fmt.Sprintf("log %d-%d", g, i), log.SeverityInfo)
+						if err := exporter.Export(context.Background(), []*sdklog.Record{record}); err != nil {
+							t.Errorf("Export() unexpected error = %v", err)
+						}
+					}
+				}()
+			}
+			wg.Wait()
+			exporter.Shutdown(context.Background())
+
+			seen := make(map[string]bool)
+			for _, req := range server.decodeLogRequests(t) {
+				for _, entry := range req.Logs {
+					if seen[entry.Msg] {
+						t.Errorf("log %q delivered twice", entry.Msg)
+					}
+					seen[entry.Msg] = true
+				}
+			}
+			if len(seen) != goroutines*perGoroutine {
+				t.Errorf("delivered %d distinct logs, want %d", len(seen), goroutines*perGoroutine)
+			}
+			if stats := exporter.Stats(); stats.Dropped != 0 {
+				t.Errorf("Stats().Dropped = %d, want 0", stats.Dropped)
+			}
+		})
+	}
+}
+
+func TestLogsExporterShardsKeepEmitOrder(t *testing.T) {
+	server := newCaptureServer(t)
+	exporter := NewLogsExporter(testConfig(server.URL).WithLogBatchShards(4))
+
+	for i := 0; i < 20; i++ {
+		record := newTestRecord(fmt.Sprintf("step %d", i), log.SeverityInfo)
+		if err := exporter.Export(context.Background(), []*sdklog.Record{record}); err != nil {
+			t.Fatalf("Export() unexpected error = %v", err)
+		}
+	}
+	exporter.Shutdown(context.Background())
+
+	var msgs []string
+	for _, req := range server.decodeLogRequests(t) {
+		for _, entry := range req.Logs {
+			msgs = append(msgs, entry.Msg)
+		}
+	}
+	if len(msgs) != 20 {
+		t.Fatalf("delivered %d logs, want 20", len(msgs))
+	}
+	for i, msg := range msgs {
+		if want := fmt.Sprintf("step %d", i); msg != want {
+			t.Fatalf("log %d = %q, want %q; got order %v", i, msg, want, msgs)
+		}
+	}
+}
+
+func BenchmarkLogsExporterExportParallel(b *testing.B) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+
+	for _, shards := range []int{1, 16} {
+		b.Run(fmt.Sprintf("shards=%d", shards), func(b *testing.B) {
+			exporter := NewLogsExporter(testConfig(server.URL).WithBatchSize(1000).WithLogBatchShards(shards))
+			defer exporter.Shutdown(context.Background())
+
+			// 64 producers, regardless of GOMAXPROCS
+			b.SetParallelism(max(1, 64/runtime.GOMAXPROCS(0)))
+			b.ReportAllocs()
+			b.RunParallel(func(pb *testing.PB) {
+				records := []*sdklog.Record{newTestRecord("benchmark log", log.SeverityInfo)}
+				for pb.Next() {
+					exporter.Export(context.Background(), records)
+				}
+			})
+		})
+	}
+}
+
+func TestLogsExporterStreamsLargeBatch(t *testing.T) {
+	buffered, streamed := newCaptureServer(t), newCaptureServer(t)
+	bufferedExporter := NewLogsExporter(testConfig(buffered.URL))
+	streamedExporter := NewLogsExporter(testConfig(streamed.URL).WithStreamThresholdBytes(1))
+
+	var records []*sdklog.Record
+	for i := 0; i < 50; i++ {
+		records = append(records, newTestRecord(fmt.Sprintf("log %d", i), log.SeverityInfo))
+	}
+	for _, exporter := range []*DefaultLogsExporter{bufferedExporter, streamedExporter} {
+		if err := exporter.Export(context.Background(), records); err != nil {
+			t.Fatalf("Export() unexpected error = %v", err)
+		}
+		exporter.Shutdown(context.Background())
+	}
+
+	want, got := buffered.decodeLogRequests(t), streamed.decodeLogRequests(t)
+	if len(got) != 1 || len(got[0].Logs) != len(records) {
+		t.Fatalf("streamed requests = %+v, want one request with %d logs", got, len(records))
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("streamed request = %+v, want %+v", got[0], want[0])
+	}
+}
+
+func TestLogsExporterMaxRequestBytes(t *testing.T) {
+	server := newCaptureServer(t)
+	exporter := NewLogsExporter(testConfig(server.URL).WithMaxRequestBytes(1024))
+
+	var records []*sdklog.Record
+	for i := 0; i < 40; i++ {
+		records = append(records, newTestRecord(fmt.Sprintf("log %d", i), log.SeverityInfo))
+	}
+	records = append(records, newTestRecord(strings.Repeat("x", 4096), log.SeverityInfo))
+	if err := exporter.Export(context.Background(), records); err != nil {
+		t.Fatalf("Export() unexpected error = %v", err)
+	}
+	exporter.Shutdown(context.Background())
+
+	requests := server.decodeLogRequests(t)
+	if len(requests) < 2 {
+		t.Fatalf("got %d requests, want the batch split across several", len(requests))
+	}
+	var got []string
+	for _, req := range requests {
+		if len(req.Logs) > 1 {
+			size := 0
+			for _, entry := range req.Logs {
+				size += entry.estimatedSize()
+			}
+			if size > 1024 {
+				t.Errorf("request with %d logs is ~%d bytes, want at most 1024", len(req.Logs), size)
+			}
+		}
+		for _, entry := range req.Logs {
+			got = append(got, entry.Msg)
+		}
+	}
+	if len(got) != len(records) {
+		t.Fatalf("got %d logs, want %d", len(got), len(records))
+	}
+	for i, record := range records {
+		if got[i] != record.Body().String() {
+			t.Errorf("log %d = %q, want %q", i, got[i], record.Body().String())
+		}
+	}
+}
+
+func TestLogsExporterContinueExportOnCancel(t *testing.T) {
+	tests := []struct {
+		name             string
+		continueOnCancel bool
+		wantRequests     int
+	}{
+		{"continue on cancel delivers the batch", true, 1},
+		{"abort on cancel drops the request", false, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := newCaptureServer(t)
+
+			config := testConfig(server.URL).WithContinueExportOnCancel(tt.continueOnCancel)
+			config.BatchSize = 1
+			exporter := NewLogsExporter(config)
+			defer exporter.Shutdown(context.Background())
+
+			ctx, cancel := context.WithCancel(context.Background())
+			cancel()
+
+			// BatchSize 1 makes Export flush synchronously on the caller's context
+			if err := exporter.Export(ctx, []*sdklog.Record{newTestRecord("canceled request", log.SeverityInfo)}); err != nil {
+				t.Fatalf("Export() unexpected error = %v", err)
+			}
+
+			if got := len(server.requestsFor("/logs/batch")); got != tt.wantRequests {
+				t.Errorf("server received %d requests, want %d", got, tt.wantRequests)
+			}
+		})
+	}
+}
+
+func TestLogsExporterMaxBatchBytes(t *testing.T) {
+	server := newCaptureServer(t)
+
+	config := testConfig(server.URL).WithMaxBatchBytes(1000)
+	exporter := NewLogsExporter(config)
+	defer exporter.Shutdown(context.Background())
+
+	oversized := strings.Repeat("x", 600)
+	for i := 0; i < 2; i++ {
+		if err := exporter.Export(context.Background(), []*sdklog.Record{newTestRecord(oversized, log.SeverityInfo)}); err != nil {
+			t.Fatalf("Export() unexpected error = %v", err)
+		}
+	}
+
+	// Two ~600 byte entries cross the 1000 byte cap long before BatchSize (100)
+	requests := server.decodeLogRequests(t)
+	if len(requests) != 1 {
+		t.Fatalf("expected an early flush after crossing MaxBatchBytes, got %d requests", len(requests))
+	}
+	if got := len(requests[0].Logs); got != 2 {
+		t.Errorf("flushed batch has %d entries, want 2", got)
+	}
+}
+
+func TestMinLogLevel(t *testing.T) {
+	server := newCaptureServer(t)
+	sdk := newSDK(testConfig(server.URL).WithMinLogLevel(slog.LevelWarn))
+
+	sdk.Logger().Info("routine detail")
+	sdk.Logger().Warn("disk almost full")
+	sdk.Shutdown(context.Background())
+
+	var msgs []string
+	for _, req := range server.decodeLogRequests(t) {
+		for _, entry := range req.Logs {
+			msgs = append(msgs, entry.Msg)
+		}
+	}
+	if len(msgs) != 1 || msgs[0] != "disk almost full" {
+		t.Errorf("exported messages = %v, want only the WARN log", msgs)
+	}
+}
+
+func TestMinLogLevelUnsetExportsDebug(t *testing.T) {
+	server := newCaptureServer(t)
+	config := testConfig(server.URL)
+	config.MinLogLevel = nil // as in a Config literal
+	sdk := newSDK(config)
+
+	sdk.Logger().Debug("cache miss")
+	sdk.Shutdown(context.Background())
+
+	requests := server.decodeLogRequests(t)
+	if len(requests) != 1 || len(requests[0].Logs) != 1 || requests[0].Logs[0].Msg != "cache miss" {
+		t.Errorf("requests = %+v, want the DEBUG log exported", requests)
+	}
+}
+
+func TestMinLogLevelVarStaysLive(t *testing.T) {
+	server := newCaptureServer(t)
+	level := new(slog.LevelVar)
+	level.Set(slog.LevelWarn)
+	config := testConfig(server.URL)
+	config.MinLogLevel = level
+	sdk := newSDK(config)
+
+	sdk.Logger().Info("before lowering")
+	level.Set(slog.LevelInfo)
+	sdk.Logger().Info("after lowering")
+	sdk.SetLogLevel(slog.LevelError)
+	level.Set(slog.LevelDebug)
+	sdk.Logger().Warn("after override")
+	sdk.Shutdown(context.Background())
+
+	var msgs []string
+	for _, req := range server.decodeLogRequests(t) {
+		for _, entry := range req.Logs {
+			msgs = append(msgs, entry.Msg)
+		}
+	}
+	if len(msgs) != 1 || msgs[0] != "after lowering" {
+		t.Errorf("exported messages = %v, want only the log after the LevelVar was lowered", msgs)
+	}
+}
+
+func TestMinLogLevelKeepsLocalOutput(t *testing.T) {
+	server := newCaptureServer(t)
+	exporter := NewLogsExporter(testConfig(server.URL))
+	loggerProvider := sdklog.NewLoggerProvider(sdklog.WithProcessor(NewLumberjackLogProcessor(exporter)))
+	defer loggerProvider.Shutdown(context.Background())
+
+	local := &recordingHandler{}
+	logger := slog.New(newLumberjackSlogHandler(loggerProvider, local, slog.LevelWarn, nil))
+	logger.Info("verbose local detail")
+
+	if records := local.all(); len(records) != 1 || records[0].Message != "verbose local detail" {
+		t.Errorf("local handler records = %v, want the INFO log", records)
+	}
+	exporter.Shutdown(context.Background())
+	if requests := server.decodeLogRequests(t); len(requests) != 0 {
+		t.Errorf("INFO log below MinLogLevel was exported: %+v", requests)
+	}
+}
+
+func TestSetLogLevel(t *testing.T) {
+	server := newCaptureServer(t)
+	sdk := newSDK(testConfig(server.URL).WithMinLogLevel(slog.LevelInfo))
+
+	sdk.Logger().Debug("dropped")
+	sdk.SetLogLevel(slog.LevelDebug)
+	sdk.Logger().Debug("kept")
+	sdk.Shutdown(context.Background())
+
+	var msgs []string
+	for _, req := range server.decodeLogRequests(t) {
+		for _, entry := range req.Logs {
+			msgs = append(msgs, entry.Msg)
+		}
+	}
+	if len(msgs) != 1 || msgs[0] != "kept" {
+		t.Errorf("exported messages = %v, want only the DEBUG log sent after SetLogLevel", msgs)
+	}
+}
+
+func TestContextWithLevel(t *testing.T) {
+	server := newCaptureServer(t)
+	sdk := newSDK(testConfig(server.URL).WithMinLogLevel(slog.LevelInfo))
+
+	ctx := context.Background()
+	debugCtx := ContextWithLevel(ctx, slog.LevelDebug)
+	sdk.Logger().DebugContext(debugCtx, "suspicious request detail")
+	sdk.Logger().DebugContext(ctx, "routine detail")
+	sdk.Logger().Debug("background detail")
+	// Derived contexts keep the override
+	childCtx, cancel := context.WithCancel(debugCtx)
+	sdk.Logger().DebugContext(childCtx, "child detail")
+	cancel()
+	// Raising the level works too
+	sdk.Logger().InfoContext(ContextWithLevel(ctx, slog.LevelWarn), "quiet info")
+	sdk.Shutdown(context.Background())
+
+	var msgs []string
+	for _, req := range server.decodeLogRequests(t) {
+		for _, entry := range req.Logs {
+			msgs = append(msgs, entry.Msg)
+		}
+	}
+	if !reflect.DeepEqual(msgs, []string{"suspicious request detail", "child detail"}) {
+		t.Errorf("exported messages = %v, want only the DEBUG logs made with the overriding context", msgs)
+	}
+}
+
+type tenantKey struct{}
+
+func TestContextAttrExtractor(t *testing.T) {
+	server := newCaptureServer(t)
+	config := testConfig(server.URL).WithContextAttrExtractor(func(ctx context.Context) []slog.Attr {
+		tenant, ok := ctx.Value(tenantKey{}).(string)
+		if !ok {
+			return nil
+		}
+		return []slog.Attr{slog.String("tenant_id", tenant)}
+	})
+	sdk := newSDK(config)
+
+	ctx := context.WithValue(context.Background(), tenantKey{}, "acme")
+	sdk.Logger().InfoContext(ctx, "order placed", "order_id", "o-1")
+	sdk.Logger().Info("no tenant")
+	sdk.Shutdown(context.Background())
+
+	props := map[string]map[string]interface{}{}
+	for _, req := range server.decodeLogRequests(t) {
+		for _, entry := range req.Logs {
+			props[entry.Msg] = entry.Props
+		}
+	}
+	if got := props["order placed"]; got["tenant_id"] != "acme" || got["order_id"] != "o-1" {
+		t.Errorf("props = %v, want tenant_id from the context alongside order_id", got)
+	}
+	got, ok := props["no tenant"]
+	if !ok {
+		t.Fatal("log without a tenant in its context was not exported")
+	}
+	if _, ok := got["tenant_id"]; ok {
+		t.Errorf("props = %v, want no tenant_id when the extractor returns nil", got)
+	}
+}
+
+func TestBeforeSendLogs(t *testing.T) {
+	server := newCaptureServer(t)
+	var hookCalls int
+	config := testConfig(server.URL).WithBeforeSendLogs(func(entries []LogEntry) []LogEntry {
+		hookCalls++
+		kept := entries[:0]
+		for _, entry := range entries {
+			if entry.Lvl != "DEBUG" {
+				kept = append(kept, entry)
+			}
+		}
+		return kept
+	})
+	exporter := NewLogsExporter(config)
+	defer exporter.Shutdown(context.Background())
+
+	exporter.sendBatch(context.Background(), []LogEntry{
+		{Msg: "cache miss", Lvl: "DEBUG"},
+		{Msg: "order placed", Lvl: "INFO"},
+		{Msg: "payment failed", Lvl: "ERROR"},
+	})
+	exporter.sendBatch(context.Background(), []LogEntry{{Msg: "tick", Lvl: "DEBUG"}})
+
+	var msgs []string
+	for _, req := range server.decodeLogRequests(t) {
+		for _, entry := range req.Logs {
+			msgs = append(msgs, entry.Msg)
+		}
+	}
+	if len(msgs) != 2 || msgs[0] != "order placed" || msgs[1] != "payment failed" {
+		t.Errorf("exported messages = %v, want only the non-DEBUG entries", msgs)
+	}
+	if got := len(server.requestsFor("/logs/batch")); got != 1 {
+		t.Errorf("got %d requests, want 1: a batch the hook empties must not be sent", got)
+	}
+	if hookCalls != 2 {
+		t.Errorf("hook called %d times, want once per batch", hookCalls)
+	}
+}
+
+func TestBeforeSendLogsRunsOncePerSplitBatch(t *testing.T) {
+	server := newCaptureServer(t)
+	var hookCalls int
+	config := testConfig(server.URL).
+		WithMaxRequestBytes(1).
+		WithBeforeSendLogs(func(entries []LogEntry) []LogEntry {
+			hookCalls++
+			return entries
+		})
+	exporter := NewLogsExporter(config)
+	defer exporter.Shutdown(context.Background())
+
+	exporter.sendBatch(context.Background(), []LogEntry{{Msg: "one"}, {Msg: "two"}, {Msg: "three"}})
+	if got := len(server.requestsFor("/logs/batch")); got != 3 {
+		t.Fatalf("got %d requests, want the batch split into 3", got)
+	}
+	if hookCalls != 1 {
+		t.Errorf("hook called %d times, want once for the whole batch before splitting", hookCalls)
+	}
+}
+
+// rejectingServer answers every log batch with the indices of its entries
+// whose message starts with "bad" listed as rejected, and records the
+// messages of each batch.
+func rejectingServer(t *testing.T) (*httptest.Server, func() [][]string) {
+	t.Helper()
+	var mu sync.Mutex
+	var batches [][]string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req LogRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Errorf("decoding request: %v", err)
+		}
+		var msgs []string
+		rejected := []int{}
+		for i, entry := range req.Logs {
+			msgs = append(msgs, entry.Msg)
+			if strings.HasPrefix(entry.Msg, "bad") {
+				rejected = append(rejected, i)
+			}
+		}
+		mu.Lock()
+		batches = append(batches, msgs)
+		mu.Unlock()
+		json.NewEncoder(w).Encode(map[string][]int{"rejected": rejected})
+	}))
+	t.Cleanup(server.Close)
+	return server, func() [][]string {
+		mu.Lock()
+		defer mu.Unlock()
+		return batches
+	}
+}
+
+func TestPartialRejectionRetriesRejectedEntries(t *testing.T) {
+	server, batches := rejectingServer(t)
+	var reported []string
+	config := testConfig(server.URL).
+		WithMaxRetries(2).
+		WithOnExportError(func(signal string, count int, err error) {
+			reported = append(reported, fmt.Sprintf("%s/%d/%v", signal, count, errors.Is(err, ErrRejected)))
+		})
+	config.RetryBackoff = time.Millisecond
+	exporter := NewLogsExporter(config)
+	defer exporter.Shutdown(context.Background())
+
+	exporter.sendBatch(context.Background(), []LogEntry{
+		{Msg: "order placed"},
+		{Msg: "bad payload"},
+		{Msg: "order shipped"},
+	})
+
+	want := [][]string{
+		{"order placed", "bad payload", "order shipped"},
+		{"bad payload"},
+		{"bad payload"},
+	}
+	if got := batches(); !reflect.DeepEqual(got, want) {
+		t.Errorf("batches = %v, want only the rejected entry retried: %v", got, want)
+	}
+	if len(reported) != 1 || reported[0] != "logs/1/true" {
+		t.Errorf("OnExportError calls = %v, want one for the dropped rejected entry", reported)
+	}
+	stats := exporter.Stats()
+	if stats.Flushed != 2 || stats.Retries != 2 {
+		t.Errorf("Flushed/Retries = %d/%d, want 2/2", stats.Flushed, stats.Retries)
+	}
+}
+
+func TestPartialRejectionAcceptedOnRetry(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) == 1 {
+			w.Write([]byte(`{"rejected":[0]}`))
+		}
+	}))
+	defer server.Close()
+	var reported int
+	config := testConfig(server.URL).WithOnExportError(func(string, int, error) { reported++ })
+	config.RetryBackoff = time.Millisecond
+	exporter := NewLogsExporter(config)
+	defer exporter.Shutdown(context.Background())
+
+	exporter.sendBatch(context.Background(), []LogEntry{{Msg: "flaky"}, {Msg: "fine"}})
+
+	if got := calls.Load(); got != 2 {
+		t.Errorf("got %d requests, want the rejected entry re-sent once", got)
+	}
+	if reported != 0 {
+		t.Errorf("OnExportError called %d times, want none once the retry succeeds", reported)
+	}
+	if got := exporter.Stats().Flushed; got != 2 {
+		t.Errorf("Flushed = %d, want 2", got)
+	}
+}
+
+func TestSpoolReplayHandlesPartialRejection(t *testing.T) {
+	server, batches := rejectingServer(t)
+	var reported []string
+	config := testConfig(server.URL).
+		WithOnExportError(func(signal string, count int, err error) {
+			reported = append(reported, fmt.Sprintf("%s/%d/%v", signal, count, errors.Is(err, ErrRejected)))
+		})
+	exporter := NewLogsExporter(config)
+	defer exporter.Shutdown(context.Background())
+
+	data, err := json.Marshal(LogRequest{Logs: []LogEntry{{Msg: "order placed"}, {Msg: "bad payload"}}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := exporter.replaySpooled(data); err != nil {
+		t.Errorf("replaySpooled() = %v, want nil so the spooled line is removed", err)
+	}
+
+	if got := len(batches()); got != 1 {
+		t.Errorf("got %d requests, want the spooled request sent once", got)
+	}
+	if len(reported) != 1 || reported[0] != "logs/1/true" {
+		t.Errorf("OnExportError calls = %v, want one for the rejected entry", reported)
+	}
+	if got := exporter.Stats().Flushed; got != 1 {
+		t.Errorf("Flushed = %d, want the accepted entry counted", got)
+	}
+}
@@ -7,11 +7,18 @@ import (
 	
 	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/metric"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
 )
 
 type Metrics struct {
 	meter metric.Meter
 	
+	// semconv selects the http.request.method, http.route and
+	// http.response.status_code request attributes over the legacy method,
+	// path and status_code ones. They only exist from semconv v1.21.0, hence
+	// v1.26.0 here while the rest of the package uses v1.17.0
+	semconv bool
+	
 	requestCounter    metric.Int64Counter
 	requestDuration   metric.Float64Histogram
 	activeRequests    metric.Int64UpDownCounter
@@ -92,6 +99,13 @@ func (m *Metrics) RecordRequest(ctx context.Context, method, path string, status
 		attribute.String("path", path),
 		attribute.Int("status_code", statusCode),
 	}
+	if m.semconv {
+		attrs = []attribute.KeyValue{
+			semconv.HTTPRequestMethodKey.String(method),
+			semconv.HTTPRoute(path),
+			semconv.HTTPResponseStatusCode(statusCode),
+		}
+	}
 	
 	m.requestCounter.Add(ctx, 1, metric.WithAttributes(attrs...))
 	m.requestDuration.Record(ctx, duration.Seconds(), metric.WithAttributes(attrs...))
@@ -5,9 +5,9 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
-	"math/rand"
+	"io"
+	"math"
 	"net/http"
-	"os"
 	"sync"
 	"time"
 	
@@ -36,18 +36,54 @@ type HistogramValue struct {
 	Buckets []Bucket  `json:"buckets,omitempty"`
 }
 
-// Bucket represents a histogram bucket
+// Bucket represents a histogram bucket. The overflow bucket has an UpperBound
+// of +Inf, which is encoded as the string "+Inf" since JSON has no infinity.
 type Bucket struct {
 	UpperBound float64 `json:"upper_bound"`
 	Count      uint64  `json:"count"`
 }
 
+const infBound = "+Inf"
+
+type jsonBucket struct {
+	UpperBound interface{} `json:"upper_bound"`
+	Count      uint64      `json:"count"`
+}
+
+func (b Bucket) MarshalJSON() ([]byte, error) {
+	out := jsonBucket{UpperBound: b.UpperBound, Count: b.Count}
+	if math.IsInf(b.UpperBound, 1) {
+		out.UpperBound = infBound
+	}
+	return json.Marshal(out)
+}
+
+func (b *Bucket) UnmarshalJSON(data []byte) error {
+	var in jsonBucket
+	if err := json.Unmarshal(data, &in); err != nil {
+		return err
+	}
+	switch bound := in.UpperBound.(type) {
+	case float64:
+		b.UpperBound = bound
+	case string:
+		if bound != infBound {
+			return fmt.Errorf("invalid bucket upper bound %q", bound)
+		}
+		b.UpperBound = math.Inf(1)
+	}
+	b.Count = in.Count
+	return nil
+}
+
 // MetricsBatchRequest represents the payload sent to /metrics/batch
 type MetricsBatchRequest struct {
-	Type    string               `json:"type"`
-	Env     string               `json:"env"`
-	Ts      int64                `json:"ts"`
-	Payload MetricsBatchPayload  `json:"payload"`
+	Type       string              `json:"type"`
+	Env        string              `json:"env"`
+	Ts         int64               `json:"ts"`
+	SdkVersion int                 `json:"sdk_version"`
+	SdkSemver  string              `json:"sdk_semver,omitempty"`
+	Payload    MetricsBatchPayload `json:"payload"`
 }
 
 type MetricsBatchPayload struct {
@@ -60,50 +96,135 @@ type MetricsBatchPayload struct {
 type MetricsExporter struct {
 	config      *Config
 	client      *http.Client
+	apiKey      *apiKeySource
+	breaker     *circuitBreaker
 	batch       []MetricPoint
+	spare       []MetricPoint
+	batchBytes  int
 	batchMu     sync.Mutex
 	stopCh      chan struct{}
 	wg          sync.WaitGroup
 	flushTicker *time.Ticker
+	ageTimer    *time.Timer
+	lastFlush   time.Time
+	stats       exporterStats
+	spool       *spool
+	
+	releaseID   string
+	releaseType string
+	
+	series *seriesLimiter
 }
 
 func NewMetricsExporter(config *Config) *MetricsExporter {
+	return newMetricsExporter(config, newCircuitBreaker(config))
+}
+
+// newMetricsExporter creates the exporter with a circuit breaker that may be shared
+// with the SDK's other exporters.
+func newMetricsExporter(config *Config, breaker *circuitBreaker) *MetricsExporter {
 	exporter := &MetricsExporter{
-		config: config,
-		client: &http.Client{
-			Timeout: 30 * time.Second,
-		},
-		batch:  make([]MetricPoint, 0, config.BatchSize),
-		stopCh: make(chan struct{}),
+		config:  config,
+		breaker: breaker,
+		client:  newHTTPClient(config),
+		apiKey:  newAPIKeySource(config),
+		batch:   make([]MetricPoint, 0, config.BatchSize),
+		stopCh:  make(chan struct{}),
+
+		lastFlush: time.Now(),
+		
+		releaseID:   config.releaseID(),
+		releaseType: config.releaseType(),
+		
+		series: newSeriesLimiter(config),
 	}
 	
 	exporter.flushTicker = time.NewTicker(config.BatchTimeout)
 	exporter.wg.Add(1)
 	go exporter.runFlusher()
 	
+	if exporter.spool = newExporterSpool(config, "metrics"); exporter.spool != nil {
+		exporter.wg.Add(1)
+		go func() {
+			defer exporter.wg.Done()
+			exporter.spool.run(config.SpoolReplayInterval, exporter.stopCh, func(data []byte) error {
+				return exporter.sendWithRetry(context.Background(), data)
+			})
+		}()
+	}
+	
 	return exporter
 }
 
 func (e *MetricsExporter) Temporality(kind metric.InstrumentKind) metricdata.Temporality {
-	return metricdata.CumulativeTemporality
+	if e.config.MetricTemporality != metricdata.DeltaTemporality {
+		return metricdata.CumulativeTemporality
+	}
+	switch kind {
+	case metric.InstrumentKindUpDownCounter, metric.InstrumentKindObservableUpDownCounter,
+		metric.InstrumentKindGauge, metric.InstrumentKindObservableGauge:
+		// These track a current value, so deltas are not useful
+		return metricdata.CumulativeTemporality
+	default:
+		return metricdata.DeltaTemporality
+	}
 }
 
 func (e *MetricsExporter) Aggregation(kind metric.InstrumentKind) metric.Aggregation {
+	if kind == metric.InstrumentKindHistogram && len(e.config.HistogramBoundaries) > 0 {
+		return metric.AggregationExplicitBucketHistogram{
+			Boundaries: e.config.HistogramBoundaries,
+		}
+	}
 	return metric.DefaultAggregationSelector(kind)
 }
 
+// histogramViews returns one view per instrument listed in
+// Config.HistogramBoundariesByName, since the aggregation selector only sees
+// the instrument kind.
+func histogramViews(config *Config) []metric.View {
+	views := make([]metric.View, 0, len(config.HistogramBoundariesByName))
+	for name, boundaries := range config.HistogramBoundariesByName {
+		views = append(views, metric.NewView(
+			metric.Instrument{Name: name, Kind: metric.InstrumentKindHistogram},
+			metric.Stream{Aggregation: metric.AggregationExplicitBucketHistogram{Boundaries: boundaries}},
+		))
+	}
+	return views
+}
+
 func (e *MetricsExporter) Export(ctx context.Context, rm *metricdata.ResourceMetrics) error {
 	for _, sm := range rm.ScopeMetrics {
 		for _, m := range sm.Metrics {
 			points := e.convertMetric(m)
 			
 			e.batchMu.Lock()
-			e.batch = append(e.batch, points...)
-			shouldFlush := len(e.batch) >= e.config.BatchSize
+			for queueFull(e.config, len(e.batch), len(points)) {
+				e.batchMu.Unlock()
+				e.flush(exportContext(ctx, e.config))
+				e.batchMu.Lock()
+			}
+			evicted, accepted := applyOverflowPolicy(e.config, e.batch, points)
+			if dropped := evicted + len(points) - len(accepted); dropped > 0 {
+				e.stats.dropped.Add(uint64(dropped))
+				for _, point := range e.batch[:evicted] {
+					e.batchBytes -= point.estimatedSize()
+				}
+				e.batch = append(e.batch[:0], e.batch[evicted:]...)
+			}
+			if len(accepted) > 0 {
+				e.armAgeTimerLocked()
+			}
+			e.batch = append(e.batch, accepted...)
+			e.stats.enqueued.Add(uint64(len(accepted)))
+			for _, point := range accepted {
+				e.batchBytes += point.estimatedSize()
+			}
+			shouldFlush := batchFull(e.config, len(e.batch), e.batchBytes) || batchStale(e.config, e.lastFlush)
 			e.batchMu.Unlock()
 			
 			if shouldFlush {
-				e.flush()
+				e.flush(exportContext(ctx, e.config))
 			}
 		}
 	}
@@ -116,7 +237,7 @@ func (e *MetricsExporter) convertMetric(m metricdata.Metrics) []MetricPoint {
 	
 	switch data := m.Data.(type) {
 	case metricdata.Gauge[int64]:
-		for _, dp := range data.DataPoints {
+		for _, dp := range limitDataPoints(e.series, m.Name, data.DataPoints, true) {
 			points = append(points, MetricPoint{
 				Name:        m.Name,
 				Type:        "gauge",
@@ -129,7 +250,7 @@ func (e *MetricsExporter) convertMetric(m metricdata.Metrics) []MetricPoint {
 		}
 		
 	case metricdata.Gauge[float64]:
-		for _, dp := range data.DataPoints {
+		for _, dp := range limitDataPoints(e.series, m.Name, data.DataPoints, true) {
 			points = append(points, MetricPoint{
 				Name:        m.Name,
 				Type:        "gauge", 
@@ -142,7 +263,7 @@ func (e *MetricsExporter) convertMetric(m metricdata.Metrics) []MetricPoint {
 		}
 		
 	case metricdata.Sum[int64]:
-		for _, dp := range data.DataPoints {
+		for _, dp := range limitDataPoints(e.series, m.Name, data.DataPoints, false) {
 			points = append(points, MetricPoint{
 				Name:        m.Name,
 				Type:        "counter",
@@ -155,7 +276,7 @@ func (e *MetricsExporter) convertMetric(m metricdata.Metrics) []MetricPoint {
 		}
 		
 	case metricdata.Sum[float64]:
-		for _, dp := range data.DataPoints {
+		for _, dp := range limitDataPoints(e.series, m.Name, data.DataPoints, false) {
 			points = append(points, MetricPoint{
 				Name:        m.Name,
 				Type:        "counter",
@@ -168,23 +289,11 @@ func (e *MetricsExporter) convertMetric(m metricdata.Metrics) []MetricPoint {
 		}
 		
 	case metricdata.Histogram[int64]:
-		for _, dp := range data.DataPoints {
-			buckets := make([]Bucket, len(dp.Bounds))
-			for i, bound := range dp.Bounds {
-				count := uint64(0)
-				if i < len(dp.BucketCounts) {
-					count = dp.BucketCounts[i]
-				}
-				buckets[i] = Bucket{
-					UpperBound: bound,
-					Count:      count,
-				}
-			}
-			
+		for _, dp := range limitHistogramPoints(e.series, m.Name, data.DataPoints) {
 			histValue := HistogramValue{
 				Count:   dp.Count,
 				Sum:     float64(dp.Sum),
-				Buckets: buckets,
+				Buckets: convertBuckets(dp.Bounds, dp.BucketCounts),
 			}
 			
 			if min, hasMin := dp.Min.Value(); hasMin {
@@ -208,23 +317,11 @@ func (e *MetricsExporter) convertMetric(m metricdata.Metrics) []MetricPoint {
 		}
 		
 	case metricdata.Histogram[float64]:
-		for _, dp := range data.DataPoints {
-			buckets := make([]Bucket, len(dp.Bounds))
-			for i, bound := range dp.Bounds {
-				count := uint64(0)
-				if i < len(dp.BucketCounts) {
-					count = dp.BucketCounts[i]
-				}
-				buckets[i] = Bucket{
-					UpperBound: bound,
-					Count:      count,
-				}
-			}
-			
+		for _, dp := range limitHistogramPoints(e.series, m.Name, data.DataPoints) {
 			histValue := HistogramValue{
 				Count:   dp.Count,
 				Sum:     dp.Sum,
-				Buckets: buckets,
+				Buckets: convertBuckets(dp.Bounds, dp.BucketCounts),
 			}
 			
 			if min, hasMin := dp.Min.Value(); hasMin {
@@ -249,10 +346,44 @@ func (e *MetricsExporter) convertMetric(m metricdata.Metrics) []MetricPoint {
 	return points
 }
 
+// convertBuckets pairs each bound with its count. OTEL reports one more count
+// than bounds; the last is the overflow bucket, given an upper bound of +Inf.
+func convertBuckets(bounds []float64, counts []uint64) []Bucket {
+	buckets := make([]Bucket, 0, len(bounds)+1)
+	for i, bound := range bounds {
+		count := uint64(0)
+		if i < len(counts) {
+			count = counts[i]
+		}
+		buckets = append(buckets, Bucket{
+			UpperBound: bound,
+			Count:      count,
+		})
+	}
+	if len(counts) > len(bounds) {
+		buckets = append(buckets, Bucket{
+			UpperBound: math.Inf(1),
+			Count:      counts[len(bounds)],
+		})
+	}
+	return buckets
+}
+
+// estimatedSize cheaply approximates the encoded size of the point.
+func (p MetricPoint) estimatedSize() int {
+	size := entryOverheadBytes + len(p.Name) + len(p.Unit) + len(p.Description) + estimateStringMapSize(p.Attributes)
+	if hist, ok := p.Value.(HistogramValue); ok {
+		size += len(hist.Buckets) * 32
+	}
+	return size
+}
+
+// convertAttributes renders attrs as strings; Emit rather than AsString, so
+// that non-string values such as status codes aren't exported empty.
 func convertAttributes(attrs attribute.Set) map[string]string {
 	result := make(map[string]string)
 	for _, kv := range attrs.ToSlice() {
-		result[string(kv.Key)] = kv.Value.AsString()
+		result[string(kv.Key)] = kv.Value.Emit()
 	}
 	return result
 }
@@ -263,91 +394,191 @@ func (e *MetricsExporter) runFlusher() {
 	for {
 		select {
 		case <-e.flushTicker.C:
-			e.flush()
+			e.flush(context.Background())
 		case <-e.stopCh:
 			return
 		}
 	}
 }
 
-func (e *MetricsExporter) flush() {
+func (e *MetricsExporter) flush(ctx context.Context) {
 	e.batchMu.Lock()
+	if e.ageTimer != nil {
+		e.ageTimer.Stop()
+		e.ageTimer = nil
+	}
+	e.lastFlush = time.Now()
+	resetFlushTicker(e.flushTicker, e.config, e.stopCh)
 	if len(e.batch) == 0 {
 		e.batchMu.Unlock()
 		return
 	}
 	
-	metrics := make([]MetricPoint, len(e.batch))
-	copy(metrics, e.batch)
-	e.batch = e.batch[:0]
+	// Swap in the spare buffer rather than copying, so Export callers only
+	// wait for a slice swap
+	metrics := e.batch
+	e.batch = e.spare
+	if e.batch == nil {
+		e.batch = make([]MetricPoint, 0, e.config.BatchSize)
+	}
+	e.spare = nil
+	e.batchBytes = 0
 	e.batchMu.Unlock()
 	
-	e.sendBatch(metrics)
+	e.sendBatch(ctx, metrics)
+	
+	// Drop references to the sent entries and keep the buffer for next time
+	clear(metrics)
+	e.batchMu.Lock()
+	if e.spare == nil {
+		e.spare = metrics[:0]
+	}
+	e.batchMu.Unlock()
 }
 
-func (e *MetricsExporter) sendBatch(metrics []MetricPoint) {
-	env := "production"
-	if e.config.Debug {
-		env = "development"
+// armAgeTimerLocked schedules a flush once the oldest buffered entry reaches
+// MaxBufferAge. Must be called with batchMu held.
+func (e *MetricsExporter) armAgeTimerLocked() {
+	if e.config.MaxBufferAge > 0 && e.ageTimer == nil {
+		e.ageTimer = time.AfterFunc(e.config.MaxBufferAge, func() {
+			e.flush(context.Background())
+		})
 	}
-	
-	payload := MetricsBatchPayload{
-		Metrics: metrics,
+}
+
+func (e *MetricsExporter) sendBatch(ctx context.Context, metrics []MetricPoint) {
+	if e.config.BeforeSendMetrics != nil {
+		if metrics = e.config.BeforeSendMetrics(metrics); len(metrics) == 0 {
+			return
+		}
 	}
-	
-	if releaseId := os.Getenv("LUMBERJACK_RELEASE_ID"); releaseId != "" {
-		payload.ReleaseId = releaseId
+	for _, part := range splitBatch(e.config, metrics) {
+		e.sendRequest(ctx, part)
 	}
+}
+
+// sendRequest sends metrics in a single request, spooling them if it fails.
+func (e *MetricsExporter) sendRequest(ctx context.Context, metrics []MetricPoint) {
+	env := e.config.environment()
 	
-	if releaseType := os.Getenv("LUMBERJACK_RELEASE_TYPE"); releaseType != "" {
-		payload.ReleaseType = releaseType
+	payload := MetricsBatchPayload{
+		Metrics:     metrics,
+		ReleaseId:   e.releaseID,
+		ReleaseType: e.releaseType,
 	}
 	
 	request := MetricsBatchRequest{
-		Type:    "metric_batch",
-		Env:     env,
-		Ts:      time.Now().UnixMilli(),
-		Payload: payload,
+		Type:       "metric_batch",
+		Env:        env,
+		Ts:         time.Now().UnixMilli(),
+		SdkVersion: sdkVersion,
+		SdkSemver:  sdkRelease(),
+		Payload:    payload,
+	}
+	
+	var data []byte
+	var err error
+	if streamBatch(e.config, metrics) {
+		// Encode entry by entry into the request body instead of holding the
+		// whole payload in memory.
+		head := request
+		head.Payload.Metrics = []MetricPoint{}
+		err = e.send(ctx, func() (io.ReadCloser, error) {
+			return streamJSONArray(head, "metrics", metrics)
+		})
+	} else {
+		buf, encErr := encodeJSON(request)
+		if encErr != nil {
+			e.config.debugf("Failed to marshal metrics: %v\n", encErr)
+			e.stats.dropped.Add(uint64(len(metrics)))
+			return
+		}
+		defer putJSONBuffer(buf)
+		data = buf.Bytes()
+		err = e.sendWithRetry(ctx, data)
 	}
 	
-	data, err := json.Marshal(request)
 	if err != nil {
-		if e.config.Debug {
-			fmt.Printf("Failed to marshal metrics: %v\n", err)
+		e.config.reportExportError("metrics", len(metrics), err)
+		spooled := false
+		if data != nil {
+			spooled = e.spool.storeFailed(e.config, "metrics", data, err)
+		} else {
+			spooled = e.spool.storeFailedJSON(e.config, "metrics", request, err)
+		}
+		if !spooled {
+			e.stats.dropped.Add(uint64(len(metrics)))
 		}
 		return
 	}
-	
-	e.sendWithRetry(data)
+	e.stats.recordFlushed(len(metrics))
+	e.config.debugf("Successfully sent %d metrics\n", len(metrics))
+}
+
+func (e *MetricsExporter) sendWithRetry(ctx context.Context, data []byte) error {
+	return e.send(ctx, func() (io.ReadCloser, error) {
+		return io.NopCloser(bytes.NewReader(data)), nil
+	})
+}
+
+// send POSTs the body returned by body unless the circuit breaker is open,
+// and records the outcome with the breaker.
+func (e *MetricsExporter) send(ctx context.Context, body func() (io.ReadCloser, error)) error {
+	if !e.breaker.allow() {
+		e.config.debugf("Circuit breaker open, not sending metrics\n")
+		return errCircuitOpen
+	}
+	err := e.postWithRetry(ctx, body)
+	e.breaker.record(err)
+	return err
 }
 
-func (e *MetricsExporter) sendWithRetry(data []byte) {
+// postWithRetry POSTs the body returned by body, calling it again for every
+// attempt.
+func (e *MetricsExporter) postWithRetry(ctx context.Context, body func() (io.ReadCloser, error)) error {
 	url := fmt.Sprintf("%s/metrics/batch", e.config.BaseURL)
 	retries := 0
 	backoff := e.config.RetryBackoff
+	start := time.Now()
 	
 	for retries <= e.config.MaxRetries {
-		req, err := http.NewRequest("POST", url, bytes.NewBuffer(data))
+		reqBody, err := body()
 		if err != nil {
-			if e.config.Debug {
-				fmt.Printf("Failed to create metrics request: %v\n", err)
-			}
-			return
+			return err
+		}
+		req, err := http.NewRequestWithContext(ctx, "POST", url, reqBody)
+		if err != nil {
+			reqBody.Close()
+			e.config.debugf("Failed to create metrics request: %v\n", err)
+			return err
 		}
 		
 		req.Header.Set("Content-Type", "application/json")
-		req.Header.Set("Authorization", "Bearer "+e.config.APIKey)
+		req.Header.Set("User-Agent", e.config.userAgent())
+		if err := e.apiKey.setHeader(req); err != nil {
+			reqBody.Close()
+			e.config.debugf("Failed to authenticate request: %v\n", err)
+			return err
+		}
 		
 		resp, err := e.client.Do(req)
 		if err != nil {
-			if e.config.Debug {
-				fmt.Printf("Failed to send metrics (attempt %d): %v\n", retries+1, err)
+			if ctx.Err() != nil {
+				e.config.debugf("Aborted sending metrics: %v\n", ctx.Err())
+				return ctx.Err()
 			}
+			e.config.debugf("Failed to send metrics (attempt %d): %v\n", retries+1, err)
 			retries++
 			if retries <= e.config.MaxRetries {
-				jitter := time.Duration(rand.Float64() * float64(backoff))
-				time.Sleep(backoff + jitter)
-				backoff *= 2
+				delay := jitteredBackoff(e.config, backoff)
+				if retryBudgetExceeded(e.config, start, delay) {
+					break
+				}
+				e.stats.retries.Add(1)
+				if err := sleepDelay(ctx, delay); err != nil {
+					return err
+				}
+				backoff = nextBackoff(e.config, backoff)
 			}
 			continue
 		}
@@ -355,37 +586,45 @@ func (e *MetricsExporter) sendWithRetry(data []byte) {
 		resp.Body.Close()
 		
 		if resp.StatusCode == http.StatusOK {
-			if e.config.Debug {
-				var request MetricsBatchRequest
-				json.Unmarshal(data, &request)
-				fmt.Printf("Successfully sent %d metrics\n", len(request.Payload.Metrics))
-			}
-			return
+			return nil
 		}
 		
-		if e.config.Debug {
-			fmt.Printf("Failed to send metrics, status: %d\n", resp.StatusCode)
-		}
+		e.config.debugf("Failed to send metrics, status: %d\n", resp.StatusCode)
 		
 		if resp.StatusCode >= 500 {
 			retries++
 			if retries <= e.config.MaxRetries {
-				jitter := time.Duration(rand.Float64() * float64(backoff))
-				time.Sleep(backoff + jitter)
-				backoff *= 2
+				delay := jitteredBackoff(e.config, backoff)
+				if retryBudgetExceeded(e.config, start, delay) {
+					break
+				}
+				e.stats.retries.Add(1)
+				if err := sleepDelay(ctx, delay); err != nil {
+					return err
+				}
+				backoff = nextBackoff(e.config, backoff)
 			}
 		} else {
-			break
+			return fmt.Errorf("%w: status %d", errPermanent, resp.StatusCode)
 		}
 	}
 	
-	if e.config.Debug && retries > e.config.MaxRetries {
-		fmt.Printf("Max retries exceeded for metrics batch\n")
+	if retries <= e.config.MaxRetries {
+		e.config.debugf("Retry time limit exceeded for metrics batch\n")
+		return fmt.Errorf("retry time limit of %v exceeded for metrics batch", e.config.MaxRetryElapsed)
 	}
+	
+	e.config.debugf("Max retries exceeded for metrics batch\n")
+	return fmt.Errorf("max retries exceeded for metrics batch")
+}
+
+// Stats returns a snapshot of the exporter's counters.
+func (e *MetricsExporter) Stats() ExporterStats {
+	return e.stats.snapshot()
 }
 
 func (e *MetricsExporter) ForceFlush(ctx context.Context) error {
-	e.flush()
+	e.flush(exportContext(ctx, e.config))
 	return nil
 }
 
@@ -399,7 +638,7 @@ func (e *MetricsExporter) Shutdown(ctx context.Context) error {
 	}
 	
 	e.flushTicker.Stop()
-	e.flush()
+	e.flush(ctx)
 	
 	done := make(chan struct{})
 	go func() {
@@ -0,0 +1,324 @@
+package lumberjack
+
+import (
+	"context"
+	"encoding/json"
+	"math"
+	"net/http"
+	"reflect"
+	"testing"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	otelmetric "go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+)
+
+// collectMetrics forces a collection through the SDK's default metrics
+// exporter and returns every point the stub server received.
+func collectMetrics(t *testing.T, sdk *SDK, server *captureServer) []MetricPoint {
+	t.Helper()
+	ctx := context.Background()
+	if err := sdk.meterProvider.ForceFlush(ctx); err != nil {
+		t.Fatalf("ForceFlush() unexpected error = %v", err)
+	}
+	sdk.defaultMetricsExporter.ForceFlush(ctx)
+
+	var points []MetricPoint
+	for _, r := range server.requestsFor("/metrics/batch") {
+		var req MetricsBatchRequest
+		if err := json.Unmarshal(r.Body, &req); err != nil {
+			t.Fatalf("failed to decode metrics request: %v", err)
+		}
+		points = append(points, req.Payload.Metrics...)
+	}
+	return points
+}
+
+func findMetric(points []MetricPoint, name string) *MetricPoint {
+	for i := range points {
+		if points[i].Name == name {
+			return &points[i]
+		}
+	}
+	return nil
+}
+
+// findLastMetric returns the most recently exported point named name.
+func findLastMetric(points []MetricPoint, name string) *MetricPoint {
+	for i := len(points) - 1; i >= 0; i-- {
+		if points[i].Name == name {
+			return &points[i]
+		}
+	}
+	return nil
+}
+
+func TestRegisterGauge(t *testing.T) {
+	server := newCaptureServer(t)
+	sdk := newSDK(testConfig(server.URL))
+	defer sdk.Shutdown(context.Background())
+
+	err := sdk.RegisterGauge("queue.depth", "1", "Jobs waiting in the queue", func() float64 {
+		return 42.5
+	})
+	if err != nil {
+		t.Fatalf("RegisterGauge() unexpected error = %v", err)
+	}
+
+	point := findMetric(collectMetrics(t, sdk, server), "queue.depth")
+	if point == nil {
+		t.Fatalf("gauge queue.depth was not exported")
+	}
+	if point.Type != "gauge" || point.Value != 42.5 {
+		t.Errorf("exported gauge = %+v, want type gauge with value 42.5", point)
+	}
+	if point.Unit != "1" || point.Description != "Jobs waiting in the queue" {
+		t.Errorf("exported gauge unit/description = %q/%q", point.Unit, point.Description)
+	}
+}
+
+func TestDeltaTemporality(t *testing.T) {
+	server := newCaptureServer(t)
+	sdk := newSDK(testConfig(server.URL).WithMetricTemporality(metricdata.DeltaTemporality))
+	defer sdk.Shutdown(context.Background())
+
+	counter, err := sdk.Meter().Int64Counter("jobs.processed")
+	if err != nil {
+		t.Fatalf("Int64Counter() unexpected error = %v", err)
+	}
+
+	var got []any
+	for _, n := range []int64{5, 3} {
+		counter.Add(context.Background(), n)
+		points := collectMetrics(t, sdk, server)
+		point := findLastMetric(points, "jobs.processed")
+		if point == nil {
+			t.Fatalf("counter jobs.processed was not exported")
+		}
+		got = append(got, point.Value)
+	}
+
+	// JSON numbers decode as float64
+	if got[0] != 5.0 || got[1] != 3.0 {
+		t.Errorf("successive counter values = %v, want per-interval increments [5 3]", got)
+	}
+}
+
+func TestTemporalitySelector(t *testing.T) {
+	exporter := NewMetricsExporter(testConfig("http://127.0.0.1:0").WithMetricTemporality(metricdata.DeltaTemporality))
+	defer exporter.Shutdown(context.Background())
+
+	tests := map[metric.InstrumentKind]metricdata.Temporality{
+		metric.InstrumentKindCounter:           metricdata.DeltaTemporality,
+		metric.InstrumentKindHistogram:         metricdata.DeltaTemporality,
+		metric.InstrumentKindObservableCounter: metricdata.DeltaTemporality,
+		metric.InstrumentKindUpDownCounter:     metricdata.CumulativeTemporality,
+		metric.InstrumentKindObservableGauge:   metricdata.CumulativeTemporality,
+	}
+	for kind, want := range tests {
+		if got := exporter.Temporality(kind); got != want {
+			t.Errorf("Temporality(%v) = %v, want %v", kind, got, want)
+		}
+	}
+}
+
+func TestHistogramBoundaries(t *testing.T) {
+	server := newCaptureServer(t)
+	config := testConfig(server.URL).
+		WithHistogramBoundaries(0.1, 0.5, 1).
+		WithInstrumentHistogramBoundaries("db.latency", 0.001, 0.01)
+	sdk := newSDK(config)
+	defer sdk.Shutdown(context.Background())
+
+	requestLatency, _ := sdk.Meter().Float64Histogram("request.latency")
+	dbLatency, _ := sdk.Meter().Float64Histogram("db.latency")
+	requestLatency.Record(context.Background(), 0.3)
+	dbLatency.Record(context.Background(), 0.005)
+
+	points := collectMetrics(t, sdk, server)
+	tests := map[string][]float64{
+		"request.latency": {0.1, 0.5, 1, math.Inf(1)},
+		"db.latency":      {0.001, 0.01, math.Inf(1)},
+	}
+	for name, want := range tests {
+		point := findMetric(points, name)
+		if point == nil {
+			t.Fatalf("histogram %s was not exported", name)
+		}
+		// Values round-trip through JSON as maps
+		raw, _ := json.Marshal(point.Value)
+		var hist HistogramValue
+		json.Unmarshal(raw, &hist)
+
+		var bounds []float64
+		for _, b := range hist.Buckets {
+			bounds = append(bounds, b.UpperBound)
+		}
+		if len(bounds) != len(want) {
+			t.Fatalf("%s bucket bounds = %v, want %v", name, bounds, want)
+		}
+		for i := range want {
+			if bounds[i] != want[i] {
+				t.Errorf("%s bucket bounds = %v, want %v", name, bounds, want)
+				break
+			}
+		}
+	}
+}
+
+func TestHistogramOverflowBucket(t *testing.T) {
+	exporter := NewMetricsExporter(testConfig("http://127.0.0.1:0"))
+	defer exporter.Shutdown(context.Background())
+
+	now := time.Now()
+	metrics := []metricdata.Metrics{
+		{
+			Name: "int.histogram",
+			Data: metricdata.Histogram[int64]{DataPoints: []metricdata.HistogramDataPoint[int64]{{
+				Time: now, Count: 6, Sum: 1234, Bounds: []float64{10, 100}, BucketCounts: []uint64{1, 2, 3},
+			}}},
+		},
+		{
+			Name: "float.histogram",
+			Data: metricdata.Histogram[float64]{DataPoints: []metricdata.HistogramDataPoint[float64]{{
+				Time: now, Count: 4, Sum: 12.5, Bounds: []float64{1}, BucketCounts: []uint64{1, 3},
+			}}},
+		},
+	}
+
+	for _, m := range metrics {
+		t.Run(m.Name, func(t *testing.T) {
+			points := exporter.convertMetric(m)
+			if len(points) != 1 {
+				t.Fatalf("expected 1 point, got %d", len(points))
+			}
+
+			// Round trip through JSON to check the +Inf bound survives encoding
+			data, err := json.Marshal(points[0])
+			if err != nil {
+				t.Fatalf("json.Marshal() unexpected error = %v", err)
+			}
+			var decoded struct {
+				Value HistogramValue `json:"value"`
+			}
+			if err := json.Unmarshal(data, &decoded); err != nil {
+				t.Fatalf("json.Unmarshal() unexpected error = %v", err)
+			}
+
+			hist := decoded.Value
+			var total uint64
+			for _, b := range hist.Buckets {
+				total += b.Count
+			}
+			if total != hist.Count {
+				t.Errorf("bucket counts sum to %d, want Count %d", total, hist.Count)
+			}
+			last := hist.Buckets[len(hist.Buckets)-1]
+			if !math.IsInf(last.UpperBound, 1) {
+				t.Errorf("last bucket upper bound = %v, want +Inf", last.UpperBound)
+			}
+		})
+	}
+}
+
+func TestMaxMetricSeries(t *testing.T) {
+	server := newCaptureServer(t)
+	sdk := newSDK(testConfig(server.URL).WithMaxMetricSeries(5))
+	defer sdk.Shutdown(context.Background())
+
+	counter, err := sdk.Meter().Int64Counter("requests.by_user")
+	if err != nil {
+		t.Fatalf("Int64Counter() unexpected error = %v", err)
+	}
+	for i := 0; i < 50; i++ {
+		counter.Add(context.Background(), 1, otelmetric.WithAttributes(attribute.Int("user_id", i)))
+	}
+
+	var series int
+	var total, overflow float64
+	for _, point := range collectMetrics(t, sdk, server) {
+		if point.Name != "requests.by_user" {
+			continue
+		}
+		series++
+		total += point.Value.(float64)
+		if point.Attributes["overflow"] == "true" {
+			overflow += point.Value.(float64)
+		}
+	}
+	if series != 6 {
+		t.Errorf("exported %d series, want 5 plus one overflow series", series)
+	}
+	if total != 50 || overflow != 45 {
+		t.Errorf("total/overflow values = %v/%v, want 50/45: overflowing points must be merged, not dropped", total, overflow)
+	}
+}
+
+func TestRecordRequestAttributes(t *testing.T) {
+	tests := []struct {
+		name    string
+		semconv bool
+		want    map[string]string
+	}{
+		{
+			name: "legacy by default",
+			want: map[string]string{"method": "GET", "path": "/items/{id}", "status_code": "404"},
+		},
+		{
+			name:    "semantic conventions",
+			semconv: true,
+			want: map[string]string{
+				"http.request.method":       "GET",
+				"http.route":                "/items/{id}",
+				"http.response.status_code": "404",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := newCaptureServer(t)
+			sdk := newSDK(testConfig(server.URL).WithSemconvHTTPMetrics(tt.semconv))
+			defer sdk.Shutdown(context.Background())
+
+			sdk.Metrics().RecordRequest(context.Background(), "GET", "/items/{id}", 404, time.Millisecond)
+			point := findLastMetric(collectMetrics(t, sdk, server), "lumberjack.requests")
+			if point == nil {
+				t.Fatal("lumberjack.requests was not exported")
+			}
+			if !reflect.DeepEqual(point.Attributes, tt.want) {
+				t.Errorf("attributes = %v, want %v", point.Attributes, tt.want)
+			}
+		})
+	}
+}
+
+func TestMetricsExporterForceFlushHonorsContext(t *testing.T) {
+	server := newCaptureServer(t)
+	server.setStatus(http.StatusServiceUnavailable)
+
+	config := testConfig(server.URL).
+		WithMaxRetries(5).
+		WithRetryBackoff(time.Second).
+		WithContinueExportOnCancel(false)
+	exporter := NewMetricsExporter(config)
+	defer exporter.Shutdown(context.Background())
+
+	exporter.batchMu.Lock()
+	exporter.batch = append(exporter.batch, MetricPoint{Name: "requests", Type: "counter", Value: 1})
+	exporter.batchMu.Unlock()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		server.waitForRequest(t, time.Second)
+		cancel()
+	}()
+
+	start := time.Now()
+	exporter.ForceFlush(ctx)
+	if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+		t.Errorf("ForceFlush() returned after %v, want prompt exit when its context is canceled mid-retry", elapsed)
+	}
+}
@@ -0,0 +1,119 @@
+package lumberjack
+
+import (
+	"net/http"
+	"strings"
+
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.17.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// HTTPMiddleware wraps next so that every request runs inside a server span.
+// Incoming traceparent (or, failing that, B3) and baggage headers are
+// honored, the response status is recorded on the span (5xx marks it as an
+// error), and request count and duration are recorded through the SDK's
+// Metrics.
+func (s *SDK) HTTPMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		remote := false
+		if traceparent := r.Header.Get(traceparentHeader); traceparent != "" {
+			if remoteCtx, err := s.ContextWithTraceparent(ctx, traceparent); err == nil {
+				ctx, remote = remoteCtx, true
+			}
+		}
+		if !remote {
+			ctx, _ = ContextWithB3(ctx, r.Header)
+		}
+		if header := r.Header.Get(baggageHeader); header != "" {
+			if baggageCtx, err := ContextWithBaggage(ctx, header); err == nil {
+				ctx = baggageCtx
+			}
+		}
+
+		route := requestRoute(r)
+		ctx, span := s.StartSpan(ctx, spanNameForRoute(r.Method, route),
+			trace.WithSpanKind(trace.SpanKindServer),
+			trace.WithAttributes(
+				semconv.HTTPMethod(r.Method),
+				semconv.HTTPTarget(r.URL.RequestURI()),
+			),
+		)
+		defer span.End()
+
+		var timer *RequestTimer
+		if s.metrics != nil {
+			timer = s.metrics.StartRequest(ctx, r.Method, route)
+		}
+
+		rec := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+		r = r.WithContext(ctx)
+		next.ServeHTTP(rec, r)
+
+		// ServeMux fills in the matched pattern while dispatching, which gives
+		// a lower-cardinality name than the raw path.
+		if r.Pattern != "" && r.Pattern != route {
+			route = r.Pattern
+			span.SetName(spanNameForRoute(r.Method, route))
+		}
+		span.SetAttributes(
+			semconv.HTTPRoute(route),
+			semconv.HTTPStatusCode(rec.status),
+		)
+		if rec.status >= http.StatusInternalServerError {
+			span.SetStatus(codes.Error, http.StatusText(rec.status))
+		}
+
+		if timer != nil {
+			timer.path = route
+			timer.End(rec.status)
+		}
+	})
+}
+
+// HTTPMiddleware wraps next with the global SDK's HTTP middleware.
+func HTTPMiddleware(next http.Handler) http.Handler {
+	return Get().HTTPMiddleware(next)
+}
+
+func requestRoute(r *http.Request) string {
+	if r.Pattern != "" {
+		return r.Pattern
+	}
+	return r.URL.Path
+}
+
+// spanNameForRoute builds "METHOD route", tolerating ServeMux patterns that
+// already carry a method prefix such as "GET /items/{id}".
+func spanNameForRoute(method, route string) string {
+	if strings.Contains(route, " ") {
+		return route
+	}
+	return method + " " + route
+}
+
+// statusRecorder captures the status code written by the wrapped handler.
+type statusRecorder struct {
+	http.ResponseWriter
+	status      int
+	wroteHeader bool
+}
+
+func (r *statusRecorder) WriteHeader(status int) {
+	if !r.wroteHeader {
+		r.status = status
+		r.wroteHeader = true
+	}
+	r.ResponseWriter.WriteHeader(status)
+}
+
+func (r *statusRecorder) Write(b []byte) (int, error) {
+	r.wroteHeader = true
+	return r.ResponseWriter.Write(b)
+}
+
+// Unwrap lets http.ResponseController reach the underlying writer.
+func (r *statusRecorder) Unwrap() http.ResponseWriter {
+	return r.ResponseWriter
+}
@@ -0,0 +1,143 @@
+package lumberjack
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func newTracingTestSDK(t *testing.T) (*SDK, *tracetest.InMemoryExporter) {
+	t.Helper()
+	spans := tracetest.NewInMemoryExporter()
+	sdk := newSDK(testConfig("http://127.0.0.1:0").WithCustomSpanExporter(spans))
+	t.Cleanup(func() { sdk.Shutdown(context.Background()) })
+	return sdk, spans
+}
+
+func TestHTTPMiddleware(t *testing.T) {
+	sdk, spans := newTracingTestSDK(t)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/items/{id}", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+	mux.HandleFunc("/broken", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	})
+	handler := sdk.HTTPMiddleware(mux)
+
+	tests := []struct {
+		name       string
+		path       string
+		wantName   string
+		wantStatus codes.Code
+	}{
+		{"ok request", "/items/42", "GET /items/{id}", codes.Unset},
+		{"server error", "/broken", "GET /broken", codes.Error},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			spans.Reset()
+
+			req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+			req.Header.Set("traceparent", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+			handler.ServeHTTP(httptest.NewRecorder(), req)
+
+			sdk.tracerProvider.ForceFlush(context.Background())
+			got := spans.GetSpans()
+			if len(got) != 1 {
+				t.Fatalf("expected 1 exported span, got %d", len(got))
+			}
+			span := got[0]
+			if span.Name != tt.wantName {
+				t.Errorf("span name = %q, want %q", span.Name, tt.wantName)
+			}
+			if span.Status.Code != tt.wantStatus {
+				t.Errorf("span status = %v, want %v", span.Status.Code, tt.wantStatus)
+			}
+			if span.SpanContext.TraceID().String() != "4bf92f3577b34da6a3ce929d0e0e4736" {
+				t.Errorf("span trace ID = %v, want the incoming traceparent's", span.SpanContext.TraceID())
+			}
+			if span.Parent.SpanID().String() != "00f067aa0ba902b7" {
+				t.Errorf("span parent = %v, want 00f067aa0ba902b7", span.Parent.SpanID())
+			}
+		})
+	}
+}
+
+func TestHTTPMiddlewareExportsStatusCode(t *testing.T) {
+	server := newCaptureServer(t)
+	sdk := newSDK(testConfig(server.URL))
+
+	handler := sdk.HTTPMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/broken", nil))
+	sdk.Shutdown(context.Background())
+
+	spans := exportedSpans(t, server)
+	if len(spans) != 1 {
+		t.Fatalf("expected 1 exported span, got %d", len(spans))
+	}
+	if got := spans[0].Attributes["http.status_code"]; got != "503" {
+		t.Errorf("http.status_code = %q, want %q", got, "503")
+	}
+}
+
+func TestHTTPMiddlewareExtractsBaggage(t *testing.T) {
+	sdk, _ := newTracingTestSDK(t)
+
+	var tenant string
+	handler := sdk.HTTPMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		tenant = BaggageFromContext(r.Context()).Member("tenant").Value()
+	}))
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("baggage", "tenant=acme,userId=42")
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+
+	if tenant != "acme" {
+		t.Errorf("handler saw tenant baggage %q, want %q", tenant, "acme")
+	}
+}
+
+func TestHTTPMiddlewareB3Fallback(t *testing.T) {
+	sdk, spans := newTracingTestSDK(t)
+	handler := sdk.HTTPMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+
+	tests := []struct {
+		name      string
+		headers   map[string]string
+		wantTrace string
+	}{
+		{"b3 only", map[string]string{"b3": "80f198ee56343ba864fe8b2a57d3eff7-e457b5a2e4d86bd1-1"}, "80f198ee56343ba864fe8b2a57d3eff7"},
+		{"w3c wins", map[string]string{
+			"traceparent": "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01",
+			"b3":          "80f198ee56343ba864fe8b2a57d3eff7-e457b5a2e4d86bd1-1",
+		}, "4bf92f3577b34da6a3ce929d0e0e4736"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			spans.Reset()
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			for key, value := range tt.headers {
+				req.Header.Set(key, value)
+			}
+			handler.ServeHTTP(httptest.NewRecorder(), req)
+
+			sdk.tracerProvider.ForceFlush(context.Background())
+			got := spans.GetSpans()
+			if len(got) != 1 {
+				t.Fatalf("expected 1 exported span, got %d", len(got))
+			}
+			if traceID := got[0].SpanContext.TraceID().String(); traceID != tt.wantTrace {
+				t.Errorf("trace ID = %s, want %s", traceID, tt.wantTrace)
+			}
+		})
+	}
+}
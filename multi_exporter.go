@@ -0,0 +1,64 @@
+package lumberjack
+
+import (
+	"context"
+	"errors"
+
+	sdklog "go.opentelemetry.io/otel/sdk/log"
+)
+
+// logsFlusher is implemented by logs exporters that buffer records and can
+// flush them on demand.
+type logsFlusher interface {
+	ForceFlush(ctx context.Context) error
+}
+
+// MultiLogsExporter returns a LogsExporter that forwards every call to each of
+// exporters, e.g. to ship logs to Lumberjack and a local file at once. Every
+// child receives every batch even if another child fails; errors from all
+// children are joined.
+//
+//	config.WithCustomLogsExporter(lumberjack.MultiLogsExporter(
+//		lumberjack.NewLogsExporter(config),
+//		fileExporter,
+//	))
+func MultiLogsExporter(exporters ...LogsExporter) LogsExporter {
+	return &multiLogsExporter{exporters: exporters}
+}
+
+type multiLogsExporter struct {
+	exporters []LogsExporter
+}
+
+func (m *multiLogsExporter) Export(ctx context.Context, records []*sdklog.Record) error {
+	var errs []error
+	for _, exporter := range m.exporters {
+		if err := exporter.Export(ctx, records); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// ForceFlush flushes every child that supports it.
+func (m *multiLogsExporter) ForceFlush(ctx context.Context) error {
+	var errs []error
+	for _, exporter := range m.exporters {
+		if flusher, ok := exporter.(logsFlusher); ok {
+			if err := flusher.ForceFlush(ctx); err != nil {
+				errs = append(errs, err)
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (m *multiLogsExporter) Shutdown(ctx context.Context) error {
+	var errs []error
+	for _, exporter := range m.exporters {
+		if err := exporter.Shutdown(ctx); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
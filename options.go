@@ -0,0 +1,75 @@
+package lumberjack
+
+import (
+	"time"
+
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+// Option configures the SDK created by New. Each one applies the Config
+// builder of the same name (WithProject and WithKey apply WithProjectName
+// and WithAPIKey) on top of the environment defaults of NewConfig.
+type Option func(*Config)
+
+// New initializes the global SDK like Init, from NewConfig with opts applied
+// in order:
+//
+//	sdk := lumberjack.New(lumberjack.WithProject("checkout"), lumberjack.WithKey(key))
+func New(opts ...Option) *SDK {
+	config := NewConfig()
+	for _, opt := range opts {
+		opt(config)
+	}
+	return Init(config)
+}
+
+// WithConfig applies fn to the Config, for settings without a dedicated
+// Option.
+func WithConfig(fn func(*Config)) Option {
+	return fn
+}
+
+func WithProject(name string) Option {
+	return func(c *Config) { c.WithProjectName(name) }
+}
+
+func WithKey(key string) Option {
+	return func(c *Config) { c.WithAPIKey(key) }
+}
+
+func WithBaseURL(url string) Option {
+	return func(c *Config) { c.WithBaseURL(url) }
+}
+
+func WithDebug(debug bool) Option {
+	return func(c *Config) { c.WithDebug(debug) }
+}
+
+func WithServiceVersion(version string) Option {
+	return func(c *Config) { c.WithServiceVersion(version) }
+}
+
+func WithEnvironment(environment string) Option {
+	return func(c *Config) { c.WithEnvironment(environment) }
+}
+
+func WithBatchSize(size int) Option {
+	return func(c *Config) { c.WithBatchSize(size) }
+}
+
+func WithBatchTimeout(timeout time.Duration) Option {
+	return func(c *Config) { c.WithBatchTimeout(timeout) }
+}
+
+func WithSampler(sampler sdktrace.Sampler) Option {
+	return func(c *Config) { c.WithSampler(sampler) }
+}
+
+func WithPropagator(propagator propagation.TextMapPropagator) Option {
+	return func(c *Config) { c.WithPropagator(propagator) }
+}
+
+func WithReplaceSlog(replace bool) Option {
+	return func(c *Config) { c.WithReplaceSlog(replace) }
+}
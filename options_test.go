@@ -0,0 +1,62 @@
+package lumberjack
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+func TestNewWithOptions(t *testing.T) {
+	originalGlobalSDK := globalSDK
+	originalTracerProvider := otel.GetTracerProvider()
+	originalMeterProvider := otel.GetMeterProvider()
+	originalPropagator := otel.GetTextMapPropagator()
+	globalSDK = nil
+	once = sync.Once{}
+	defer func() {
+		globalSDK = originalGlobalSDK
+		once = sync.Once{}
+		otel.SetTracerProvider(originalTracerProvider)
+		otel.SetMeterProvider(originalMeterProvider)
+		otel.SetTextMapPropagator(originalPropagator)
+	}()
+	t.Setenv("LUMBERJACK_PROJECT_NAME", "from-env")
+
+	server := newCaptureServer(t)
+	sdk := New(
+		WithProject("checkout"),
+		WithKey("test-key"),
+		WithBaseURL(server.URL),
+		WithEnvironment("staging"),
+		WithBatchSize(10),
+		WithBatchTimeout(time.Hour),
+		WithReplaceSlog(false),
+		WithSampler(sdktrace.NeverSample()),
+		WithConfig(func(c *Config) { c.MaxQueueSize = 50 }),
+	)
+	defer sdk.Shutdown(context.Background())
+
+	if got, ok := TryGet(); !ok || got != sdk {
+		t.Fatal("New() did not initialize the global SDK")
+	}
+	config := sdk.config
+	if config.ProjectName != "checkout" || config.APIKey != "test-key" || config.BaseURL != server.URL {
+		t.Errorf("project/key/base URL = %q/%q/%q, want the option values over the environment", config.ProjectName, config.APIKey, config.BaseURL)
+	}
+	if config.Environment != "staging" || config.BatchSize != 10 || config.BatchTimeout != time.Hour {
+		t.Errorf("environment/batch size/batch timeout = %q/%d/%v", config.Environment, config.BatchSize, config.BatchTimeout)
+	}
+	if config.ReplaceSlog || config.MaxQueueSize != 50 {
+		t.Errorf("ReplaceSlog/MaxQueueSize = %v/%d, want false/50", config.ReplaceSlog, config.MaxQueueSize)
+	}
+
+	_, span := sdk.StartSpan(context.Background(), "work")
+	defer span.End()
+	if span.IsRecording() {
+		t.Error("span is recording, want it dropped by the NeverSample sampler")
+	}
+}
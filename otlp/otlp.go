@@ -0,0 +1,71 @@
+// Package otlp sends the Lumberjack SDK's spans and logs to an OpenTelemetry
+// Collector, or any other OTLP/HTTP receiver, instead of the Lumberjack JSON
+// endpoints. It lives in its own package so the core SDK carries no OTLP
+// dependency.
+package otlp
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	lumberjack "github.com/TreebeardHQ/go-sdk"
+	"go.opentelemetry.io/otel/exporters/otlp/otlplog/otlploghttp"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	sdklog "go.opentelemetry.io/otel/sdk/log"
+)
+
+// Configure installs OTLP/HTTP span and log exporters on config, replacing the
+// default Lumberjack exporters. endpoint is the receiver's base URL, e.g.
+// "http://localhost:4318"; spans go to /v1/traces and logs to /v1/logs.
+//
+//	config := lumberjack.NewConfig()
+//	if err := otlp.Configure(ctx, config, "http://localhost:4318"); err != nil {
+//		return err
+//	}
+//	lumberjack.Init(config)
+func Configure(ctx context.Context, config *lumberjack.Config, endpoint string) error {
+	base := strings.TrimSuffix(endpoint, "/")
+
+	spanExporter, err := otlptracehttp.New(ctx, otlptracehttp.WithEndpointURL(base+"/v1/traces"))
+	if err != nil {
+		return fmt.Errorf("failed to create OTLP span exporter: %w", err)
+	}
+	logExporter, err := otlploghttp.New(ctx, otlploghttp.WithEndpointURL(base+"/v1/logs"))
+	if err != nil {
+		return fmt.Errorf("failed to create OTLP log exporter: %w", err)
+	}
+
+	config.WithCustomSpanExporter(spanExporter).
+		WithCustomLogsExporter(NewLogsExporter(logExporter))
+	return nil
+}
+
+// LogsExporter adapts an OpenTelemetry log exporter to lumberjack.LogsExporter.
+// Records are batched before export, since the SDK hands them over one at a
+// time.
+type LogsExporter struct {
+	processor *sdklog.BatchProcessor
+}
+
+// NewLogsExporter wraps exporter in a batching lumberjack.LogsExporter.
+func NewLogsExporter(exporter sdklog.Exporter, opts ...sdklog.BatchProcessorOption) *LogsExporter {
+	return &LogsExporter{processor: sdklog.NewBatchProcessor(exporter, opts...)}
+}
+
+func (e *LogsExporter) Export(ctx context.Context, records []*sdklog.Record) error {
+	for _, record := range records {
+		if err := e.processor.OnEmit(ctx, record); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (e *LogsExporter) ForceFlush(ctx context.Context) error {
+	return e.processor.ForceFlush(ctx)
+}
+
+func (e *LogsExporter) Shutdown(ctx context.Context) error {
+	return e.processor.Shutdown(ctx)
+}
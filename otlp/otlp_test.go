@@ -0,0 +1,109 @@
+package otlp
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	lumberjack "github.com/TreebeardHQ/go-sdk"
+	collogspb "go.opentelemetry.io/proto/otlp/collector/logs/v1"
+	coltracepb "go.opentelemetry.io/proto/otlp/collector/trace/v1"
+	"google.golang.org/protobuf/proto"
+)
+
+// otlpReceiver is a stub OTLP/HTTP endpoint that decodes every protobuf
+// request it receives.
+type otlpReceiver struct {
+	mu     sync.Mutex
+	traces []*coltracepb.ExportTraceServiceRequest
+	logs   []*collogspb.ExportLogsServiceRequest
+}
+
+func (r *otlpReceiver) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	body, _ := io.ReadAll(req.Body)
+	if req.Header.Get("Content-Type") != "application/x-protobuf" {
+		http.Error(w, "unexpected content type", http.StatusUnsupportedMediaType)
+		return
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	switch req.URL.Path {
+	case "/v1/traces":
+		msg := &coltracepb.ExportTraceServiceRequest{}
+		if err := proto.Unmarshal(body, msg); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		r.traces = append(r.traces, msg)
+	case "/v1/logs":
+		msg := &collogspb.ExportLogsServiceRequest{}
+		if err := proto.Unmarshal(body, msg); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		r.logs = append(r.logs, msg)
+	default:
+		http.NotFound(w, req)
+		return
+	}
+	w.Header().Set("Content-Type", "application/x-protobuf")
+	w.WriteHeader(http.StatusOK)
+}
+
+func TestConfigureSendsOTLP(t *testing.T) {
+	receiver := &otlpReceiver{}
+	server := httptest.NewServer(receiver)
+	defer server.Close()
+
+	config := lumberjack.NewConfig().
+		WithAPIKey("test-key").
+		WithBaseURL(server.URL).
+		WithProjectName("test").
+		WithReplaceSlog(false)
+	if err := Configure(context.Background(), config, server.URL); err != nil {
+		t.Fatalf("Configure() unexpected error = %v", err)
+	}
+	sdk := lumberjack.Init(config)
+
+	ctx, span := sdk.StartSpan(context.Background(), "checkout")
+	sdk.Logger().InfoContext(ctx, "order placed")
+	span.End()
+	if err := sdk.Shutdown(context.Background()); err != nil {
+		t.Fatalf("Shutdown() unexpected error = %v", err)
+	}
+
+	receiver.mu.Lock()
+	defer receiver.mu.Unlock()
+
+	var spanNames []string
+	for _, req := range receiver.traces {
+		for _, rs := range req.ResourceSpans {
+			for _, ss := range rs.ScopeSpans {
+				for _, s := range ss.Spans {
+					spanNames = append(spanNames, s.Name)
+				}
+			}
+		}
+	}
+	if len(spanNames) != 1 || spanNames[0] != "checkout" {
+		t.Errorf("received spans %v, want [checkout]", spanNames)
+	}
+
+	var bodies []string
+	for _, req := range receiver.logs {
+		for _, rl := range req.ResourceLogs {
+			for _, sl := range rl.ScopeLogs {
+				for _, l := range sl.LogRecords {
+					bodies = append(bodies, l.Body.GetStringValue())
+				}
+			}
+		}
+	}
+	if len(bodies) != 1 || bodies[0] != "order placed" {
+		t.Errorf("received logs %v, want [order placed]", bodies)
+	}
+}
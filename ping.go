@@ -0,0 +1,81 @@
+package lumberjack
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+// Errors returned (wrapped) by Ping so callers can tell a rejected API key
+// from an unreachable endpoint with errors.Is.
+var (
+	// ErrUnauthorized is returned when Lumberjack rejects the API key (401/403).
+	ErrUnauthorized = errors.New("lumberjack rejected the API key")
+	// ErrUnreachable is returned when no response was received from BaseURL.
+	ErrUnreachable = errors.New("lumberjack endpoint unreachable")
+)
+
+// Ping checks that BaseURL is reachable and accepts the configured API key by
+// sending an empty, authenticated log batch. Call it at startup to fail fast
+// on bad credentials instead of losing the first batches.
+//
+//	if err := sdk.Ping(ctx); errors.Is(err, lumberjack.ErrUnauthorized) {
+//		log.Fatal("check LUMBERJACK_API_KEY")
+//	}
+func (s *SDK) Ping(ctx context.Context) error {
+	client, apiKey := s.pingClient()
+
+	body, err := json.Marshal(LogRequest{
+		Logs:        []LogEntry{},
+		ProjectName: s.config.ProjectName,
+		SdkVersion:  sdkVersion,
+		SdkSemver:   sdkRelease(),
+	})
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, "POST", s.config.BaseURL+"/logs/batch", bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrUnreachable, err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", s.config.userAgent())
+	if err := apiKey.setHeader(req); err != nil {
+		return fmt.Errorf("%w: %v", ErrUnauthorized, err)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrUnreachable, err)
+	}
+	resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusUnauthorized || resp.StatusCode == http.StatusForbidden:
+		return fmt.Errorf("%w: %s", ErrUnauthorized, resp.Status)
+	case resp.StatusCode >= 300:
+		return fmt.Errorf("ping failed: %s", resp.Status)
+	}
+	return nil
+}
+
+// pingClient reuses the default logs exporter's client and key source when
+// there is one, so Ping exercises the same transport the SDK exports with.
+func (s *SDK) pingClient() (*http.Client, *apiKeySource) {
+	if e := s.defaultLogsExporter; e != nil {
+		return e.client, e.apiKey
+	}
+	return newHTTPClient(s.config), newAPIKeySource(s.config)
+}
+
+// Ping checks connectivity and credentials using the global SDK. Before Init
+// it returns ErrNotInitialized.
+func Ping(ctx context.Context) error {
+	if sdk, ok := TryGet(); ok {
+		return sdk.Ping(ctx)
+	}
+	return ErrNotInitialized
+}
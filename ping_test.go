@@ -0,0 +1,59 @@
+package lumberjack
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+)
+
+func TestPing(t *testing.T) {
+	tests := []struct {
+		name    string
+		status  int
+		wantErr error
+	}{
+		{"ok", http.StatusOK, nil},
+		{"unauthorized", http.StatusUnauthorized, ErrUnauthorized},
+		{"forbidden", http.StatusForbidden, ErrUnauthorized},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := newCaptureServer(t)
+			server.setStatus(tt.status)
+			sdk := newSDK(testConfig(server.URL))
+			defer sdk.Shutdown(context.Background())
+
+			err := sdk.Ping(context.Background())
+			if tt.wantErr == nil && err != nil {
+				t.Fatalf("Ping() error = %v, want nil", err)
+			}
+			if tt.wantErr != nil && !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Ping() error = %v, want errors.Is(%v)", err, tt.wantErr)
+			}
+
+			requests := server.requestsFor("/logs/batch")
+			if len(requests) != 1 {
+				t.Fatalf("got %d requests, want 1", len(requests))
+			}
+			if got := requests[0].Header.Get("Authorization"); got != "Bearer test-key" {
+				t.Errorf("Authorization = %q, want %q", got, "Bearer test-key")
+			}
+		})
+	}
+}
+
+func TestPingUnreachable(t *testing.T) {
+	server := newCaptureServer(t)
+	sdk := newSDK(testConfig(server.URL))
+	defer sdk.Shutdown(context.Background())
+	server.Close()
+
+	err := sdk.Ping(context.Background())
+	if !errors.Is(err, ErrUnreachable) {
+		t.Errorf("Ping() error = %v, want errors.Is(ErrUnreachable)", err)
+	}
+	if errors.Is(err, ErrUnauthorized) {
+		t.Errorf("Ping() error = %v, should not report an auth failure", err)
+	}
+}
@@ -0,0 +1,47 @@
+// Package prometheus exposes Lumberjack SDK metrics on a Prometheus-scrapeable
+// HTTP endpoint. It lives in its own package so the core SDK carries no
+// Prometheus dependency.
+package prometheus
+
+import (
+	"fmt"
+	"net/http"
+
+	promclient "github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	otelprom "go.opentelemetry.io/otel/exporters/prometheus"
+)
+
+// Exporter is a metric reader that serves the metrics it collects in the
+// Prometheus text format. Register it with Config.WithMetricReader so every
+// instrument created through the SDK's Meter is exported both to Lumberjack
+// and to Prometheus:
+//
+//	exporter, err := prometheus.New()
+//	sdk := lumberjack.Init(lumberjack.NewConfig().WithMetricReader(exporter))
+//	http.Handle("/metrics", exporter.Handler())
+type Exporter struct {
+	*otelprom.Exporter
+
+	handler http.Handler
+}
+
+// New creates an Exporter backed by its own Prometheus registry, so it never
+// collides with collectors registered on the default registry.
+func New() (*Exporter, error) {
+	registry := promclient.NewRegistry()
+	exporter, err := otelprom.New(otelprom.WithRegisterer(registry))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create prometheus exporter: %w", err)
+	}
+	return &Exporter{
+		Exporter: exporter,
+		handler:  promhttp.HandlerFor(registry, promhttp.HandlerOpts{}),
+	}, nil
+}
+
+// Handler returns the HTTP handler that serves the collected metrics, usually
+// mounted at /metrics.
+func (e *Exporter) Handler() http.Handler {
+	return e.handler
+}
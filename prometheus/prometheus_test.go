@@ -0,0 +1,56 @@
+package prometheus
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	lumberjack "github.com/TreebeardHQ/go-sdk"
+)
+
+func TestHandlerServesSDKMetrics(t *testing.T) {
+	backend := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer backend.Close()
+
+	exporter, err := New()
+	if err != nil {
+		t.Fatalf("New() unexpected error = %v", err)
+	}
+
+	config := lumberjack.NewConfig().
+		WithAPIKey("test-key").
+		WithBaseURL(backend.URL).
+		WithProjectName("test").
+		WithReplaceSlog(false).
+		WithMetricReader(exporter)
+	sdk := lumberjack.Init(config)
+	defer sdk.Shutdown(context.Background())
+
+	counter, err := sdk.Meter().Int64Counter("jobs.processed")
+	if err != nil {
+		t.Fatalf("Int64Counter() unexpected error = %v", err)
+	}
+	counter.Add(context.Background(), 3)
+
+	server := httptest.NewServer(exporter.Handler())
+	defer server.Close()
+
+	resp, err := http.Get(server.URL + "/metrics")
+	if err != nil {
+		t.Fatalf("GET /metrics unexpected error = %v", err)
+	}
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(resp.Body)
+
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("GET /metrics status = %d, want 200", resp.StatusCode)
+	}
+	if !strings.Contains(string(body), "jobs_processed_total") {
+		t.Errorf("scrape output does not contain jobs_processed_total:\n%s", body)
+	}
+}
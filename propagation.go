@@ -0,0 +1,87 @@
+package lumberjack
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/baggage"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	traceparentHeader = "traceparent"
+	tracestateHeader  = "tracestate"
+	baggageHeader     = "baggage"
+)
+
+// InjectTraceparent serializes the active span context in ctx into a W3C
+// traceparent value (version-traceid-spanid-flags). It returns false if ctx
+// carries no valid span context.
+func InjectTraceparent(ctx context.Context) (string, bool) {
+	spanCtx := trace.SpanContextFromContext(ctx)
+	if !spanCtx.IsValid() {
+		return "", false
+	}
+	return formatTraceparent(spanCtx), true
+}
+
+// InjectIntoHeader sets the traceparent and tracestate headers on h from the
+// active span context in ctx, and the baggage header from any baggage in ctx.
+// It returns false if ctx carries no valid span context, in which case only
+// baggage, if any, is written.
+func InjectIntoHeader(ctx context.Context, h http.Header) bool {
+	if b := baggage.FromContext(ctx); b.Len() > 0 {
+		h.Set(baggageHeader, b.String())
+	}
+
+	spanCtx := trace.SpanContextFromContext(ctx)
+	if !spanCtx.IsValid() {
+		return false
+	}
+
+	h.Set(traceparentHeader, formatTraceparent(spanCtx))
+	if ts := spanCtx.TraceState().String(); ts != "" {
+		h.Set(tracestateHeader, ts)
+	} else {
+		h.Del(tracestateHeader)
+	}
+	return true
+}
+
+// formatTraceparent is the inverse of parseTraceparent
+func formatTraceparent(spanCtx trace.SpanContext) string {
+	flags := spanCtx.TraceFlags() & trace.FlagsSampled
+	return fmt.Sprintf("00-%s-%s-%s", spanCtx.TraceID(), spanCtx.SpanID(), flags)
+}
+
+// ContextWithBaggage parses a W3C baggage header value such as
+// "userId=42,tenant=acme;region=eu" and returns a copy of ctx carrying it.
+func ContextWithBaggage(ctx context.Context, header string) (context.Context, error) {
+	b, err := baggage.Parse(header)
+	if err != nil {
+		return ctx, fmt.Errorf("invalid baggage: %w", err)
+	}
+	return baggage.ContextWithBaggage(ctx, b), nil
+}
+
+// BaggageFromContext returns the baggage carried by ctx, which is empty if
+// there is none.
+func BaggageFromContext(ctx context.Context) baggage.Baggage {
+	return baggage.FromContext(ctx)
+}
+
+// LinkFromTraceparent builds a span link to the span identified by a W3C
+// traceparent value, for use with trace.WithLinks when a span has several
+// causal parents:
+//
+//	link, err := lumberjack.LinkFromTraceparent(msg.Headers["traceparent"])
+//	ctx, span := lumberjack.StartSpan(ctx, "consume", trace.WithLinks(link))
+func LinkFromTraceparent(traceparent string, attrs ...attribute.KeyValue) (trace.Link, error) {
+	spanCtx, err := parseTraceparent(traceparent)
+	if err != nil {
+		return trace.Link{}, fmt.Errorf("invalid traceparent: %w", err)
+	}
+	return trace.Link{SpanContext: spanCtx, Attributes: attrs}, nil
+}
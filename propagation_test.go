@@ -0,0 +1,343 @@
+package lumberjack
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestInjectTraceparentRoundTrip(t *testing.T) {
+	tests := []string{
+		"00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01",
+		"00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-00",
+	}
+
+	for _, traceparent := range tests {
+		t.Run(traceparent, func(t *testing.T) {
+			spanCtx, err := parseTraceparent(traceparent)
+			if err != nil {
+				t.Fatalf("parseTraceparent() unexpected error = %v", err)
+			}
+			ctx := trace.ContextWithRemoteSpanContext(context.Background(), spanCtx)
+
+			got, ok := InjectTraceparent(ctx)
+			if !ok {
+				t.Fatalf("InjectTraceparent() ok = false, want true")
+			}
+			if got != traceparent {
+				t.Errorf("InjectTraceparent() = %q, want %q", got, traceparent)
+			}
+
+			// Parsing the injected value again must yield the same span context
+			reparsed, err := parseTraceparent(got)
+			if err != nil {
+				t.Fatalf("parseTraceparent() of injected value unexpected error = %v", err)
+			}
+			if !reparsed.Equal(spanCtx) {
+				t.Errorf("round trip span context = %v, want %v", reparsed, spanCtx)
+			}
+		})
+	}
+}
+
+func TestInjectTraceparentWithoutSpan(t *testing.T) {
+	if got, ok := InjectTraceparent(context.Background()); ok {
+		t.Errorf("InjectTraceparent() = %q, true; want false", got)
+	}
+
+	h := http.Header{}
+	if InjectIntoHeader(context.Background(), h) {
+		t.Errorf("InjectIntoHeader() = true, want false")
+	}
+	if len(h) != 0 {
+		t.Errorf("InjectIntoHeader() modified headers without a span: %v", h)
+	}
+}
+
+func TestInjectIntoHeader(t *testing.T) {
+	spanCtx, err := parseTraceparent("00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	if err != nil {
+		t.Fatalf("parseTraceparent() unexpected error = %v", err)
+	}
+	ts, err := trace.ParseTraceState("vendor=value")
+	if err != nil {
+		t.Fatalf("ParseTraceState() unexpected error = %v", err)
+	}
+	ctx := trace.ContextWithRemoteSpanContext(context.Background(), spanCtx.WithTraceState(ts))
+
+	h := http.Header{}
+	if !InjectIntoHeader(ctx, h) {
+		t.Fatalf("InjectIntoHeader() = false, want true")
+	}
+	if got := h.Get("traceparent"); got != "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01" {
+		t.Errorf("traceparent header = %q", got)
+	}
+	if got := h.Get("tracestate"); got != "vendor=value" {
+		t.Errorf("tracestate header = %q, want %q", got, "vendor=value")
+	}
+}
+
+func TestContextWithBaggageRoundTrip(t *testing.T) {
+	ctx, err := ContextWithBaggage(context.Background(), "userId=42,tenant=acme;region=eu,note=hello%20world")
+	if err != nil {
+		t.Fatalf("ContextWithBaggage() unexpected error = %v", err)
+	}
+
+	b := BaggageFromContext(ctx)
+	want := map[string]string{"userId": "42", "tenant": "acme", "note": "hello world"}
+	if b.Len() != len(want) {
+		t.Errorf("baggage has %d members, want %d", b.Len(), len(want))
+	}
+	for key, value := range want {
+		if got := b.Member(key).Value(); got != value {
+			t.Errorf("member %q = %q, want %q", key, got, value)
+		}
+	}
+	if props := b.Member("tenant").Properties(); len(props) != 1 || props[0].Key() != "region" {
+		t.Errorf("tenant properties = %v, want [region=eu]", props)
+	}
+
+	// Injecting and parsing again must preserve every member
+	h := http.Header{}
+	InjectIntoHeader(ctx, h)
+	reparsedCtx, err := ContextWithBaggage(context.Background(), h.Get("baggage"))
+	if err != nil {
+		t.Fatalf("ContextWithBaggage() of injected header unexpected error = %v", err)
+	}
+	reparsed := BaggageFromContext(reparsedCtx)
+	for key, value := range want {
+		if got := reparsed.Member(key).Value(); got != value {
+			t.Errorf("round trip member %q = %q, want %q", key, got, value)
+		}
+	}
+}
+
+func TestContextWithBaggageMalformed(t *testing.T) {
+	for _, header := range []string{"novalue", "=missingkey", "bad key=1"} {
+		ctx := context.Background()
+		got, err := ContextWithBaggage(ctx, header)
+		if err == nil {
+			t.Errorf("ContextWithBaggage(%q) error = nil, want an error", header)
+		}
+		if got != ctx {
+			t.Errorf("ContextWithBaggage(%q) returned a new context on error", header)
+		}
+	}
+}
+
+func TestInitRegistersPropagator(t *testing.T) {
+	original := otel.GetTextMapPropagator()
+	defer otel.SetTextMapPropagator(original)
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator())
+
+	server := newCaptureServer(t)
+	sdk := newSDK(testConfig(server.URL))
+	defer sdk.Shutdown(context.Background())
+
+	const traceparent = "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"
+	propagator := otel.GetTextMapPropagator()
+	ctx := propagator.Extract(context.Background(), propagation.HeaderCarrier{
+		"Traceparent": []string{traceparent},
+		"Baggage":     []string{"tenant=acme"},
+	})
+	if !trace.SpanContextFromContext(ctx).IsValid() {
+		t.Fatal("global propagator did not extract the traceparent")
+	}
+
+	header := http.Header{}
+	propagator.Inject(ctx, propagation.HeaderCarrier(header))
+	if got := header.Get("traceparent"); got != traceparent {
+		t.Errorf("injected traceparent = %q, want %q", got, traceparent)
+	}
+	if got := header.Get("baggage"); got != "tenant=acme" {
+		t.Errorf("injected baggage = %q, want tenant=acme", got)
+	}
+}
+
+func TestInitRegistersCustomPropagator(t *testing.T) {
+	original := otel.GetTextMapPropagator()
+	defer otel.SetTextMapPropagator(original)
+
+	server := newCaptureServer(t)
+	sdk := newSDK(testConfig(server.URL).WithPropagator(propagation.Baggage{}))
+	defer sdk.Shutdown(context.Background())
+
+	if fields := otel.GetTextMapPropagator().Fields(); len(fields) != 1 || fields[0] != "baggage" {
+		t.Errorf("global propagator fields = %v, want only the configured baggage propagator", fields)
+	}
+}
+
+func TestContextWithB3(t *testing.T) {
+	tests := []struct {
+		name        string
+		headers     map[string]string
+		wantTrace   string
+		wantSpan    string
+		wantSampled bool
+	}{
+		{
+			name:        "single sampled",
+			headers:     map[string]string{"b3": "80f198ee56343ba864fe8b2a57d3eff7-e457b5a2e4d86bd1-1-05e3ac9a4f6e3b90"},
+			wantTrace:   "80f198ee56343ba864fe8b2a57d3eff7",
+			wantSpan:    "e457b5a2e4d86bd1",
+			wantSampled: true,
+		},
+		{
+			name:      "single not sampled with 64-bit trace id",
+			headers:   map[string]string{"b3": "64fe8b2a57d3eff7-e457b5a2e4d86bd1-0"},
+			wantTrace: "000000000000000064fe8b2a57d3eff7",
+			wantSpan:  "e457b5a2e4d86bd1",
+		},
+		{
+			name:        "single debug",
+			headers:     map[string]string{"b3": "80f198ee56343ba864fe8b2a57d3eff7-e457b5a2e4d86bd1-d"},
+			wantTrace:   "80f198ee56343ba864fe8b2a57d3eff7",
+			wantSpan:    "e457b5a2e4d86bd1",
+			wantSampled: true,
+		},
+		{
+			name: "multi sampled",
+			headers: map[string]string{
+				"X-B3-TraceId": "80f198ee56343ba864fe8b2a57d3eff7",
+				"X-B3-SpanId":  "e457b5a2e4d86bd1",
+				"X-B3-Sampled": "1",
+			},
+			wantTrace:   "80f198ee56343ba864fe8b2a57d3eff7",
+			wantSpan:    "e457b5a2e4d86bd1",
+			wantSampled: true,
+		},
+		{
+			name: "multi not sampled",
+			headers: map[string]string{
+				"X-B3-TraceId": "80f198ee56343ba864fe8b2a57d3eff7",
+				"X-B3-SpanId":  "e457b5a2e4d86bd1",
+				"X-B3-Sampled": "0",
+			},
+			wantTrace: "80f198ee56343ba864fe8b2a57d3eff7",
+			wantSpan:  "e457b5a2e4d86bd1",
+		},
+		{
+			name: "multi debug flag",
+			headers: map[string]string{
+				"X-B3-TraceId": "80f198ee56343ba864fe8b2a57d3eff7",
+				"X-B3-SpanId":  "e457b5a2e4d86bd1",
+				"X-B3-Flags":   "1",
+			},
+			wantTrace:   "80f198ee56343ba864fe8b2a57d3eff7",
+			wantSpan:    "e457b5a2e4d86bd1",
+			wantSampled: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			headers := http.Header{}
+			for key, value := range tt.headers {
+				headers.Set(key, value)
+			}
+			ctx, ok := ContextWithB3(context.Background(), headers)
+			if !ok {
+				t.Fatal("ContextWithB3() ok = false, want true")
+			}
+			spanCtx := trace.SpanContextFromContext(ctx)
+			if spanCtx.TraceID().String() != tt.wantTrace || spanCtx.SpanID().String() != tt.wantSpan {
+				t.Errorf("span context = %s/%s, want %s/%s", spanCtx.TraceID(), spanCtx.SpanID(), tt.wantTrace, tt.wantSpan)
+			}
+			if spanCtx.IsSampled() != tt.wantSampled {
+				t.Errorf("sampled = %v, want %v", spanCtx.IsSampled(), tt.wantSampled)
+			}
+			if !spanCtx.IsRemote() {
+				t.Error("span context is not marked remote")
+			}
+		})
+	}
+}
+
+func TestContextWithB3Invalid(t *testing.T) {
+	tests := map[string]map[string]string{
+		"no headers":            {},
+		"sampling only":         {"b3": "0"},
+		"bad trace id":          {"b3": "not-hex"},
+		"zero trace id":         {"b3": "00000000000000000000000000000000-e457b5a2e4d86bd1-1"},
+		"bad sampled flag":      {"b3": "80f198ee56343ba864fe8b2a57d3eff7-e457b5a2e4d86bd1-yes"},
+		"multi without span id": {"X-B3-TraceId": "80f198ee56343ba864fe8b2a57d3eff7"},
+	}
+	for name, values := range tests {
+		t.Run(name, func(t *testing.T) {
+			headers := http.Header{}
+			for key, value := range values {
+				headers.Set(key, value)
+			}
+			ctx, ok := ContextWithB3(context.Background(), headers)
+			if ok || trace.SpanContextFromContext(ctx).IsValid() {
+				t.Errorf("ContextWithB3() ok = %v, want false and ctx unchanged", ok)
+			}
+		})
+	}
+}
+
+func TestContextWithDatadogHeaders(t *testing.T) {
+	tests := []struct {
+		name        string
+		traceID     string
+		parentID    string
+		priority    string
+		wantTrace   string
+		wantSpan    string
+		wantSampled bool
+	}{
+		{"auto keep", "1234567890123456789", "987654321", "1", "0000000000000000112210f47de98115", "000000003ade68b1", true},
+		{"user keep", "18446744073709551615", "1", "2", "0000000000000000ffffffffffffffff", "0000000000000001", true},
+		{"auto reject", "1234567890123456789", "987654321", "0", "0000000000000000112210f47de98115", "000000003ade68b1", false},
+		{"user reject", "1234567890123456789", "987654321", "-1", "0000000000000000112210f47de98115", "000000003ade68b1", false},
+		{"no priority", "1234567890123456789", "987654321", "", "0000000000000000112210f47de98115", "000000003ade68b1", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			headers := http.Header{}
+			headers.Set("x-datadog-trace-id", tt.traceID)
+			headers.Set("x-datadog-parent-id", tt.parentID)
+			if tt.priority != "" {
+				headers.Set("x-datadog-sampling-priority", tt.priority)
+			}
+
+			ctx, ok := ContextWithDatadogHeaders(context.Background(), headers)
+			if !ok {
+				t.Fatal("ContextWithDatadogHeaders() ok = false, want true")
+			}
+			spanCtx := trace.SpanContextFromContext(ctx)
+			if spanCtx.TraceID().String() != tt.wantTrace || spanCtx.SpanID().String() != tt.wantSpan {
+				t.Errorf("span context = %s/%s, want %s/%s", spanCtx.TraceID(), spanCtx.SpanID(), tt.wantTrace, tt.wantSpan)
+			}
+			if spanCtx.IsSampled() != tt.wantSampled {
+				t.Errorf("sampled = %v, want %v", spanCtx.IsSampled(), tt.wantSampled)
+			}
+			if !spanCtx.IsRemote() {
+				t.Error("span context is not marked remote")
+			}
+		})
+	}
+}
+
+func TestContextWithDatadogHeadersInvalid(t *testing.T) {
+	tests := map[string][2]string{
+		"missing trace id":    {"", "987654321"},
+		"missing parent id":   {"1234567890123456789", ""},
+		"hex trace id":        {"112210f47de98115", "987654321"},
+		"zero trace id":       {"0", "987654321"},
+		"overflowing span id": {"1234567890123456789", "18446744073709551616"},
+	}
+	for name, ids := range tests {
+		t.Run(name, func(t *testing.T) {
+			headers := http.Header{}
+			headers.Set("x-datadog-trace-id", ids[0])
+			headers.Set("x-datadog-parent-id", ids[1])
+			if _, ok := ContextWithDatadogHeaders(context.Background(), headers); ok {
+				t.Error("ContextWithDatadogHeaders() ok = true, want false")
+			}
+		})
+	}
+}
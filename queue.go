@@ -0,0 +1,126 @@
+package lumberjack
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+// OverflowPolicy decides what an exporter does when its in-memory queue
+// already holds Config.MaxQueueSize entries.
+type OverflowPolicy int
+
+const (
+	// DropOldest evicts the oldest queued entries to make room for new ones.
+	DropOldest OverflowPolicy = iota
+	// DropNewest discards incoming entries that don't fit.
+	DropNewest
+	// Block makes the exporting goroutine flush the queue itself before
+	// enqueuing, applying backpressure to the caller. It only bounds memory:
+	// a batch whose send fails is spooled or dropped, not requeued, and
+	// dropped entries are counted in ExporterStats.Dropped.
+	Block
+)
+
+func (p OverflowPolicy) String() string {
+	switch p {
+	case DropOldest:
+		return "DropOldest"
+	case DropNewest:
+		return "DropNewest"
+	case Block:
+		return "Block"
+	default:
+		return "OverflowPolicy(unknown)"
+	}
+}
+
+// ExporterStats is a point-in-time snapshot of an exporter's counters.
+type ExporterStats struct {
+	// Enqueued counts entries accepted into the queue.
+	Enqueued uint64
+	// Flushed counts entries delivered successfully.
+	Flushed uint64
+	// Dropped counts entries discarded because the queue was full, or
+	// because delivery failed and they were not spooled for replay.
+	Dropped uint64
+	// Retries counts send attempts repeated after a failure.
+	Retries uint64
+	// LastSuccess is when a batch was last delivered, or zero if never.
+	LastSuccess time.Time
+}
+
+// Stats is a point-in-time snapshot of the SDK's exporters, per signal.
+type Stats struct {
+	Logs    ExporterStats
+	Spans   ExporterStats
+	Metrics ExporterStats
+	// Circuit is the state of the circuit breaker shared by the default
+	// exporters; always CircuitClosed when the breaker is disabled.
+	Circuit CircuitState
+}
+
+// exporterStatsOf returns exporter's stats if it reports any.
+func exporterStatsOf(exporter any) ExporterStats {
+	if reporter, ok := exporter.(interface{ Stats() ExporterStats }); ok {
+		return reporter.Stats()
+	}
+	return ExporterStats{}
+}
+
+type exporterStats struct {
+	enqueued    atomic.Uint64
+	flushed     atomic.Uint64
+	dropped     atomic.Uint64
+	retries     atomic.Uint64
+	lastSuccess atomic.Int64 // unix nanoseconds
+}
+
+// recordFlushed counts n delivered entries and stamps the success time.
+func (s *exporterStats) recordFlushed(n int) {
+	s.flushed.Add(uint64(n))
+	s.lastSuccess.Store(time.Now().UnixNano())
+}
+
+func (s *exporterStats) snapshot() ExporterStats {
+	stats := ExporterStats{
+		Enqueued: s.enqueued.Load(),
+		Flushed:  s.flushed.Load(),
+		Dropped:  s.dropped.Load(),
+		Retries:  s.retries.Load(),
+	}
+	if ns := s.lastSuccess.Load(); ns != 0 {
+		stats.LastSuccess = time.Unix(0, ns)
+	}
+	return stats
+}
+
+// queueFull reports whether a Block policy exporter must flush before
+// enqueuing incoming more entries.
+func queueFull(config *Config, queued, incoming int) bool {
+	return config.MaxQueueSize > 0 && config.OverflowPolicy == Block &&
+		queued > 0 && queued+incoming > config.MaxQueueSize
+}
+
+// applyOverflowPolicy bounds queued+incoming to Config.MaxQueueSize. It
+// returns how many entries to evict from the front of queued and which
+// incoming entries to accept; everything else is dropped.
+func applyOverflowPolicy[T any](config *Config, queued, incoming []T) (evicted int, accepted []T) {
+	max := config.MaxQueueSize
+	if max <= 0 || config.OverflowPolicy == Block || len(queued)+len(incoming) <= max {
+		return 0, incoming
+	}
+
+	switch config.OverflowPolicy {
+	case DropNewest:
+		space := max - len(queued)
+		if space <= 0 {
+			return 0, nil
+		}
+		return 0, incoming[:space]
+	default: // DropOldest
+		if len(incoming) >= max {
+			return len(queued), incoming[len(incoming)-max:]
+		}
+		return len(queued) + len(incoming) - max, incoming
+	}
+}
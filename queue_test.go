@@ -0,0 +1,113 @@
+package lumberjack
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"go.opentelemetry.io/otel/log"
+	sdklog "go.opentelemetry.io/otel/sdk/log"
+)
+
+func TestLogsExporterOverflowPolicies(t *testing.T) {
+	tests := []struct {
+		policy       OverflowPolicy
+		wantDropped  uint64
+		wantFirstMsg string
+		wantLastMsg  string
+	}{
+		{DropOldest, 40, "log 40", "log 49"},
+		{DropNewest, 40, "log 0", "log 9"},
+		// Block doesn't drop for a full queue, but the failed flushes it
+		// makes room with are counted
+		{Block, 40, "log 40", "log 49"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.policy.String(), func(t *testing.T) {
+			server := newCaptureServer(t)
+			server.setStatus(http.StatusInternalServerError)
+
+			config := testConfig(server.URL).WithMaxQueueSize(10, tt.policy)
+			config.BatchSize = 1000
+			config.MaxRetries = 0
+			exporter := NewLogsExporter(config)
+			defer exporter.Shutdown(context.Background())
+
+			for i := 0; i < 50; i++ {
+				record := newTestRecord(fmt.Sprintf("log %d", i), log.SeverityInfo)
+				if err := exporter.Export(context.Background(), []*sdklog.Record{record}); err != nil {
+					t.Fatalf("Export() unexpected error = %v", err)
+				}
+
+				shard := &exporter.shards[0]
+				shard.mu.Lock()
+				queued := len(shard.batch)
+				shard.mu.Unlock()
+				if queued > 10 {
+					t.Fatalf("queue grew to %d entries, want at most 10", queued)
+				}
+			}
+
+			if got := exporter.Stats().Dropped; got != tt.wantDropped {
+				t.Errorf("Stats().Dropped = %d, want %d", got, tt.wantDropped)
+			}
+
+			shard := &exporter.shards[0]
+			shard.mu.Lock()
+			batch := append([]LogEntry(nil), shard.batch...)
+			shard.mu.Unlock()
+			if len(batch) != 10 {
+				t.Fatalf("queue holds %d entries, want 10", len(batch))
+			}
+			if batch[0].Msg != tt.wantFirstMsg || batch[9].Msg != tt.wantLastMsg {
+				t.Errorf("queue holds %q..%q, want %q..%q", batch[0].Msg, batch[9].Msg, tt.wantFirstMsg, tt.wantLastMsg)
+			}
+		})
+	}
+}
+
+func TestSDKStats(t *testing.T) {
+	server := newCaptureServer(t)
+	server.setStatus(http.StatusInternalServerError)
+
+	config := testConfig(server.URL).
+		WithMaxQueueSize(2, DropNewest).
+		WithBatchSize(1000).
+		WithMaxRetries(1).
+		WithRetryBackoff(time.Millisecond)
+	sdk := newSDK(config)
+	defer sdk.Shutdown(context.Background())
+
+	for i := 0; i < 5; i++ {
+		sdk.Logger().Info(fmt.Sprintf("log %d", i))
+	}
+	sdk.loggerProvider.ForceFlush(context.Background())
+
+	stats := sdk.Stats().Logs
+	// 3 dropped for the full queue, and the 2 queued once their send failed
+	if stats.Enqueued != 2 || stats.Dropped != 5 {
+		t.Errorf("Enqueued, Dropped = %d, %d, want 2, 5", stats.Enqueued, stats.Dropped)
+	}
+	if stats.Retries != 1 {
+		t.Errorf("Retries = %d, want 1", stats.Retries)
+	}
+	if stats.Flushed != 0 || !stats.LastSuccess.IsZero() {
+		t.Errorf("Flushed = %d, LastSuccess = %v after failed send, want 0 and zero time", stats.Flushed, stats.LastSuccess)
+	}
+
+	server.setStatus(http.StatusOK)
+	before := time.Now()
+	sdk.Logger().Info("recovered")
+	sdk.loggerProvider.ForceFlush(context.Background())
+
+	stats = sdk.Stats().Logs
+	if stats.Flushed != 1 {
+		t.Errorf("Flushed = %d, want 1", stats.Flushed)
+	}
+	if stats.LastSuccess.Before(before) {
+		t.Errorf("LastSuccess = %v, want after %v", stats.LastSuccess, before)
+	}
+}
@@ -0,0 +1,245 @@
+package lumberjack
+
+import (
+	"container/list"
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/otel/log"
+	sdklog "go.opentelemetry.io/otel/sdk/log"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// maxRateLimitKeys bounds how many distinct keys the limiter tracks. Idle
+// keys are forgotten first; past that the least recently seen key is
+// evicted, reporting its pending count.
+const maxRateLimitKeys = 10000
+
+// rateLimitSummaryInterval is how often summaries of keys still being
+// suppressed are emitted, so a key that never recovers is still reported.
+var rateLimitSummaryInterval = 10 * time.Second
+
+// rateLimitLogProcessor drops logs that exceed a per-key token bucket and
+// reports how many were dropped in a "suppressed N similar logs" summary,
+// emitted when the key is next allowed through, every
+// rateLimitSummaryInterval, on eviction or on flush.
+type rateLimitLogProcessor struct {
+	sdklog.Processor
+	rate float64
+	key  string
+
+	mu      sync.Mutex
+	buckets map[string]*list.Element
+	lru     *list.List
+	// template is a copy of the first record seen, so summaries inherit the
+	// provider's attribute limits; a zero Record truncates every string.
+	template *sdklog.Record
+
+	ticker   *time.Ticker
+	stopCh   chan struct{}
+	stopOnce sync.Once
+	wg       sync.WaitGroup
+}
+
+type logBucket struct {
+	key        string
+	tokens     float64
+	last       time.Time
+	suppressed int
+}
+
+func newRateLimitLogProcessor(next sdklog.Processor, config *Config) *rateLimitLogProcessor {
+	p := &rateLimitLogProcessor{
+		Processor: next,
+		rate:      config.RateLimitPerSecond,
+		key:       config.RateLimitKey,
+		buckets:   make(map[string]*list.Element),
+		lru:       list.New(),
+		ticker:    time.NewTicker(rateLimitSummaryInterval),
+		stopCh:    make(chan struct{}),
+	}
+	p.wg.Add(1)
+	go p.runSummaries()
+	return p
+}
+
+// runSummaries emits pending summaries on every tick until Shutdown.
+func (p *rateLimitLogProcessor) runSummaries() {
+	defer p.wg.Done()
+	for {
+		select {
+		case <-p.ticker.C:
+			p.flushSummaries(context.Background())
+		case <-p.stopCh:
+			return
+		}
+	}
+}
+
+func (p *rateLimitLogProcessor) OnEmit(ctx context.Context, record *sdklog.Record) error {
+	key := p.recordKey(record)
+	p.mu.Lock()
+	if p.template == nil {
+		template := record.Clone()
+		p.template = &template
+	}
+	p.mu.Unlock()
+
+	allowed, suppressed, evicted := p.allow(key, time.Now())
+	if evicted != nil {
+		summary := p.summary(evicted.key, evicted.suppressed)
+		if err := p.Processor.OnEmit(ctx, &summary); err != nil {
+			return err
+		}
+	}
+	if !allowed {
+		return nil
+	}
+	if suppressed > 0 {
+		summary := p.summary(key, suppressed)
+		if err := p.Processor.OnEmit(ctx, &summary); err != nil {
+			return err
+		}
+	}
+	return p.Processor.OnEmit(ctx, record)
+}
+
+// recordKey returns the value of the configured key attribute, falling back
+// to the log message.
+func (p *rateLimitLogProcessor) recordKey(record *sdklog.Record) string {
+	if p.key != "" {
+		var value string
+		record.WalkAttributes(func(kv log.KeyValue) bool {
+			if kv.Key == p.key {
+				value = kv.Value.String()
+				return false
+			}
+			return true
+		})
+		if value != "" {
+			return value
+		}
+	}
+	return record.Body().String()
+}
+
+// allow takes a token from key's bucket. When it succeeds it also returns, and
+// resets, the number of logs suppressed since the key was last allowed. If
+// making room for a new key evicted one with suppressed logs, it returns
+// that bucket too.
+func (p *rateLimitLogProcessor) allow(key string, now time.Time) (bool, int, *logBucket) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	burst := max(p.rate, 1)
+	var evicted *logBucket
+	elem, ok := p.buckets[key]
+	if ok {
+		p.lru.MoveToFront(elem)
+	} else {
+		if p.lru.Len() >= maxRateLimitKeys {
+			p.pruneLocked(now)
+		}
+		if p.lru.Len() >= maxRateLimitKeys {
+			oldest := p.lru.Remove(p.lru.Back()).(*logBucket)
+			delete(p.buckets, oldest.key)
+			if oldest.suppressed > 0 {
+				evicted = oldest
+			}
+		}
+		elem = p.lru.PushFront(&logBucket{key: key, tokens: burst, last: now})
+		p.buckets[key] = elem
+	}
+	b := elem.Value.(*logBucket)
+
+	b.tokens = min(burst, b.tokens+now.Sub(b.last).Seconds()*p.rate)
+	b.last = now
+	if b.tokens < 1 {
+		b.suppressed++
+		return false, 0, evicted
+	}
+	b.tokens--
+	suppressed := b.suppressed
+	b.suppressed = 0
+	return true, suppressed, evicted
+}
+
+// pruneLocked forgets keys whose bucket has refilled and that have nothing
+// left to report. Must be called with mu held.
+func (p *rateLimitLogProcessor) pruneLocked(now time.Time) {
+	burst := max(p.rate, 1)
+	for elem := p.lru.Back(); elem != nil; {
+		prev := elem.Prev()
+		b := elem.Value.(*logBucket)
+		if b.suppressed == 0 && b.tokens+now.Sub(b.last).Seconds()*p.rate >= burst {
+			p.lru.Remove(elem)
+			delete(p.buckets, b.key)
+		}
+		elem = prev
+	}
+}
+
+// flushSummaries emits a summary for every key with suppressed logs.
+func (p *rateLimitLogProcessor) flushSummaries(ctx context.Context) error {
+	p.mu.Lock()
+	if p.template == nil {
+		// Nothing has been emitted, so nothing was suppressed
+		p.mu.Unlock()
+		return nil
+	}
+	var summaries []sdklog.Record
+	for elem := p.lru.Front(); elem != nil; elem = elem.Next() {
+		b := elem.Value.(*logBucket)
+		if b.suppressed > 0 {
+			summaries = append(summaries, p.summary(b.key, b.suppressed))
+			b.suppressed = 0
+		}
+	}
+	p.mu.Unlock()
+
+	for i := range summaries {
+		if err := p.Processor.OnEmit(ctx, &summaries[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (p *rateLimitLogProcessor) ForceFlush(ctx context.Context) error {
+	if err := p.flushSummaries(ctx); err != nil {
+		return err
+	}
+	return p.Processor.ForceFlush(ctx)
+}
+
+func (p *rateLimitLogProcessor) Shutdown(ctx context.Context) error {
+	p.stopOnce.Do(func() {
+		p.ticker.Stop()
+		close(p.stopCh)
+	})
+	p.wg.Wait()
+	if err := p.flushSummaries(ctx); err != nil {
+		return err
+	}
+	return p.Processor.Shutdown(ctx)
+}
+
+// summary builds a "suppressed N similar logs" record for key.
+func (p *rateLimitLogProcessor) summary(key string, suppressed int) sdklog.Record {
+	r := p.template.Clone()
+	r.SetTimestamp(time.Now())
+	r.SetObservedTimestamp(time.Now())
+	r.SetSeverity(log.SeverityWarn)
+	r.SetSeverityText("")
+	r.SetBody(log.StringValue(fmt.Sprintf("suppressed %d similar logs", suppressed)))
+	r.SetTraceID(trace.TraceID{})
+	r.SetSpanID(trace.SpanID{})
+	r.SetTraceFlags(0)
+	r.SetAttributes(
+		log.String("suppressed.key", key),
+		log.Int("suppressed.count", suppressed),
+	)
+	return r
+}
@@ -0,0 +1,160 @@
+package lumberjack
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"go.opentelemetry.io/otel/log"
+	sdklog "go.opentelemetry.io/otel/sdk/log"
+)
+
+func TestRateLimitSuppressesNoisyLogs(t *testing.T) {
+	server := newCaptureServer(t)
+	sdk := newSDK(testConfig(server.URL).WithRateLimit(5))
+
+	for i := 0; i < 1000; i++ {
+		sdk.Logger().Error("db connection refused")
+	}
+	sdk.Logger().Info("unrelated")
+	sdk.loggerProvider.ForceFlush(context.Background())
+	sdk.Shutdown(context.Background())
+
+	counts := make(map[string]int)
+	var suppressed int64
+	for _, req := range server.decodeLogRequests(t) {
+		for _, entry := range req.Logs {
+			counts[entry.Msg]++
+			if entry.Props["suppressed.key"] == "db connection refused" {
+				count, err := strconv.ParseInt(fmt.Sprint(entry.Props["suppressed.count"]), 10, 64)
+				if err != nil {
+					t.Fatalf("suppressed.count = %v, want an integer", entry.Props["suppressed.count"])
+				}
+				suppressed += count
+			}
+		}
+	}
+
+	hot := counts["db connection refused"]
+	if hot < 5 || hot > 6 {
+		t.Errorf("exported %d copies of the noisy log, want about 5", hot)
+	}
+	if counts["unrelated"] != 1 {
+		t.Errorf("exported %d copies of an unrelated log, want 1", counts["unrelated"])
+	}
+	if suppressed != int64(1000-hot) {
+		t.Errorf("summaries report %d suppressed logs, want %d", suppressed, 1000-hot)
+	}
+}
+
+func TestRateLimitRefills(t *testing.T) {
+	p := newRateLimitLogProcessor(nil, testConfig("").WithRateLimit(10))
+	now := time.Now()
+
+	for i := 0; i < 10; i++ {
+		if ok, _, _ := p.allow("key", now); !ok {
+			t.Fatalf("log %d denied within the burst", i)
+		}
+	}
+	if ok, _, _ := p.allow("key", now); ok {
+		t.Fatal("log allowed beyond the burst")
+	}
+	ok, suppressed, _ := p.allow("key", now.Add(100*time.Millisecond))
+	if !ok || suppressed != 1 {
+		t.Errorf("allow() after refill = %v, %d, want true, 1", ok, suppressed)
+	}
+}
+
+func TestRateLimitEvictsAtKeyCap(t *testing.T) {
+	p := newRateLimitLogProcessor(&recordingProcessor{}, testConfig("").WithRateLimit(1))
+	defer p.Shutdown(context.Background())
+	now := time.Now()
+
+	// Every key exhausted and suppressing, so none can be pruned as idle
+	for i := 0; i < maxRateLimitKeys; i++ {
+		key := fmt.Sprintf("key %d", i)
+		p.allow(key, now)
+		p.allow(key, now)
+	}
+	_, _, evicted := p.allow("one more", now)
+
+	if evicted == nil || evicted.key != "key 0" || evicted.suppressed != 1 {
+		t.Errorf("evicted = %+v, want the least recently seen key with its pending count", evicted)
+	}
+	if got := len(p.buckets); got != maxRateLimitKeys {
+		t.Errorf("tracking %d keys, want the cap of %d", got, maxRateLimitKeys)
+	}
+}
+
+// recordingProcessor records the body of every log emitted to it.
+type recordingProcessor struct {
+	mu     sync.Mutex
+	bodies []string
+}
+
+func (p *recordingProcessor) OnEmit(ctx context.Context, record *sdklog.Record) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.bodies = append(p.bodies, record.Body().String())
+	return nil
+}
+
+func (p *recordingProcessor) Shutdown(context.Context) error   { return nil }
+func (p *recordingProcessor) ForceFlush(context.Context) error { return nil }
+
+func (p *recordingProcessor) emitted() []string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return append([]string(nil), p.bodies...)
+}
+
+func TestRateLimitPeriodicSummaries(t *testing.T) {
+	defer func(interval time.Duration) { rateLimitSummaryInterval = interval }(rateLimitSummaryInterval)
+	rateLimitSummaryInterval = 20 * time.Millisecond
+
+	next := &recordingProcessor{}
+	p := newRateLimitLogProcessor(next, testConfig("").WithRateLimit(1))
+	defer p.Shutdown(context.Background())
+
+	for i := 0; i < 5; i++ {
+		p.OnEmit(context.Background(), newTestRecord("stuck", log.SeverityError))
+	}
+
+	deadline := time.Now().Add(2 * time.Second)
+	for time.Now().Before(deadline) {
+		if bodies := next.emitted(); len(bodies) == 2 {
+			if bodies[1] != "suppressed 4 similar logs" {
+				t.Errorf("emitted %q, want the summary after the allowed log", bodies)
+			}
+			return
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Fatalf("emitted %q, want a summary without a flush", next.emitted())
+}
+
+func TestRateLimitKeyAttribute(t *testing.T) {
+	server := newCaptureServer(t)
+	sdk := newSDK(testConfig(server.URL).WithRateLimit(1).WithRateLimitKey("route"))
+
+	for i := 0; i < 10; i++ {
+		sdk.Logger().Info("request failed", "route", "/a", "attempt", i)
+		sdk.Logger().Info("request failed", "route", "/b", "attempt", i)
+	}
+	sdk.Shutdown(context.Background())
+
+	routes := make(map[string]int)
+	for _, req := range server.decodeLogRequests(t) {
+		for _, entry := range req.Logs {
+			if entry.Msg == "request failed" {
+				routes[entry.Props["route"].(string)]++
+			}
+		}
+	}
+	if routes["/a"] != 1 || routes["/b"] != 1 {
+		t.Errorf("exported logs per route = %v, want one each", routes)
+	}
+}
@@ -0,0 +1,109 @@
+package lumberjack
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"runtime"
+	"runtime/debug"
+	"strings"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Recover reports a panic in progress and then re-panics with the same value.
+// The panic value and stack are recorded on the span in ctx, if any, and
+// logged at ERROR. It must be deferred directly:
+//
+//	defer sdk.Recover(ctx)
+func (s *SDK) Recover(ctx context.Context) {
+	if v := recover(); v != nil {
+		s.reportPanic(ctx, v, debug.Stack())
+		panic(v)
+	}
+}
+
+// RecoverMiddleware wraps next so that a panicking request is reported like
+// Recover and answered with 500 Internal Server Error instead of crashing the
+// connection. http.ErrAbortHandler is re-panicked untouched, as net/http
+// expects. Install it inside HTTPMiddleware so the panic lands on the request
+// span.
+func (s *SDK) RecoverMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		defer func() {
+			v := recover()
+			if v == nil {
+				return
+			}
+			if v == http.ErrAbortHandler {
+				panic(v)
+			}
+			s.reportPanic(r.Context(), v, debug.Stack())
+			http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		}()
+		next.ServeHTTP(w, r)
+	})
+}
+
+func (s *SDK) reportPanic(ctx context.Context, v any, stack []byte) {
+	err, ok := v.(error)
+	if !ok {
+		err = fmt.Errorf("%v", v)
+	}
+	msg := fmt.Sprintf("panic: %v", v)
+
+	span := trace.SpanFromContext(ctx)
+	span.RecordError(err, trace.WithAttributes(
+		attribute.String("exception.stacktrace", string(stack)),
+	))
+	span.SetStatus(codes.Error, msg)
+
+	s.logger.logAttrsPC(ctx, panicPC(), slog.LevelError, msg,
+		slog.String("panic", fmt.Sprint(v)),
+		slog.String("stack", string(stack)),
+	)
+}
+
+// panicPC returns the program counter of the function that panicked, skipping
+// the recovery frames and the runtime's panic machinery, or 0 if it cannot be
+// found. It must be called while a deferred function is handling a panic.
+func panicPC() uintptr {
+	var pcs [64]uintptr
+	n := runtime.Callers(2, pcs[:])
+	inPanic := false
+	for _, pc := range pcs[:n] {
+		frame, _ := runtime.CallersFrames([]uintptr{pc}).Next()
+		switch {
+		case frame.Function == "runtime.gopanic":
+			inPanic = true
+		case inPanic && !strings.HasPrefix(frame.Function, "runtime."):
+			return pc
+		}
+	}
+	return 0
+}
+
+// Recover reports a panic in progress using the global SDK and re-panics.
+// Before Init it only re-panics. It must be deferred directly:
+//
+//	defer lumberjack.Recover(ctx)
+func Recover(ctx context.Context) {
+	if v := recover(); v != nil {
+		if sdk, ok := TryGet(); ok {
+			sdk.reportPanic(ctx, v, debug.Stack())
+		}
+		panic(v)
+	}
+}
+
+// RecoverMiddleware wraps next with the global SDK's panic recovery. Before
+// Init it returns next unchanged.
+func RecoverMiddleware(next http.Handler) http.Handler {
+	if sdk, ok := TryGet(); ok {
+		return sdk.RecoverMiddleware(next)
+	}
+	return next
+}
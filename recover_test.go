@@ -0,0 +1,134 @@
+package lumberjack
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"runtime"
+	"strings"
+	"testing"
+
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func newRecoverTestSDK(t *testing.T) (*SDK, *captureServer, *tracetest.InMemoryExporter) {
+	t.Helper()
+	server := newCaptureServer(t)
+	spans := tracetest.NewInMemoryExporter()
+	sdk := newSDK(testConfig(server.URL).WithCustomSpanExporter(spans))
+	return sdk, server, spans
+}
+
+// panicLogs returns the exported log entries produced by a recovered panic.
+func panicLogs(t *testing.T, server *captureServer) []LogEntry {
+	t.Helper()
+	var entries []LogEntry
+	for _, req := range server.decodeLogRequests(t) {
+		for _, entry := range req.Logs {
+			if strings.HasPrefix(entry.Msg, "panic: ") {
+				entries = append(entries, entry)
+			}
+		}
+	}
+	return entries
+}
+
+func panicsInSpan(sdk *SDK) {
+	ctx, span := sdk.StartSpan(context.Background(), "risky")
+	defer span.End()
+	defer sdk.Recover(ctx)
+
+	panic("boom")
+}
+
+func TestRecover(t *testing.T) {
+	sdk, server, spans := newRecoverTestSDK(t)
+
+	var repanicked any
+	func() {
+		defer func() { repanicked = recover() }()
+		panicsInSpan(sdk)
+	}()
+	if repanicked != "boom" {
+		t.Errorf("Recover() re-panicked with %v, want %q", repanicked, "boom")
+	}
+
+	sdk.tracerProvider.ForceFlush(context.Background())
+	got := spans.GetSpans()
+	if len(got) != 1 {
+		t.Fatalf("expected 1 exported span, got %d", len(got))
+	}
+	if got[0].Status.Code != codes.Error {
+		t.Errorf("span status = %v, want Error", got[0].Status.Code)
+	}
+	var stack string
+	for _, event := range got[0].Events {
+		for _, attr := range event.Attributes {
+			if attr.Key == "exception.stacktrace" {
+				stack = attr.Value.AsString()
+			}
+		}
+	}
+	if !strings.Contains(stack, "panicsInSpan") {
+		t.Errorf("span exception.stacktrace does not mention the panicking function:\n%s", stack)
+	}
+
+	sdk.Shutdown(context.Background())
+	logs := panicLogs(t, server)
+	if len(logs) != 1 {
+		t.Fatalf("expected 1 panic log, got %d", len(logs))
+	}
+	if logs[0].Lvl != "ERROR" || logs[0].Msg != "panic: boom" {
+		t.Errorf("panic log = %s %q, want ERROR %q", logs[0].Lvl, logs[0].Msg, "panic: boom")
+	}
+	if logStack, _ := logs[0].Props["stack"].(string); !strings.Contains(logStack, "panicsInSpan") {
+		t.Errorf("panic log stack does not mention the panicking function:\n%s", logStack)
+	}
+	if !strings.HasSuffix(logs[0].Fn, "panicsInSpan") {
+		t.Errorf("panic log source function = %q, want panicsInSpan", logs[0].Fn)
+	}
+}
+
+func TestRecoverMiddleware(t *testing.T) {
+	sdk, server, _ := newRecoverTestSDK(t)
+
+	handler := sdk.HTTPMiddleware(sdk.RecoverMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var m map[string]int
+		m["boom"]++ // assignment to entry in nil map
+	})))
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+	if rec.Code != http.StatusInternalServerError {
+		t.Errorf("status = %d, want 500", rec.Code)
+	}
+
+	sdk.Shutdown(context.Background())
+	logs := panicLogs(t, server)
+	if len(logs) != 1 {
+		t.Fatalf("expected 1 panic log, got %d", len(logs))
+	}
+	if !strings.Contains(logs[0].Msg, "nil map") {
+		t.Errorf("panic log message = %q, want the runtime error", logs[0].Msg)
+	}
+	if _, file, _, _ := runtime.Caller(0); logs[0].Fl != file {
+		t.Errorf("panic log source file = %q, want %q", logs[0].Fl, file)
+	}
+}
+
+func TestRecoverMiddlewareAbortHandler(t *testing.T) {
+	sdk, _, _ := newRecoverTestSDK(t)
+	defer sdk.Shutdown(context.Background())
+
+	handler := sdk.RecoverMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		panic(http.ErrAbortHandler)
+	}))
+
+	defer func() {
+		if v := recover(); v != http.ErrAbortHandler {
+			t.Errorf("recovered %v, want http.ErrAbortHandler to propagate", v)
+		}
+	}()
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+}
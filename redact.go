@@ -0,0 +1,163 @@
+package lumberjack
+
+import (
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+const (
+	// redactedValue replaces attribute values matched by RedactKeys or
+	// RedactValuePatterns.
+	redactedValue = "[REDACTED]"
+	// scrubbedQueryValue replaces query parameter values in URL attributes.
+	scrubbedQueryValue = "REDACTED"
+)
+
+// redactor applies Config.RedactKeys and Config.RedactValuePatterns to
+// attribute key/value pairs.
+type redactor struct {
+	keys     map[string]struct{}
+	patterns []*regexp.Regexp
+}
+
+func newRedactor(config *Config) *redactor {
+	return &redactor{
+		keys:     toLowerSet(config.RedactKeys),
+		patterns: config.RedactValuePatterns,
+	}
+}
+
+func (r *redactor) enabled() bool {
+	return len(r.keys) > 0 || len(r.patterns) > 0
+}
+
+// matchesKey reports whether key, or its last dot-separated segment (so that
+// slog group prefixes such as "db.password" still match "password"), is a
+// redacted key. Matching is case-insensitive.
+func (r *redactor) matchesKey(key string) bool {
+	if len(r.keys) == 0 {
+		return false
+	}
+	key = strings.ToLower(key)
+	if _, ok := r.keys[key]; ok {
+		return true
+	}
+	if i := strings.LastIndexByte(key, '.'); i >= 0 {
+		_, ok := r.keys[key[i+1:]]
+		return ok
+	}
+	return false
+}
+
+func (r *redactor) matchesValue(value string) bool {
+	for _, pattern := range r.patterns {
+		if pattern.MatchString(value) {
+			return true
+		}
+	}
+	return false
+}
+
+// redactStrings redacts matching entries of a string attribute map in place.
+func (r *redactor) redactStrings(attrs map[string]string) {
+	if !r.enabled() {
+		return
+	}
+	for key, value := range attrs {
+		if r.matchesKey(key) || r.matchesValue(value) {
+			attrs[key] = redactedValue
+		}
+	}
+}
+
+// redactProps redacts matching entries of a log props map in place. Only
+// string values are checked against RedactValuePatterns.
+func (r *redactor) redactProps(props map[string]interface{}) {
+	if !r.enabled() {
+		return
+	}
+	for key, value := range props {
+		if r.matchesKey(key) {
+			props[key] = redactedValue
+			continue
+		}
+		if str, ok := value.(string); ok && r.matchesValue(str) {
+			props[key] = redactedValue
+		}
+	}
+}
+
+// isURLAttribute reports whether an attribute key conventionally holds a URL
+// or a query string.
+func isURLAttribute(key string) bool {
+	key = strings.ToLower(key)
+	switch key {
+	case "http.url", "http.target", "url.full", "url.query":
+		return true
+	}
+	return strings.HasSuffix(key, ".url") || strings.HasSuffix(key, "_url")
+}
+
+// scrubURLAttributes replaces query parameter values in URL-valued attributes
+// with REDACTED unless the parameter name is in safeParams.
+func scrubURLAttributes(attrs map[string]string, safeParams map[string]struct{}) {
+	for key, value := range attrs {
+		if !isURLAttribute(key) {
+			continue
+		}
+		if strings.ToLower(key) == "url.query" {
+			attrs[key] = scrubQuery(value, safeParams)
+			continue
+		}
+		attrs[key] = scrubURL(value, safeParams)
+	}
+}
+
+// scrubURL redacts the query string of raw, leaving the path and any
+// fragment untouched.
+func scrubURL(raw string, safeParams map[string]struct{}) string {
+	q := strings.IndexByte(raw, '?')
+	if q < 0 {
+		return raw
+	}
+
+	query, fragment := raw[q+1:], ""
+	if f := strings.IndexByte(query, '#'); f >= 0 {
+		query, fragment = query[:f], query[f:]
+	}
+	return raw[:q+1] + scrubQuery(query, safeParams) + fragment
+}
+
+// scrubQuery redacts every parameter value in query except allowlisted ones,
+// preserving parameter order.
+func scrubQuery(query string, safeParams map[string]struct{}) string {
+	if query == "" {
+		return query
+	}
+
+	pairs := strings.Split(query, "&")
+	for i, pair := range pairs {
+		key, _, hasValue := strings.Cut(pair, "=")
+		if !hasValue {
+			continue
+		}
+		name, err := url.QueryUnescape(key)
+		if err != nil {
+			name = key
+		}
+		if _, ok := safeParams[strings.ToLower(name)]; ok {
+			continue
+		}
+		pairs[i] = key + "=" + scrubbedQueryValue
+	}
+	return strings.Join(pairs, "&")
+}
+
+func toLowerSet(values []string) map[string]struct{} {
+	set := make(map[string]struct{}, len(values))
+	for _, v := range values {
+		set[strings.ToLower(v)] = struct{}{}
+	}
+	return set
+}
@@ -0,0 +1,74 @@
+package lumberjack
+
+import (
+	"context"
+	"log/slog"
+	"regexp"
+	"testing"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestLogsRedaction(t *testing.T) {
+	server := newCaptureServer(t)
+
+	config := testConfig(server.URL).
+		WithRedactKeys("password", "Authorization").
+		WithRedactValuePatterns(regexp.MustCompile(`^sk_live_`))
+	sdk := newSDK(config)
+
+	sdk.Logger().LogAttrs(context.Background(), slog.LevelInfo, "user login",
+		slog.Group("db", slog.String("password", "hunter2"), slog.String("host", "db.internal")),
+		slog.String("AUTHORIZATION", "Bearer abc"),
+		slog.String("stripe_key", "sk_live_123"),
+		slog.String("user", "alice"),
+	)
+	sdk.Shutdown(context.Background())
+
+	requests := server.decodeLogRequests(t)
+	if len(requests) != 1 || len(requests[0].Logs) != 1 {
+		t.Fatalf("expected a single exported log, got %+v", requests)
+	}
+	props := requests[0].Logs[0].Props
+
+	want := map[string]interface{}{
+		"db":            `{"host":"db.internal","password":"[REDACTED]"}`,
+		"AUTHORIZATION": "[REDACTED]",
+		"stripe_key":    "[REDACTED]",
+		"user":          "alice",
+	}
+	for key, value := range want {
+		if props[key] != value {
+			t.Errorf("props[%q] = %v, want %v", key, props[key], value)
+		}
+	}
+}
+
+func TestSpanRedaction(t *testing.T) {
+	config := testConfig("http://127.0.0.1:0").
+		WithRedactKeys("api_key").
+		WithRedactValuePatterns(regexp.MustCompile(`\d{4}-\d{4}-\d{4}-\d{4}`))
+	exporter := newTestSpanExporter(t, config)
+
+	stub := tracetest.SpanStub{
+		Attributes: []attribute.KeyValue{
+			attribute.String("client.API_KEY", "abc"),
+			attribute.String("card", "4111-1111-1111-1111"),
+			attribute.String("region", "eu"),
+		},
+	}
+
+	span := exporter.convertSpan(stub.Snapshot())
+
+	want := map[string]string{
+		"client.API_KEY": "[REDACTED]",
+		"card":           "[REDACTED]",
+		"region":         "eu",
+	}
+	for key, value := range want {
+		if got := span.Attributes[key]; got != value {
+			t.Errorf("attribute %q = %q, want %q", key, got, value)
+		}
+	}
+}
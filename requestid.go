@@ -0,0 +1,85 @@
+package lumberjack
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"log/slog"
+	"net/http"
+)
+
+// DefaultRequestIDHeader is the header RequestIDMiddleware uses when none is
+// given.
+const DefaultRequestIDHeader = "X-Request-ID"
+
+// requestIDAttrKey is the log attribute carrying a request's ID.
+const requestIDAttrKey = "request_id"
+
+// maxRequestIDLength bounds incoming IDs; longer ones are replaced.
+const maxRequestIDLength = 128
+
+type requestIDKey struct{}
+
+// RequestIDMiddleware returns middleware that tags every request with a
+// correlation ID: the incoming value of header (X-Request-ID if empty), or a
+// new random ID if it is absent. The ID is stored in the request context,
+// added as a "request_id" attribute to every log made with that context, and
+// echoed in the same response header:
+//
+//	handler = sdk.RequestIDMiddleware("")(sdk.HTTPMiddleware(mux))
+func (s *SDK) RequestIDMiddleware(header string) func(http.Handler) http.Handler {
+	if header == "" {
+		header = DefaultRequestIDHeader
+	}
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			id := r.Header.Get(header)
+			if id == "" || len(id) > maxRequestIDLength {
+				id = newRequestID()
+			}
+			w.Header().Set(header, id)
+			ctx := context.WithValue(r.Context(), requestIDKey{}, id)
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+// RequestIDMiddleware returns the global SDK's request ID middleware. Before
+// Init it returns middleware that passes requests through unchanged.
+func RequestIDMiddleware(header string) func(http.Handler) http.Handler {
+	if sdk, ok := TryGet(); ok {
+		return sdk.RequestIDMiddleware(header)
+	}
+	return func(next http.Handler) http.Handler { return next }
+}
+
+// RequestIDFromContext returns the request ID set by RequestIDMiddleware, or
+// "" if there is none.
+func RequestIDFromContext(ctx context.Context) string {
+	if ctx == nil {
+		return ""
+	}
+	id, _ := ctx.Value(requestIDKey{}).(string)
+	return id
+}
+
+func newRequestID() string {
+	var b [16]byte
+	rand.Read(b[:])
+	return hex.EncodeToString(b[:])
+}
+
+// withRequestIDAttr wraps extract so the request ID in the context, if any,
+// is added ahead of the attributes extract returns. extract may be nil.
+func withRequestIDAttr(extract func(context.Context) []slog.Attr) func(context.Context) []slog.Attr {
+	return func(ctx context.Context) []slog.Attr {
+		var attrs []slog.Attr
+		if extract != nil {
+			attrs = extract(ctx)
+		}
+		if id := RequestIDFromContext(ctx); id != "" {
+			attrs = append([]slog.Attr{slog.String(requestIDAttrKey, id)}, attrs...)
+		}
+		return attrs
+	}
+}
@@ -0,0 +1,64 @@
+package lumberjack
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestRequestIDMiddleware(t *testing.T) {
+	server := newCaptureServer(t)
+	sdk := newSDK(testConfig(server.URL))
+
+	var seen []string
+	handler := sdk.RequestIDMiddleware("")(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		seen = append(seen, RequestIDFromContext(r.Context()))
+		sdk.Logger().InfoContext(r.Context(), "handled")
+	}))
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest("GET", "/orders", nil))
+	generated := rec.Header().Get(DefaultRequestIDHeader)
+	if len(generated) != 32 {
+		t.Errorf("generated request ID = %q, want 32 hex characters", generated)
+	}
+
+	req := httptest.NewRequest("GET", "/orders", nil)
+	req.Header.Set(DefaultRequestIDHeader, "req-123")
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	if got := rec.Header().Get(DefaultRequestIDHeader); got != "req-123" {
+		t.Errorf("response request ID = %q, want the incoming req-123", got)
+	}
+	sdk.Shutdown(context.Background())
+
+	if len(seen) != 2 || seen[0] != generated || seen[1] != "req-123" {
+		t.Errorf("context request IDs = %v, want [%s req-123]", seen, generated)
+	}
+	var logged []any
+	for _, req := range server.decodeLogRequests(t) {
+		for _, entry := range req.Logs {
+			logged = append(logged, entry.Props[requestIDAttrKey])
+		}
+	}
+	if len(logged) != 2 || logged[0] != generated || logged[1] != "req-123" {
+		t.Errorf("logged request IDs = %v, want [%s req-123]", logged, generated)
+	}
+}
+
+func TestRequestIDMiddlewareCustomHeader(t *testing.T) {
+	sdk := &SDK{}
+	handler := sdk.RequestIDMiddleware("X-Correlation-ID")(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+
+	req := httptest.NewRequest("GET", "/", nil)
+	req.Header.Set("X-Correlation-ID", "corr-1")
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	if got := rec.Header().Get("X-Correlation-ID"); got != "corr-1" {
+		t.Errorf("X-Correlation-ID = %q, want corr-1", got)
+	}
+	if got := rec.Header().Get(DefaultRequestIDHeader); got != "" {
+		t.Errorf("%s = %q, want it unset with a custom header", DefaultRequestIDHeader, got)
+	}
+}
@@ -2,22 +2,28 @@ package lumberjack
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log"
 	"log/slog"
 	"os"
+	"runtime"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/metric"
+	metricnoop "go.opentelemetry.io/otel/metric/noop"
 	sdklog "go.opentelemetry.io/otel/sdk/log"
 	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
 	"go.opentelemetry.io/otel/sdk/resource"
 	sdktrace "go.opentelemetry.io/otel/sdk/trace"
 	semconv "go.opentelemetry.io/otel/semconv/v1.17.0"
 	"go.opentelemetry.io/otel/trace"
+	tracenoop "go.opentelemetry.io/otel/trace/noop"
 )
 
 var (
@@ -25,11 +31,36 @@ var (
 	once      sync.Once
 )
 
+// ErrNotInitialized is returned by package-level functions that need the
+// global SDK when Init hasn't been called.
+var ErrNotInitialized = errors.New("lumberjack SDK not initialized")
+
+// Before Init the package-level accessors return these no-op values instead
+// of panicking.
+var (
+	noopLogger = NewLogger(discardHandler{})
+	noopTracer = tracenoop.NewTracerProvider().Tracer("")
+	noopMeter  = metricnoop.NewMeterProvider().Meter("")
+)
+
+// Errors returned (wrapped) by ContextWithTraceparent so callers can tell
+// the failure modes apart with errors.Is.
+var (
+	// ErrTraceparentFormat is returned when the header is structurally malformed.
+	ErrTraceparentFormat = errors.New("malformed traceparent")
+	// ErrTraceparentVersion is returned when the header uses an unsupported version.
+	ErrTraceparentVersion = errors.New("unsupported traceparent version")
+	// ErrTraceparentZero is returned when the trace ID or span ID is all zeros.
+	ErrTraceparentZero = errors.New("all-zero traceparent id")
+)
+
 type SDK struct {
 	config               *Config
 	logger               *Logger
+	logLevel             *exportLevel
 	tracer               trace.Tracer
 	meter                metric.Meter
+	metrics              *Metrics
 	spanExporter         sdktrace.SpanExporter
 	logsExporter         LogsExporter
 	metricsExporter      sdkmetric.Exporter
@@ -39,6 +70,8 @@ type SDK struct {
 	defaultSpanExporter  *SpanExporter
 	defaultLogsExporter  *DefaultLogsExporter
 	defaultMetricsExporter *MetricsExporter
+	breaker              *circuitBreaker
+	namedLoggers         sync.Map // component name -> *Logger
 }
 
 func Init(config *Config) *SDK {
@@ -48,6 +81,19 @@ func Init(config *Config) *SDK {
 	return globalSDK
 }
 
+// InitE is like Init but validates config first, returning the error from
+// Config.Validate instead of initializing a best-effort SDK. A nil config is
+// replaced by NewConfig, as in Init.
+func InitE(config *Config) (*SDK, error) {
+	if config == nil {
+		config = NewConfig()
+	}
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+	return Init(config), nil
+}
+
 func InitWithConfig(cfg Config) *SDK {
 	return Init(&cfg)
 }
@@ -59,21 +105,31 @@ func Get() *SDK {
 	return globalSDK
 }
 
+// TryGet returns the global SDK, or false if Init hasn't been called. Unlike
+// Get it never panics, for code that only logs when an application has
+// initialized the SDK.
+func TryGet() (*SDK, bool) {
+	return globalSDK, globalSDK != nil
+}
+
 func newSDK(config *Config) *SDK {
 	if config == nil {
 		config = NewConfig()
 	}
 	
 	if config.APIKey == "" && !config.Debug {
-		fmt.Println("Warning: Lumberjack SDK initialized without API key. Logs will only go to stdout.")
+		config.warnf("Lumberjack SDK initialized without API key. Logs will only go to stdout.\n")
 	}
 	
+	// One breaker for all default exporters: they share an endpoint
+	breaker := newCircuitBreaker(config)
+	
 	var logsExporter LogsExporter
 	var defaultLogsExporter *DefaultLogsExporter
 	if config.CustomLogsExporter != nil {
 		logsExporter = config.CustomLogsExporter
 	} else {
-		defaultLogsExporter = NewLogsExporter(config)
+		defaultLogsExporter = newLogsExporter(config, breaker)
 		logsExporter = defaultLogsExporter
 	}
 	
@@ -82,7 +138,7 @@ func newSDK(config *Config) *SDK {
 	if config.CustomSpanExporter != nil {
 		spanExporter = config.CustomSpanExporter
 	} else {
-		defaultSpanExporter = NewSpanExporter(config)
+		defaultSpanExporter = newSpanExporter(config, breaker)
 		spanExporter = defaultSpanExporter
 	}
 	
@@ -91,48 +147,84 @@ func newSDK(config *Config) *SDK {
 	if config.CustomMetricsExporter != nil {
 		metricsExporter = config.CustomMetricsExporter
 	} else {
-		defaultMetricsExporter = NewMetricsExporter(config)
+		defaultMetricsExporter = newMetricsExporter(config, breaker)
 		metricsExporter = defaultMetricsExporter
 	}
 	
 	res, err := resource.New(context.Background(),
-		resource.WithAttributes(
-			semconv.ServiceName(config.ProjectName),
-			semconv.ServiceVersion(os.Getenv("LUMBERJACK_SERVICE_VERSION")),
-		),
+		resource.WithAttributes(resourceAttributes(config)...),
 	)
-	if err != nil && config.Debug {
-		fmt.Printf("Failed to create resource: %v\n", err)
+	if err != nil {
+		config.debugf("Failed to create resource: %v\n", err)
+	}
+	
+	var spanProcessor sdktrace.SpanProcessor
+	if config.SpanProcessor == SimpleSpanProcessor {
+		spanProcessor = sdktrace.NewSimpleSpanProcessor(spanExporter)
+	} else {
+		spanProcessor = sdktrace.NewBatchSpanProcessor(spanExporter)
 	}
 	
-	tracerProvider := sdktrace.NewTracerProvider(
-		sdktrace.WithBatcher(spanExporter),
+	tracerOptions := []sdktrace.TracerProviderOption{
+		sdktrace.WithSpanProcessor(spanProcessor),
 		sdktrace.WithResource(res),
-	)
+	}
+	if config.Sampler != nil {
+		tracerOptions = append(tracerOptions, sdktrace.WithSampler(config.Sampler))
+	}
+	tracerProvider := sdktrace.NewTracerProvider(tracerOptions...)
 	otel.SetTracerProvider(tracerProvider)
+	otel.SetTextMapPropagator(config.propagator())
 	
-	meterProvider := sdkmetric.NewMeterProvider(
+	meterOptions := []sdkmetric.Option{
 		sdkmetric.WithResource(res),
+		sdkmetric.WithView(histogramViews(config)...),
 		sdkmetric.WithReader(sdkmetric.NewPeriodicReader(
 			metricsExporter,
 			sdkmetric.WithInterval(30*time.Second),
 		)),
-	)
+	}
+	for _, reader := range config.MetricReaders {
+		meterOptions = append(meterOptions, sdkmetric.WithReader(reader))
+	}
+	meterProvider := sdkmetric.NewMeterProvider(meterOptions...)
 	otel.SetMeterProvider(meterProvider)
+	meter := meterProvider.Meter("lumberjack")
 	
 	// Create OpenTelemetry log provider with our exporter
-	logProcessor := NewLumberjackLogProcessor(logsExporter)
+	var logProcessor sdklog.Processor = NewLumberjackLogProcessor(logsExporter)
+	if config.LogsAsSpanEvents {
+		logProcessor = newSpanEventLogProcessor(logProcessor, config)
+	}
+	if config.RateLimitPerSecond > 0 {
+		logProcessor = newRateLimitLogProcessor(logProcessor, config)
+	}
+	if config.DedupeWindow > 0 {
+		// Outside the rate limiter, so duplicates don't use up its budget
+		logProcessor = newDedupeLogProcessor(logProcessor, config)
+	}
+	if config.AutoErrorMetric {
+		// Outermost, so errors dropped by the rate limiter still count
+		errorMetric, err := newErrorMetricLogProcessor(logProcessor, meter)
+		if err != nil {
+			config.debugf("Failed to create error log metric: %v\n", err)
+		} else {
+			logProcessor = errorMetric
+		}
+	}
 	loggerProvider := sdklog.NewLoggerProvider(
 		sdklog.WithResource(res),
 		sdklog.WithProcessor(logProcessor),
 	)
 
 	base := baselineHandler() // <-- CLEAN handler, never Lumberjack
+	
+	logLevel := &exportLevel{config: config}
 
 	var handler slog.Handler
 	if config.ReplaceSlog {
 		// Create the OpenTelemetry slog bridge handler
-		handler = CreateLumberjackSlogHandler(loggerProvider, base)
+		handler = newLumberjackSlogHandler(loggerProvider, base, logLevel, withRequestIDAttr(config.ContextAttrExtractor))
 		slog.SetDefault(slog.New(handler))
 
 		if config.CaptureStdLog {
@@ -142,16 +234,26 @@ func newSDK(config *Config) *SDK {
 		}
 	} else {
 		// Create handler but don't set as default
-		handler = CreateLumberjackSlogHandler(loggerProvider, base)
+		handler = newLumberjackSlogHandler(loggerProvider, base, logLevel, withRequestIDAttr(config.ContextAttrExtractor))
 	}
 		
 	logger := NewLogger(handler)
+	logger.warnings = config.debugWriter()
+	
+	metrics, err := NewMetrics(meter)
+	if err != nil {
+		config.debugf("Failed to create metrics: %v\n", err)
+	} else {
+		metrics.semconv = config.SemconvHTTPMetrics
+	}
 	
 	sdk := &SDK{
 		config:                 config,
 		logger:                 logger,
+		logLevel:               logLevel,
 		tracer:                 tracerProvider.Tracer("lumberjack"),
-		meter:                  meterProvider.Meter("lumberjack"),
+		meter:                  meter,
+		metrics:                metrics,
 		spanExporter:           spanExporter,
 		logsExporter:           logsExporter,
 		metricsExporter:        metricsExporter,
@@ -161,19 +263,94 @@ func newSDK(config *Config) *SDK {
 		defaultSpanExporter:    defaultSpanExporter,
 		defaultLogsExporter:    defaultLogsExporter,
 		defaultMetricsExporter: defaultMetricsExporter,
+		breaker:                breaker,
 	}
 	
-	if config.Debug {
-		fmt.Printf("Lumberjack SDK initialized for project: %s\n", config.ProjectName)
-	}
+	config.debugf("Lumberjack SDK initialized for project: %s\n", config.ProjectName)
 	
 	return sdk
 }
 
+// resourceAttributes returns the default service attributes followed by
+// Config.ResourceAttributes, so user-provided keys override the defaults.
+func resourceAttributes(config *Config) []attribute.KeyValue {
+	attrs := []attribute.KeyValue{
+		semconv.ServiceName(config.ProjectName),
+		semconv.ServiceVersion(config.serviceVersion()),
+	}
+	if config.DetectResources {
+		if hostname, err := os.Hostname(); err == nil {
+			attrs = append(attrs, semconv.HostName(hostname))
+		}
+		attrs = append(attrs,
+			semconv.ProcessPID(os.Getpid()),
+			semconv.ProcessRuntimeVersion(runtime.Version()),
+		)
+	}
+	if config.DetectK8s {
+		attrs = append(attrs, k8sResourceAttributes(config)...)
+	}
+	// Later duplicates win, so user attributes override the ones above
+	for key, value := range config.ResourceAttributes {
+		attrs = append(attrs, attribute.String(key, value))
+	}
+	return attrs
+}
+
 func (s *SDK) Logger() *Logger {
 	return s.logger
 }
 
+// NamedLogger returns the logger for a component, carrying a "component"
+// attribute set to name. Loggers are cached per name, so calling it on a hot
+// path doesn't allocate as With would.
+func (s *SDK) NamedLogger(name string) *Logger {
+	if logger, ok := s.namedLoggers.Load(name); ok {
+		return logger.(*Logger)
+	}
+	logger, _ := s.namedLoggers.LoadOrStore(name, s.logger.With("component", name))
+	return logger.(*Logger)
+}
+
+// SetLogLevel changes the minimum level exported to Lumberjack, overriding
+// Config.MinLogLevel from then on. It takes effect immediately for new log
+// calls.
+func (s *SDK) SetLogLevel(level slog.Level) {
+	s.logLevel.set(level)
+}
+
+// exportLevel is the minimum level exported to Lumberjack: Config.MinLogLevel,
+// read on every call so a *slog.LevelVar stays live, until SetLogLevel
+// overrides it.
+type exportLevel struct {
+	config     *Config
+	overridden atomic.Bool
+	override   slog.LevelVar
+}
+
+func (l *exportLevel) Level() slog.Level {
+	if l.overridden.Load() {
+		return l.override.Level()
+	}
+	return l.config.minLogLevel()
+}
+
+func (l *exportLevel) set(level slog.Level) {
+	l.override.Set(level)
+	l.overridden.Store(true)
+}
+
+// Stats returns a snapshot of each signal's exporter counters. Signals whose
+// exporter doesn't report stats, such as most custom exporters, are zero.
+func (s *SDK) Stats() Stats {
+	return Stats{
+		Logs:    exporterStatsOf(s.logsExporter),
+		Spans:   exporterStatsOf(s.spanExporter),
+		Metrics: exporterStatsOf(s.metricsExporter),
+		Circuit: s.breaker.currentState(),
+	}
+}
+
 func (s *SDK) Tracer() trace.Tracer {
 	return s.tracer
 }
@@ -182,6 +359,31 @@ func (s *SDK) Meter() metric.Meter {
 	return s.meter
 }
 
+// Metrics returns the built-in request and runtime metrics, or nil if they
+// could not be registered.
+func (s *SDK) Metrics() *Metrics {
+	return s.metrics
+}
+
+// RegisterGauge registers an observable gauge whose value is read from cb on
+// every metrics collection. It is a shortcut for the raw OpenTelemetry
+// Float64ObservableGauge API; the gauge lives as long as the SDK.
+func (s *SDK) RegisterGauge(name, unit, desc string, cb func() float64) error {
+	_, err := s.meter.Float64ObservableGauge(
+		name,
+		metric.WithUnit(unit),
+		metric.WithDescription(desc),
+		metric.WithFloat64Callback(func(ctx context.Context, o metric.Float64Observer) error {
+			o.Observe(cb())
+			return nil
+		}),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to register gauge %s: %w", name, err)
+	}
+	return nil
+}
+
 func (s *SDK) StartSpan(ctx context.Context, name string, opts ...trace.SpanStartOption) (context.Context, trace.Span) {
 	return s.tracer.Start(ctx, name, opts...)
 }
@@ -203,35 +405,41 @@ func (s *SDK) ContextWithTraceparent(ctx context.Context, traceparent string) (c
 func parseTraceparent(traceparent string) (trace.SpanContext, error) {
 	parts := strings.Split(traceparent, "-")
 	if len(parts) != 4 {
-		return trace.SpanContext{}, fmt.Errorf("traceparent must have 4 parts separated by '-', got %d", len(parts))
+		return trace.SpanContext{}, fmt.Errorf("%w: traceparent must have 4 parts separated by '-', got %d", ErrTraceparentFormat, len(parts))
 	}
 	
 	// Validate version (must be "00")
 	if parts[0] != "00" {
-		return trace.SpanContext{}, fmt.Errorf("unsupported traceparent version: %s", parts[0])
+		return trace.SpanContext{}, fmt.Errorf("%w: %s", ErrTraceparentVersion, parts[0])
 	}
 	
 	// Parse trace ID (32 hex characters)
 	if len(parts[1]) != 32 {
-		return trace.SpanContext{}, fmt.Errorf("trace ID must be 32 hex characters, got %d", len(parts[1]))
+		return trace.SpanContext{}, fmt.Errorf("%w: trace ID must be 32 hex characters, got %d", ErrTraceparentFormat, len(parts[1]))
 	}
 	traceID, err := trace.TraceIDFromHex(parts[1])
 	if err != nil {
-		return trace.SpanContext{}, fmt.Errorf("invalid trace ID: %w", err)
+		if isAllZeroHex(parts[1]) {
+			return trace.SpanContext{}, fmt.Errorf("%w: invalid trace ID: %v", ErrTraceparentZero, err)
+		}
+		return trace.SpanContext{}, fmt.Errorf("%w: invalid trace ID: %v", ErrTraceparentFormat, err)
 	}
 	
 	// Parse span ID (16 hex characters)
 	if len(parts[2]) != 16 {
-		return trace.SpanContext{}, fmt.Errorf("span ID must be 16 hex characters, got %d", len(parts[2]))
+		return trace.SpanContext{}, fmt.Errorf("%w: span ID must be 16 hex characters, got %d", ErrTraceparentFormat, len(parts[2]))
 	}
 	spanID, err := trace.SpanIDFromHex(parts[2])
 	if err != nil {
-		return trace.SpanContext{}, fmt.Errorf("invalid span ID: %w", err)
+		if isAllZeroHex(parts[2]) {
+			return trace.SpanContext{}, fmt.Errorf("%w: invalid span ID: %v", ErrTraceparentZero, err)
+		}
+		return trace.SpanContext{}, fmt.Errorf("%w: invalid span ID: %v", ErrTraceparentFormat, err)
 	}
 	
 	// Parse trace flags (2 hex characters)
 	if len(parts[3]) != 2 {
-		return trace.SpanContext{}, fmt.Errorf("trace flags must be 2 hex characters, got %d", len(parts[3]))
+		return trace.SpanContext{}, fmt.Errorf("%w: trace flags must be 2 hex characters, got %d", ErrTraceparentFormat, len(parts[3]))
 	}
 	var traceFlags trace.TraceFlags
 	if parts[3] == "01" {
@@ -247,13 +455,49 @@ func parseTraceparent(traceparent string) (trace.SpanContext, error) {
 	})
 	
 	if !spanCtx.IsValid() {
-		return trace.SpanContext{}, fmt.Errorf("created span context is invalid")
+		return trace.SpanContext{}, fmt.Errorf("%w: created span context is invalid", ErrTraceparentFormat)
 	}
 	
 	return spanCtx, nil
 }
 
+// isAllZeroHex reports whether s consists solely of '0' characters.
+func isAllZeroHex(s string) bool {
+	return strings.Trim(s, "0") == ""
+}
+
+// Shutdown flushes pending telemetry and stops the SDK. It returns once every
+// component has shut down, or when ctx expires; a component that ignores its
+// deadline is abandoned rather than blocking the caller. If ctx has no
+// deadline, Config.ShutdownTimeout bounds it instead. Errors from every
+// component are joined.
 func (s *SDK) Shutdown(ctx context.Context) error {
+	if _, ok := ctx.Deadline(); !ok && s.config.ShutdownTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, s.config.ShutdownTimeout)
+		defer cancel()
+	}
+	
+	done := make(chan error, 1)
+	go func() {
+		done <- s.shutdown(ctx)
+	}()
+	
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		return fmt.Errorf("shutdown did not complete: %w", ctx.Err())
+	}
+}
+
+// ShutdownWithTimeout is Shutdown bounded only by Config.ShutdownTimeout, for
+// callers without a context of their own, e.g. in a preStop hook.
+func (s *SDK) ShutdownWithTimeout() error {
+	return s.Shutdown(context.Background())
+}
+
+func (s *SDK) shutdown(ctx context.Context) error {
 	var errs []error
 	
 	// Restore previous slog handler if we replaced it
@@ -261,12 +505,27 @@ func (s *SDK) Shutdown(ctx context.Context) error {
 		restoredLogger := slog.New(s.config.PreviousSlogHandler)
 		slog.SetDefault(restoredLogger)
 		
-		if s.config.Debug {
-			fmt.Println("Lumberjack SDK: Restored previous slog handler")
+		s.config.debugf("Lumberjack SDK: Restored previous slog handler\n")
+	}
+	
+	// Shut the providers down first so telemetry still buffered in their
+	// processors and readers reaches the exporters before those stop
+	if err := s.tracerProvider.Shutdown(ctx); err != nil {
+		errs = append(errs, fmt.Errorf("failed to shutdown tracer provider: %w", err))
+	}
+	
+	if err := s.meterProvider.Shutdown(ctx); err != nil {
+		errs = append(errs, fmt.Errorf("failed to shutdown meter provider: %w", err))
+	}
+	
+	if s.loggerProvider != nil {
+		if err := s.loggerProvider.Shutdown(ctx); err != nil {
+			errs = append(errs, fmt.Errorf("failed to shutdown logger provider: %w", err))
 		}
 	}
 	
-	// Only shutdown default exporters if they were created
+	// Only shutdown default exporters if they were created. The providers
+	// normally stopped them already, in which case this is a no-op.
 	if s.defaultLogsExporter != nil {
 		if err := s.defaultLogsExporter.Shutdown(ctx); err != nil {
 			errs = append(errs, fmt.Errorf("failed to shutdown logs exporter: %w", err))
@@ -285,89 +544,170 @@ func (s *SDK) Shutdown(ctx context.Context) error {
 		}
 	}
 	
-	if err := s.tracerProvider.Shutdown(ctx); err != nil {
-		errs = append(errs, fmt.Errorf("failed to shutdown tracer provider: %w", err))
-	}
-	
-	if err := s.meterProvider.Shutdown(ctx); err != nil {
-		errs = append(errs, fmt.Errorf("failed to shutdown meter provider: %w", err))
-	}
-	
-	if s.loggerProvider != nil {
-		if err := s.loggerProvider.Shutdown(ctx); err != nil {
-			errs = append(errs, fmt.Errorf("failed to shutdown logger provider: %w", err))
-		}
+	return errors.Join(errs...)
+}
+
+// The package-level functions below use the global SDK and are safe to call
+// before Init: logging does nothing, and accessors return a no-op logger,
+// tracer or meter, so libraries can use them without requiring their
+// consumers to call Init.
+
+func GetLogger() *Logger {
+	if sdk, ok := TryGet(); ok {
+		return sdk.Logger()
 	}
-	
-	if len(errs) > 0 {
-		return fmt.Errorf("shutdown errors: %v", errs)
+	return noopLogger
+}
+
+func Trace(msg string, args ...any) {
+	if sdk, ok := TryGet(); ok {
+		sdk.Logger().logAt(context.Background(), 3, LevelTrace, msg, args...)
 	}
-	
-	return nil
 }
 
-func GetLogger() *Logger {
-	return Get().Logger()
+func TraceContext(ctx context.Context, msg string, args ...any) {
+	if sdk, ok := TryGet(); ok {
+		sdk.Logger().logAt(ctx, 3, LevelTrace, msg, args...)
+	}
 }
 
 func Debug(msg string, args ...any) {
-	Get().Logger().Debug(msg, args...)
+	if sdk, ok := TryGet(); ok {
+		sdk.Logger().logAt(context.Background(), 3, slog.LevelDebug, msg, args...)
+	}
 }
 
 func DebugContext(ctx context.Context, msg string, args ...any) {
-	Get().Logger().DebugContext(ctx, msg, args...)
+	if sdk, ok := TryGet(); ok {
+		sdk.Logger().logAt(ctx, 3, slog.LevelDebug, msg, args...)
+	}
 }
 
 func Info(msg string, args ...any) {
-	Get().Logger().Info(msg, args...)
+	if sdk, ok := TryGet(); ok {
+		sdk.Logger().logAt(context.Background(), 3, slog.LevelInfo, msg, args...)
+	}
 }
 
 func InfoContext(ctx context.Context, msg string, args ...any) {
-	Get().Logger().InfoContext(ctx, msg, args...)
+	if sdk, ok := TryGet(); ok {
+		sdk.Logger().logAt(ctx, 3, slog.LevelInfo, msg, args...)
+	}
 }
 
 func Warn(msg string, args ...any) {
-	Get().Logger().Warn(msg, args...)
+	if sdk, ok := TryGet(); ok {
+		sdk.Logger().logAt(context.Background(), 3, slog.LevelWarn, msg, args...)
+	}
 }
 
 func WarnContext(ctx context.Context, msg string, args ...any) {
-	Get().Logger().WarnContext(ctx, msg, args...)
+	if sdk, ok := TryGet(); ok {
+		sdk.Logger().logAt(ctx, 3, slog.LevelWarn, msg, args...)
+	}
 }
 
 func Error(msg string, args ...any) {
-	Get().Logger().Error(msg, args...)
+	if sdk, ok := TryGet(); ok {
+		sdk.Logger().logAt(context.Background(), 3, slog.LevelError, msg, args...)
+	}
 }
 
 func ErrorContext(ctx context.Context, msg string, args ...any) {
-	Get().Logger().ErrorContext(ctx, msg, args...)
+	if sdk, ok := TryGet(); ok {
+		sdk.Logger().logAt(ctx, 3, slog.LevelError, msg, args...)
+	}
+}
+
+func Fatal(msg string, args ...any) {
+	if sdk, ok := TryGet(); ok {
+		sdk.Logger().logAt(context.Background(), 3, LevelFatal, msg, args...)
+	}
+}
+
+func FatalContext(ctx context.Context, msg string, args ...any) {
+	if sdk, ok := TryGet(); ok {
+		sdk.Logger().logAt(ctx, 3, LevelFatal, msg, args...)
+	}
+}
+
+func LogErr(ctx context.Context, msg string, err error) error {
+	if err == nil {
+		return nil
+	}
+	if sdk, ok := TryGet(); ok {
+		sdk.Logger().logAt(ctx, 3, slog.LevelError, msg, "error", err)
+	}
+	return err
+}
+
+func SetLogLevel(level slog.Level) {
+	if sdk, ok := TryGet(); ok {
+		sdk.SetLogLevel(level)
+	}
+}
+
+func GetStats() Stats {
+	if sdk, ok := TryGet(); ok {
+		return sdk.Stats()
+	}
+	return Stats{}
 }
 
 func With(args ...any) *Logger {
-	return Get().Logger().With(args...)
+	return GetLogger().With(args...)
 }
 
 func WithGroup(name string) *Logger {
-	return Get().Logger().WithGroup(name)
+	return GetLogger().WithGroup(name)
+}
+
+// NamedLogger returns the global SDK's cached logger for a component.
+func NamedLogger(name string) *Logger {
+	if sdk, ok := TryGet(); ok {
+		return sdk.NamedLogger(name)
+	}
+	return noopLogger
 }
 
 func Log(ctx context.Context, level slog.Level, msg string, args ...any) {
-	Get().Logger().Log(ctx, level, msg, args...)
+	if sdk, ok := TryGet(); ok {
+		sdk.Logger().logAt(ctx, 3, level, msg, args...)
+	}
 }
 
 func LogAttrs(ctx context.Context, level slog.Level, msg string, attrs ...slog.Attr) {
-	Get().Logger().LogAttrs(ctx, level, msg, attrs...)
+	if sdk, ok := TryGet(); ok {
+		sdk.Logger().logAttrsAt(ctx, 3, level, msg, attrs...)
+	}
 }
 
 func StartSpan(ctx context.Context, name string, opts ...trace.SpanStartOption) (context.Context, trace.Span) {
-	return Get().StartSpan(ctx, name, opts...)
+	if sdk, ok := TryGet(); ok {
+		return sdk.StartSpan(ctx, name, opts...)
+	}
+	return noopTracer.Start(ctx, name, opts...)
+}
+
+func RegisterGauge(name, unit, desc string, cb func() float64) error {
+	if sdk, ok := TryGet(); ok {
+		return sdk.RegisterGauge(name, unit, desc, cb)
+	}
+	return ErrNotInitialized
 }
 
 func Tracer() trace.Tracer {
-	return Get().Tracer()
+	if sdk, ok := TryGet(); ok {
+		return sdk.Tracer()
+	}
+	return noopTracer
 }
 
 func Meter() metric.Meter {
-	return Get().Meter()
+	if sdk, ok := TryGet(); ok {
+		return sdk.Meter()
+	}
+	return noopMeter
 }
 
 func Shutdown(ctx context.Context) error {
@@ -377,9 +717,35 @@ func Shutdown(ctx context.Context) error {
 	return nil
 }
 
+// ShutdownWithTimeout shuts down the global SDK, bounded by its
+// Config.ShutdownTimeout.
+func ShutdownWithTimeout() error {
+	if globalSDK != nil {
+		return globalSDK.ShutdownWithTimeout()
+	}
+	return nil
+}
+
 func baselineHandler() slog.Handler {
 	// Anything that writes straight to a file (no slog.Default()) is OK.
-	return slog.NewTextHandler(os.Stderr, nil)
+	return slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{
+		ReplaceAttr: replaceLevelName,
+	})
+}
+
+// replaceLevelName prints LevelTrace and LevelFatal as TRACE and FATAL
+// instead of slog's "DEBUG-4" and "ERROR+4".
+func replaceLevelName(groups []string, a slog.Attr) slog.Attr {
+	if a.Key != slog.LevelKey || len(groups) > 0 {
+		return a
+	}
+	switch level, _ := a.Value.Any().(slog.Level); level {
+	case LevelTrace:
+		a.Value = slog.StringValue("TRACE")
+	case LevelFatal:
+		a.Value = slog.StringValue("FATAL")
+	}
+	return a
 }
 
 // ContextWithTraceparent creates a context with trace context from W3C traceparent header.
@@ -0,0 +1,200 @@
+package lumberjack
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"reflect"
+	"runtime"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestResourceAttributes(t *testing.T) {
+	server := newCaptureServer(t)
+	config := testConfig(server.URL).WithResourceAttributes(map[string]string{
+		"deployment.environment": "staging",
+		"service.name":           "checkout",
+	})
+	sdk := newSDK(config)
+
+	_, span := sdk.StartSpan(context.Background(), "work")
+	span.End()
+	// Drain the batch span processor before the exporter shuts down
+	sdk.tracerProvider.ForceFlush(context.Background())
+	sdk.Shutdown(context.Background())
+
+	spans := exportedSpans(t, server)
+	if len(spans) != 1 {
+		t.Fatalf("expected 1 exported span, got %d", len(spans))
+	}
+	if got := spans[0].Attributes["deployment.environment"]; got != "staging" {
+		t.Errorf("deployment.environment = %q, want %q", got, "staging")
+	}
+	if got := spans[0].Attributes["service.name"]; got != "checkout" {
+		t.Errorf("service.name = %q, want the user override %q", got, "checkout")
+	}
+	if spans[0].Service != "checkout" {
+		t.Errorf("Service = %q, want %q", spans[0].Service, "checkout")
+	}
+}
+
+func TestServiceVersion(t *testing.T) {
+	t.Setenv("LUMBERJACK_SERVICE_VERSION", "0.0.1-env")
+	t.Setenv("LUMBERJACK_RELEASE_ID", "")
+
+	server := newCaptureServer(t)
+	sdk := newSDK(testConfig(server.URL).WithServiceVersion("1.4.2"))
+
+	_, span := sdk.StartSpan(context.Background(), "work")
+	span.End()
+	sdk.tracerProvider.ForceFlush(context.Background())
+	sdk.Shutdown(context.Background())
+
+	spans := exportedSpans(t, server)
+	if len(spans) != 1 {
+		t.Fatalf("expected 1 exported span, got %d", len(spans))
+	}
+	if got := spans[0].Attributes["service.version"]; got != "1.4.2" {
+		t.Errorf("service.version = %q, want the configured %q", got, "1.4.2")
+	}
+
+	var req SpanBatchRequest
+	json.Unmarshal(server.requestsFor("/spans/batch")[0].Body, &req)
+	if req.Payload.ReleaseId != "1.4.2" {
+		t.Errorf("ReleaseId = %q, want %q", req.Payload.ReleaseId, "1.4.2")
+	}
+}
+
+func TestServiceVersionFallsBackToEnv(t *testing.T) {
+	t.Setenv("LUMBERJACK_SERVICE_VERSION", "0.0.1-env")
+
+	if got := testConfig("").serviceVersion(); got != "0.0.1-env" {
+		t.Errorf("serviceVersion() = %q, want the env value %q", got, "0.0.1-env")
+	}
+}
+
+func TestDetectResources(t *testing.T) {
+	hostname, err := os.Hostname()
+	if err != nil {
+		t.Skipf("os.Hostname() unavailable: %v", err)
+	}
+
+	tests := []struct {
+		name   string
+		config func(url string) *Config
+		want   map[string]string
+	}{
+		{
+			name:   "detected",
+			config: testConfig,
+			want: map[string]string{
+				"host.name":               hostname,
+				"process.pid":             strconv.Itoa(os.Getpid()),
+				"process.runtime.version": runtime.Version(),
+			},
+		},
+		{
+			name: "overridden",
+			config: func(url string) *Config {
+				return testConfig(url).WithResourceAttributes(map[string]string{"host.name": "web-1"})
+			},
+			want: map[string]string{"host.name": "web-1"},
+		},
+		{
+			name: "disabled",
+			config: func(url string) *Config {
+				return testConfig(url).WithDetectResources(false)
+			},
+			want: map[string]string{"host.name": "", "process.pid": ""},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := newCaptureServer(t)
+			sdk := newSDK(tt.config(server.URL))
+
+			_, span := sdk.StartSpan(context.Background(), "work")
+			span.End()
+			sdk.tracerProvider.ForceFlush(context.Background())
+			sdk.Shutdown(context.Background())
+
+			spans := exportedSpans(t, server)
+			if len(spans) != 1 {
+				t.Fatalf("expected 1 exported span, got %d", len(spans))
+			}
+			for key, want := range tt.want {
+				if got := spans[0].Attributes[key]; got != want {
+					t.Errorf("%s = %q, want %q", key, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestDetectK8s(t *testing.T) {
+	t.Setenv("POD_NAME", "checkout-7d9f8-abcde")
+	t.Setenv("POD_NAMESPACE", "shop")
+	t.Setenv("NODE_NAME", "")
+	t.Setenv("MY_NODE", "node-3")
+
+	tests := []struct {
+		name   string
+		config *Config
+		want   map[string]string
+	}{
+		{
+			name:   "default env keys",
+			config: NewConfig().WithDetectK8s(true),
+			want:   map[string]string{"k8s.pod.name": "checkout-7d9f8-abcde", "k8s.namespace.name": "shop"},
+		},
+		{
+			name:   "custom env keys",
+			config: NewConfig().WithDetectK8s(true).WithK8sEnvKeys("", "", "MY_NODE"),
+			want:   map[string]string{"k8s.pod.name": "checkout-7d9f8-abcde", "k8s.namespace.name": "shop", "k8s.node.name": "node-3"},
+		},
+		{
+			name:   "disabled",
+			config: NewConfig(),
+			want:   map[string]string{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := map[string]string{}
+			for _, attr := range resourceAttributes(tt.config) {
+				if strings.HasPrefix(string(attr.Key), "k8s.") {
+					got[string(attr.Key)] = attr.Value.AsString()
+				}
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("k8s resource attributes = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDetectK8sOnSpans(t *testing.T) {
+	t.Setenv("POD_NAME", "checkout-7d9f8-abcde")
+	t.Setenv("POD_NAMESPACE", "shop")
+	t.Setenv("NODE_NAME", "node-3")
+
+	server := newCaptureServer(t)
+	sdk := newSDK(testConfig(server.URL).WithDetectK8s(true))
+	_, span := sdk.StartSpan(context.Background(), "work")
+	span.End()
+	sdk.tracerProvider.ForceFlush(context.Background())
+	sdk.Shutdown(context.Background())
+
+	spans := exportedSpans(t, server)
+	if len(spans) != 1 {
+		t.Fatalf("expected 1 exported span, got %d", len(spans))
+	}
+	want := map[string]string{"k8s.pod.name": "checkout-7d9f8-abcde", "k8s.namespace.name": "shop", "k8s.node.name": "node-3"}
+	for key, value := range want {
+		if got := spans[0].Attributes[key]; got != value {
+			t.Errorf("%s = %q, want %q", key, got, value)
+		}
+	}
+}
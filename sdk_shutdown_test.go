@@ -0,0 +1,97 @@
+package lumberjack
+
+import (
+	"context"
+	"errors"
+	"io"
+	"testing"
+	"time"
+
+	"go.opentelemetry.io/otel/exporters/stdout/stdoutmetric"
+	sdklog "go.opentelemetry.io/otel/sdk/log"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+// hungSpanExporter is a span exporter whose Shutdown ignores its context and
+// blocks until release is closed.
+type hungSpanExporter struct {
+	release chan struct{}
+}
+
+func (e *hungSpanExporter) ExportSpans(context.Context, []sdktrace.ReadOnlySpan) error { return nil }
+
+func (e *hungSpanExporter) Shutdown(context.Context) error {
+	<-e.release
+	return nil
+}
+
+type failingMetricsExporter struct {
+	sdkmetric.Exporter
+	err error
+}
+
+func (e *failingMetricsExporter) Shutdown(context.Context) error { return e.err }
+
+type failingLogsExporter struct{ err error }
+
+func (e *failingLogsExporter) Export(context.Context, []*sdklog.Record) error { return nil }
+
+func (e *failingLogsExporter) Shutdown(context.Context) error { return e.err }
+
+func TestShutdownTimeout(t *testing.T) {
+	server := newCaptureServer(t)
+	hung := &hungSpanExporter{release: make(chan struct{})}
+	defer close(hung.release)
+
+	config := testConfig(server.URL).
+		WithCustomSpanExporter(hung).
+		WithShutdownTimeout(100 * time.Millisecond)
+	sdk := newSDK(config)
+
+	start := time.Now()
+	err := sdk.ShutdownWithTimeout()
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("ShutdownWithTimeout() returned after %v, want it bounded by the 100ms ShutdownTimeout", elapsed)
+	}
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("ShutdownWithTimeout() error = %v, want context.DeadlineExceeded", err)
+	}
+}
+
+func TestShutdownHonorsLongerDeadline(t *testing.T) {
+	server := newCaptureServer(t)
+	hung := &hungSpanExporter{release: make(chan struct{})}
+	time.AfterFunc(200*time.Millisecond, func() { close(hung.release) })
+
+	config := testConfig(server.URL).
+		WithCustomSpanExporter(hung).
+		WithShutdownTimeout(50 * time.Millisecond)
+	sdk := newSDK(config)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := sdk.Shutdown(ctx); err != nil {
+		t.Errorf("Shutdown() error = %v, want the caller's 5s deadline to outrank the 50ms ShutdownTimeout", err)
+	}
+}
+
+func TestShutdownJoinsErrors(t *testing.T) {
+	server := newCaptureServer(t)
+	metricsErr := errors.New("metrics exporter failed")
+	logsErr := errors.New("logs exporter failed")
+
+	stdout, err := stdoutmetric.New(stdoutmetric.WithWriter(io.Discard))
+	if err != nil {
+		t.Fatal(err)
+	}
+	config := testConfig(server.URL).
+		WithCustomMetricsExporter(&failingMetricsExporter{Exporter: stdout, err: metricsErr}).
+		WithCustomLogsExporter(&failingLogsExporter{err: logsErr})
+	sdk := newSDK(config)
+
+	err = sdk.Shutdown(context.Background())
+	if !errors.Is(err, metricsErr) || !errors.Is(err, logsErr) {
+		t.Errorf("Shutdown() error = %v, want it to wrap both exporter errors", err)
+	}
+}
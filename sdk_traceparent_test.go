@@ -2,6 +2,7 @@ package lumberjack
 
 import (
 	"context"
+	"errors"
 	"testing"
 
 	"go.opentelemetry.io/otel/trace"
@@ -107,6 +108,37 @@ func TestParseTraceparent(t *testing.T) {
 	}
 }
 
+func TestParseTraceparentSentinelErrors(t *testing.T) {
+	tests := []struct {
+		name        string
+		traceparent string
+		want        error
+	}{
+		{"missing parts", "00-4bf92f3577b34da6a3ce929d0e0e4736", ErrTraceparentFormat},
+		{"short trace ID", "00-4bf92f3577b34da6a3ce929d0e0e473-00f067aa0ba902b7-01", ErrTraceparentFormat},
+		{"invalid hex in span ID", "00-4bf92f3577b34da6a3ce929d0e0e4736-XYZ067aa0ba902b7-01", ErrTraceparentFormat},
+		{"short flags", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-1", ErrTraceparentFormat},
+		{"unsupported version", "01-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01", ErrTraceparentVersion},
+		{"all zero trace ID", "00-00000000000000000000000000000000-00f067aa0ba902b7-01", ErrTraceparentZero},
+		{"all zero span ID", "00-4bf92f3577b34da6a3ce929d0e0e4736-0000000000000000-01", ErrTraceparentZero},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := parseTraceparent(tt.traceparent)
+			if !errors.Is(err, tt.want) {
+				t.Errorf("parseTraceparent() error = %v, want errors.Is(%v)", err, tt.want)
+			}
+
+			// The sentinel must survive the wrapping done by ContextWithTraceparent
+			_, err = (&SDK{}).ContextWithTraceparent(context.Background(), tt.traceparent)
+			if !errors.Is(err, tt.want) {
+				t.Errorf("ContextWithTraceparent() error = %v, want errors.Is(%v)", err, tt.want)
+			}
+		})
+	}
+}
+
 func TestContextWithTraceparent(t *testing.T) {
 	// Initialize SDK for testing with proper config
 	config := NewConfig()
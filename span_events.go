@@ -0,0 +1,62 @@
+package lumberjack
+
+import (
+	"context"
+	"fmt"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/log"
+	sdklog "go.opentelemetry.io/otel/sdk/log"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// spanEventLogProcessor adds logs emitted within a recording span to that
+// span as events before handing them to the next processor.
+type spanEventLogProcessor struct {
+	sdklog.Processor
+	minSeverity log.Severity
+	only        bool
+}
+
+func newSpanEventLogProcessor(next sdklog.Processor, config *Config) *spanEventLogProcessor {
+	return &spanEventLogProcessor{
+		Processor: next,
+		// The slog bridge maps slog levels to severities offset by 9.
+		minSeverity: log.Severity(config.SpanEventMinLevel + 9),
+		only:        config.SpanEventsOnly,
+	}
+}
+
+func (p *spanEventLogProcessor) OnEmit(ctx context.Context, record *sdklog.Record) error {
+	span := trace.SpanFromContext(ctx)
+	if !span.IsRecording() || record.Severity() < p.minSeverity {
+		return p.Processor.OnEmit(ctx, record)
+	}
+
+	span.AddEvent(record.Body().String(),
+		trace.WithTimestamp(record.Timestamp()),
+		trace.WithAttributes(logEventAttributes(record)...),
+	)
+	if p.only {
+		return nil
+	}
+	return p.Processor.OnEmit(ctx, record)
+}
+
+// logEventAttributes converts a log record's severity and attributes to span
+// event attributes. Values are stringified, since span event attributes are
+// exported as strings.
+func logEventAttributes(record *sdklog.Record) []attribute.KeyValue {
+	props := make(map[string]interface{}, record.AttributesLen())
+	record.WalkAttributes(func(kv log.KeyValue) bool {
+		addLogProp(props, string(kv.Key), kv.Value)
+		return true
+	})
+
+	attrs := make([]attribute.KeyValue, 0, len(props)+1)
+	attrs = append(attrs, attribute.String("log.severity", severityToString(record.Severity())))
+	for key, value := range props {
+		attrs = append(attrs, attribute.String(key, fmt.Sprint(value)))
+	}
+	return attrs
+}
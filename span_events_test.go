@@ -0,0 +1,65 @@
+package lumberjack
+
+import (
+	"context"
+	"log/slog"
+	"testing"
+)
+
+func TestLogsAsSpanEvents(t *testing.T) {
+	tests := []struct {
+		name         string
+		eventsOnly   bool
+		wantExported []string
+	}{
+		{name: "also exported", wantExported: []string{"noise", "charged", "after"}},
+		{name: "events only", eventsOnly: true, wantExported: []string{"noise", "after"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := newCaptureServer(t)
+			config := testConfig(server.URL).
+				WithLogsAsSpanEvents(slog.LevelInfo).
+				WithSpanEventsOnly(tt.eventsOnly)
+			sdk := newSDK(config)
+
+			ctx, span := sdk.StartSpan(context.Background(), "checkout")
+			sdk.Logger().DebugContext(ctx, "noise")
+			sdk.Logger().InfoContext(ctx, "charged", "amount", 42)
+			span.End()
+			sdk.Logger().Info("after")
+
+			sdk.tracerProvider.ForceFlush(context.Background())
+			sdk.Shutdown(context.Background())
+
+			spans := exportedSpans(t, server)
+			if len(spans) != 1 {
+				t.Fatalf("expected 1 exported span, got %d", len(spans))
+			}
+			events := spans[0].Events
+			if len(events) != 1 || events[0].Name != "charged" {
+				t.Fatalf("span events = %+v, want a single %q event", events, "charged")
+			}
+			if events[0].Attributes["amount"] != "42" || events[0].Attributes["log.severity"] != "INFO" {
+				t.Errorf("event attributes = %v, want amount=42 and log.severity=INFO", events[0].Attributes)
+			}
+
+			var exported []string
+			for _, req := range server.decodeLogRequests(t) {
+				for _, entry := range req.Logs {
+					exported = append(exported, entry.Msg)
+				}
+			}
+			if len(exported) != len(tt.wantExported) {
+				t.Fatalf("exported logs = %q, want %q", exported, tt.wantExported)
+			}
+			for i := range exported {
+				if exported[i] != tt.wantExported[i] {
+					t.Errorf("exported logs = %q, want %q", exported, tt.wantExported)
+					break
+				}
+			}
+		})
+	}
+}
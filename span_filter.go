@@ -0,0 +1,17 @@
+package lumberjack
+
+import (
+	"time"
+
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+// KeepErrorsAndSlowerThan returns a Config.SpanFilter that keeps only spans
+// with an error status or that took longer than d, a simple form of tail
+// sampling for services where fast, successful spans aren't worth exporting.
+func KeepErrorsAndSlowerThan(d time.Duration) func(sdktrace.ReadOnlySpan) bool {
+	return func(span sdktrace.ReadOnlySpan) bool {
+		return span.Status().Code == codes.Error || span.EndTime().Sub(span.StartTime()) > d
+	}
+}
@@ -0,0 +1,44 @@
+package lumberjack
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestKeepErrorsAndSlowerThan(t *testing.T) {
+	server := newCaptureServer(t)
+	sdk := newSDK(testConfig(server.URL).WithSpanFilter(KeepErrorsAndSlowerThan(time.Second)))
+
+	start := time.Now()
+	for _, span := range []struct {
+		name     string
+		duration time.Duration
+		err      error
+	}{
+		{"fast ok", 10 * time.Millisecond, nil},
+		{"slow ok", 2 * time.Second, nil},
+		{"fast error", 10 * time.Millisecond, errors.New("card declined")},
+	} {
+		_, s := sdk.StartSpan(context.Background(), span.name, trace.WithTimestamp(start))
+		if span.err != nil {
+			recordSpanError(s, span.err)
+		}
+		s.End(trace.WithTimestamp(start.Add(span.duration)))
+	}
+	sdk.Shutdown(context.Background())
+
+	exported := map[string]bool{}
+	for _, span := range exportedSpans(t, server) {
+		exported[span.Name] = true
+	}
+	if exported["fast ok"] {
+		t.Error("fast, successful span was exported")
+	}
+	if !exported["slow ok"] || !exported["fast error"] {
+		t.Errorf("exported spans = %v, want the slow and the errored span", exported)
+	}
+}
@@ -3,11 +3,9 @@ package lumberjack
 import (
 	"bytes"
 	"context"
-	"encoding/json"
 	"fmt"
-	"math/rand"
+	"io"
 	"net/http"
-	"os"
 	"sync"
 	"time"
 	
@@ -20,11 +18,26 @@ import (
 type SpanExporter struct {
 	config      *Config
 	client      *http.Client
+	apiKey      *apiKeySource
+	breaker     *circuitBreaker
 	batch       []InternalSpan
+	spare       []InternalSpan
+	batchBytes  int
 	batchMu     sync.Mutex
 	stopCh      chan struct{}
 	wg          sync.WaitGroup
 	flushTicker *time.Ticker
+	ageTimer    *time.Timer
+	lastFlush   time.Time
+	stats       exporterStats
+	spool       *spool
+	
+	releaseID   string
+	releaseType string
+	
+	safeQueryParams map[string]struct{}
+	redactor        *redactor
+	normalizeKey    func(string) string
 }
 
 type InternalSpan struct {
@@ -32,14 +45,18 @@ type InternalSpan struct {
 	SpanID      string                 `json:"SpanID"`
 	ParentSpanID string                `json:"ParentSpanID,omitempty"`
 	Service     string                 `json:"Service"`
+	ScopeName    string                `json:"ScopeName,omitempty"`
+	ScopeVersion string                `json:"ScopeVersion,omitempty"`
 	Name        string                 `json:"Name"`
 	Kind        int                    `json:"Kind"`
 	StatusCode  int                    `json:"StatusCode"`
+	StatusMessage string               `json:"StatusMessage,omitempty"`
 	StartTime   string                 `json:"StartTime"`
 	EndTime     string                 `json:"EndTime"`
 	DurationUS  int64                  `json:"DurationUS"`
 	Attributes  map[string]string      `json:"Attributes"`
 	Events      []SpanEvent            `json:"Events,omitempty"`
+	Links       []SpanLink             `json:"Links,omitempty"`
 }
 
 type SpanEvent struct {
@@ -48,11 +65,21 @@ type SpanEvent struct {
 	Attributes   map[string]string `json:"attributes,omitempty"`
 }
 
+// SpanLink references a related span, e.g. one of several producers that fed
+// a consumer span.
+type SpanLink struct {
+	TraceID    string            `json:"traceId"`
+	SpanID     string            `json:"spanId"`
+	Attributes map[string]string `json:"attributes,omitempty"`
+}
+
 type SpanBatchRequest struct {
-	Type    string                 `json:"type"`
-	Env     string                 `json:"env"`
-	Ts      int64                  `json:"ts"`
-	Payload SpanBatchPayload       `json:"payload"`
+	Type       string           `json:"type"`
+	Env        string           `json:"env"`
+	Ts         int64            `json:"ts"`
+	SdkVersion int              `json:"sdk_version"`
+	SdkSemver  string           `json:"sdk_semver,omitempty"`
+	Payload    SpanBatchPayload `json:"payload"`
 }
 
 type SpanBatchPayload struct {
@@ -63,33 +90,81 @@ type SpanBatchPayload struct {
 }
 
 func NewSpanExporter(config *Config) *SpanExporter {
+	return newSpanExporter(config, newCircuitBreaker(config))
+}
+
+// newSpanExporter creates the exporter with a circuit breaker that may be shared
+// with the SDK's other exporters.
+func newSpanExporter(config *Config, breaker *circuitBreaker) *SpanExporter {
 	exporter := &SpanExporter{
-		config: config,
-		client: &http.Client{
-			Timeout: 30 * time.Second,
-		},
-		batch:  make([]InternalSpan, 0, config.BatchSize),
-		stopCh: make(chan struct{}),
+		config:  config,
+		breaker: breaker,
+		client:  newHTTPClient(config),
+		apiKey:  newAPIKeySource(config),
+		batch:   make([]InternalSpan, 0, config.BatchSize),
+		stopCh:  make(chan struct{}),
+
+		lastFlush: time.Now(),
+		
+		releaseID:   config.releaseID(),
+		releaseType: config.releaseType(),
+		
+		safeQueryParams: toLowerSet(config.SafeQueryParams),
+		redactor:        newRedactor(config),
+		normalizeKey:    keyNormalizer(config),
 	}
 	
 	exporter.flushTicker = time.NewTicker(config.BatchTimeout)
 	exporter.wg.Add(1)
 	go exporter.runFlusher()
 	
+	if exporter.spool = newExporterSpool(config, "spans"); exporter.spool != nil {
+		exporter.wg.Add(1)
+		go func() {
+			defer exporter.wg.Done()
+			exporter.spool.run(config.SpoolReplayInterval, exporter.stopCh, func(data []byte) error {
+				return exporter.sendWithRetry(context.Background(), data)
+			})
+		}()
+	}
+	
 	return exporter
 }
 
 func (e *SpanExporter) ExportSpans(ctx context.Context, spans []sdktrace.ReadOnlySpan) error {
 	for _, span := range spans {
+		if e.config.SpanFilter != nil && !e.config.SpanFilter(span) {
+			continue
+		}
 		internalSpan := e.convertSpan(span)
 		
 		e.batchMu.Lock()
-		e.batch = append(e.batch, internalSpan)
-		shouldFlush := len(e.batch) >= e.config.BatchSize
+		for queueFull(e.config, len(e.batch), 1) {
+			e.batchMu.Unlock()
+			e.flush(exportContext(ctx, e.config))
+			e.batchMu.Lock()
+		}
+		evicted, accepted := applyOverflowPolicy(e.config, e.batch, []InternalSpan{internalSpan})
+		if dropped := evicted + 1 - len(accepted); dropped > 0 {
+			e.stats.dropped.Add(uint64(dropped))
+			for _, span := range e.batch[:evicted] {
+				e.batchBytes -= span.estimatedSize()
+			}
+			e.batch = append(e.batch[:0], e.batch[evicted:]...)
+		}
+		if len(accepted) > 0 {
+			e.armAgeTimerLocked()
+			e.batch = append(e.batch, internalSpan)
+			e.stats.enqueued.Add(1)
+			e.batchBytes += internalSpan.estimatedSize()
+		}
+		// With a simple span processor each span is sent as soon as it ends
+		shouldFlush := e.config.SpanProcessor == SimpleSpanProcessor ||
+			batchFull(e.config, len(e.batch), e.batchBytes) || batchStale(e.config, e.lastFlush)
 		e.batchMu.Unlock()
 		
 		if shouldFlush {
-			e.flush()
+			e.flush(exportContext(ctx, e.config))
 		}
 	}
 	
@@ -108,13 +183,20 @@ func (e *SpanExporter) convertSpan(span sdktrace.ReadOnlySpan) InternalSpan {
 		if attr.Key == semconv.ServiceNameKey {
 			serviceName = attr.Value.AsString()
 		}
-		attributes[string(attr.Key)] = attr.Value.AsString()
+		// Emit, unlike AsString, also renders non-string values like process.pid
+		attributes[string(attr.Key)] = attr.Value.Emit()
 	}
 	
 	for _, attr := range span.Attributes() {
-		attributes[string(attr.Key)] = attr.Value.AsString()
+		attributes[string(attr.Key)] = attr.Value.Emit()
 	}
 	
+	if !e.config.KeepURLQueries {
+		scrubURLAttributes(attributes, e.safeQueryParams)
+	}
+	e.redactor.redactStrings(attributes)
+	attributes = normalizeKeys(attributes, e.normalizeKey)
+	
 	statusCode := 0
 	if span.Status().Code == codes.Error {
 		statusCode = 2
@@ -131,8 +213,10 @@ func (e *SpanExporter) convertSpan(span sdktrace.ReadOnlySpan) InternalSpan {
 	for _, event := range span.Events() {
 		eventAttrs := make(map[string]string)
 		for _, attr := range event.Attributes {
-			eventAttrs[string(attr.Key)] = attr.Value.AsString()
+			eventAttrs[string(attr.Key)] = attr.Value.Emit()
 		}
+		e.redactor.redactStrings(eventAttrs)
+		eventAttrs = normalizeKeys(eventAttrs, e.normalizeKey)
 		
 		events = append(events, SpanEvent{
 			TimeUnixNano: event.Time.UnixNano(),
@@ -141,113 +225,245 @@ func (e *SpanExporter) convertSpan(span sdktrace.ReadOnlySpan) InternalSpan {
 		})
 	}
 	
+	var links []SpanLink
+	for _, link := range span.Links() {
+		linkAttrs := make(map[string]string)
+		for _, attr := range link.Attributes {
+			linkAttrs[string(attr.Key)] = attr.Value.Emit()
+		}
+		e.redactor.redactStrings(linkAttrs)
+		linkAttrs = normalizeKeys(linkAttrs, e.normalizeKey)
+		
+		links = append(links, SpanLink{
+			TraceID:    link.SpanContext.TraceID().String(),
+			SpanID:     link.SpanContext.SpanID().String(),
+			Attributes: linkAttrs,
+		})
+	}
+	
 	return InternalSpan{
-		TraceID:      span.SpanContext().TraceID().String(),
-		SpanID:       span.SpanContext().SpanID().String(),
-		ParentSpanID: parentSpanID,
-		Service:      serviceName,
-		Name:         span.Name(),
-		Kind:         int(span.SpanKind()),
-		StatusCode:   statusCode,
-		StartTime:    startTime,
-		EndTime:      endTime,
-		DurationUS:   durationUS,
-		Attributes:   attributes,
-		Events:       events,
+		TraceID:       span.SpanContext().TraceID().String(),
+		SpanID:        span.SpanContext().SpanID().String(),
+		ParentSpanID:  parentSpanID,
+		Service:       serviceName,
+		ScopeName:     span.InstrumentationScope().Name,
+		ScopeVersion:  span.InstrumentationScope().Version,
+		Name:          span.Name(),
+		Kind:          int(span.SpanKind()),
+		StatusCode:    statusCode,
+		StatusMessage: span.Status().Description,
+		StartTime:     startTime,
+		EndTime:       endTime,
+		DurationUS:    durationUS,
+		Attributes:    attributes,
+		Events:        events,
+		Links:         links,
 	}
 }
 
+// estimatedSize cheaply approximates the encoded size of the span.
+func (s InternalSpan) estimatedSize() int {
+	size := entryOverheadBytes*2 + len(s.Name) + len(s.Service) + len(s.ScopeName) + len(s.ScopeVersion) + len(s.StatusMessage) + estimateStringMapSize(s.Attributes)
+	for _, event := range s.Events {
+		size += entryOverheadBytes + len(event.Name) + estimateStringMapSize(event.Attributes)
+	}
+	for _, link := range s.Links {
+		size += entryOverheadBytes + estimateStringMapSize(link.Attributes)
+	}
+	return size
+}
+
 func (e *SpanExporter) runFlusher() {
 	defer e.wg.Done()
 	
 	for {
 		select {
 		case <-e.flushTicker.C:
-			e.flush()
+			e.flush(context.Background())
 		case <-e.stopCh:
 			return
 		}
 	}
 }
 
-func (e *SpanExporter) flush() {
+func (e *SpanExporter) flush(ctx context.Context) {
 	e.batchMu.Lock()
+	if e.ageTimer != nil {
+		e.ageTimer.Stop()
+		e.ageTimer = nil
+	}
+	e.lastFlush = time.Now()
+	resetFlushTicker(e.flushTicker, e.config, e.stopCh)
 	if len(e.batch) == 0 {
 		e.batchMu.Unlock()
 		return
 	}
 	
-	spans := make([]InternalSpan, len(e.batch))
-	copy(spans, e.batch)
-	e.batch = e.batch[:0]
+	// Swap in the spare buffer rather than copying, so Export callers only
+	// wait for a slice swap
+	spans := e.batch
+	e.batch = e.spare
+	if e.batch == nil {
+		e.batch = make([]InternalSpan, 0, e.config.BatchSize)
+	}
+	e.spare = nil
+	e.batchBytes = 0
 	e.batchMu.Unlock()
 	
-	e.sendBatch(spans)
+	e.sendBatch(ctx, spans)
+	
+	// Drop references to the sent entries and keep the buffer for next time
+	clear(spans)
+	e.batchMu.Lock()
+	if e.spare == nil {
+		e.spare = spans[:0]
+	}
+	e.batchMu.Unlock()
 }
 
-func (e *SpanExporter) sendBatch(spans []InternalSpan) {
-	env := "production"
-	if e.config.Debug {
-		env = "development"
+// armAgeTimerLocked schedules a flush once the oldest buffered entry reaches
+// MaxBufferAge. Must be called with batchMu held.
+func (e *SpanExporter) armAgeTimerLocked() {
+	if e.config.MaxBufferAge > 0 && e.ageTimer == nil {
+		e.ageTimer = time.AfterFunc(e.config.MaxBufferAge, func() {
+			e.flush(context.Background())
+		})
 	}
-	
-	payload := SpanBatchPayload{
-		Spans: spans,
+}
+
+func (e *SpanExporter) sendBatch(ctx context.Context, spans []InternalSpan) {
+	if e.config.BeforeSendSpans != nil {
+		if spans = e.config.BeforeSendSpans(spans); len(spans) == 0 {
+			return
+		}
 	}
-	
-	if releaseId := os.Getenv("LUMBERJACK_RELEASE_ID"); releaseId != "" {
-		payload.ReleaseId = releaseId
+	for _, part := range splitBatch(e.config, spans) {
+		e.sendRequest(ctx, part)
 	}
+}
+
+// sendRequest sends spans in a single request, spooling them if it fails.
+func (e *SpanExporter) sendRequest(ctx context.Context, spans []InternalSpan) {
+	env := e.config.environment()
 	
-	if releaseType := os.Getenv("LUMBERJACK_RELEASE_TYPE"); releaseType != "" {
-		payload.ReleaseType = releaseType
+	payload := SpanBatchPayload{
+		Spans:       spans,
+		ReleaseId:   e.releaseID,
+		ReleaseType: e.releaseType,
 	}
 	
 	request := SpanBatchRequest{
-		Type:    "span_batch",
-		Env:     env,
-		Ts:      time.Now().UnixMilli(),
-		Payload: payload,
+		Type:       "span_batch",
+		Env:        env,
+		Ts:         time.Now().UnixMilli(),
+		SdkVersion: sdkVersion,
+		SdkSemver:  sdkRelease(),
+		Payload:    payload,
+	}
+	
+	var data []byte
+	var err error
+	if streamBatch(e.config, spans) {
+		// Encode entry by entry into the request body instead of holding the
+		// whole payload in memory.
+		head := request
+		head.Payload.Spans = []InternalSpan{}
+		err = e.send(ctx, func() (io.ReadCloser, error) {
+			return streamJSONArray(head, "spans", spans)
+		})
+	} else {
+		buf, encErr := encodeJSON(request)
+		if encErr != nil {
+			e.config.debugf("Failed to marshal spans: %v\n", encErr)
+			e.stats.dropped.Add(uint64(len(spans)))
+			return
+		}
+		defer putJSONBuffer(buf)
+		data = buf.Bytes()
+		err = e.sendWithRetry(ctx, data)
 	}
 	
-	data, err := json.Marshal(request)
 	if err != nil {
-		if e.config.Debug {
-			fmt.Printf("Failed to marshal spans: %v\n", err)
+		e.config.reportExportError("spans", len(spans), err)
+		spooled := false
+		if data != nil {
+			spooled = e.spool.storeFailed(e.config, "spans", data, err)
+		} else {
+			spooled = e.spool.storeFailedJSON(e.config, "spans", request, err)
+		}
+		if !spooled {
+			e.stats.dropped.Add(uint64(len(spans)))
 		}
 		return
 	}
-	
-	e.sendWithRetry(data)
+	e.stats.recordFlushed(len(spans))
+	e.config.debugf("Successfully sent %d spans\n", len(spans))
+}
+
+func (e *SpanExporter) sendWithRetry(ctx context.Context, data []byte) error {
+	return e.send(ctx, func() (io.ReadCloser, error) {
+		return io.NopCloser(bytes.NewReader(data)), nil
+	})
 }
 
-func (e *SpanExporter) sendWithRetry(data []byte) {
+// send POSTs the body returned by body unless the circuit breaker is open,
+// and records the outcome with the breaker.
+func (e *SpanExporter) send(ctx context.Context, body func() (io.ReadCloser, error)) error {
+	if !e.breaker.allow() {
+		e.config.debugf("Circuit breaker open, not sending spans\n")
+		return errCircuitOpen
+	}
+	err := e.postWithRetry(ctx, body)
+	e.breaker.record(err)
+	return err
+}
+
+// postWithRetry POSTs the body returned by body, calling it again for every
+// attempt.
+func (e *SpanExporter) postWithRetry(ctx context.Context, body func() (io.ReadCloser, error)) error {
 	url := fmt.Sprintf("%s/spans/batch", e.config.BaseURL)
 	retries := 0
 	backoff := e.config.RetryBackoff
+	start := time.Now()
 	
 	for retries <= e.config.MaxRetries {
-		req, err := http.NewRequest("POST", url, bytes.NewBuffer(data))
+		reqBody, err := body()
 		if err != nil {
-			if e.config.Debug {
-				fmt.Printf("Failed to create request: %v\n", err)
-			}
-			return
+			return err
+		}
+		req, err := http.NewRequestWithContext(ctx, "POST", url, reqBody)
+		if err != nil {
+			reqBody.Close()
+			e.config.debugf("Failed to create request: %v\n", err)
+			return err
 		}
 		
 		req.Header.Set("Content-Type", "application/json")
-		req.Header.Set("Authorization", "Bearer "+e.config.APIKey)
+		req.Header.Set("User-Agent", e.config.userAgent())
+		if err := e.apiKey.setHeader(req); err != nil {
+			reqBody.Close()
+			e.config.debugf("Failed to authenticate request: %v\n", err)
+			return err
+		}
 		
 		resp, err := e.client.Do(req)
 		if err != nil {
-			if e.config.Debug {
-				fmt.Printf("Failed to send spans (attempt %d): %v\n", retries+1, err)
+			if ctx.Err() != nil {
+				e.config.debugf("Aborted sending spans: %v\n", ctx.Err())
+				return ctx.Err()
 			}
+			e.config.debugf("Failed to send spans (attempt %d): %v\n", retries+1, err)
 			retries++
 			if retries <= e.config.MaxRetries {
-				jitter := time.Duration(rand.Float64() * float64(backoff))
-				time.Sleep(backoff + jitter)
-				backoff *= 2
+				delay := jitteredBackoff(e.config, backoff)
+				if retryBudgetExceeded(e.config, start, delay) {
+					break
+				}
+				e.stats.retries.Add(1)
+				if err := sleepDelay(ctx, delay); err != nil {
+					return err
+				}
+				backoff = nextBackoff(e.config, backoff)
 			}
 			continue
 		}
@@ -255,33 +471,41 @@ func (e *SpanExporter) sendWithRetry(data []byte) {
 		resp.Body.Close()
 		
 		if resp.StatusCode == http.StatusOK {
-			if e.config.Debug {
-				var request SpanBatchRequest
-				json.Unmarshal(data, &request)
-				fmt.Printf("Successfully sent %d spans\n", len(request.Payload.Spans))
-			}
-			return
+			return nil
 		}
 		
-		if e.config.Debug {
-			fmt.Printf("Failed to send spans, status: %d\n", resp.StatusCode)
-		}
+		e.config.debugf("Failed to send spans, status: %d\n", resp.StatusCode)
 		
 		if resp.StatusCode >= 500 {
 			retries++
 			if retries <= e.config.MaxRetries {
-				jitter := time.Duration(rand.Float64() * float64(backoff))
-				time.Sleep(backoff + jitter)
-				backoff *= 2
+				delay := jitteredBackoff(e.config, backoff)
+				if retryBudgetExceeded(e.config, start, delay) {
+					break
+				}
+				e.stats.retries.Add(1)
+				if err := sleepDelay(ctx, delay); err != nil {
+					return err
+				}
+				backoff = nextBackoff(e.config, backoff)
 			}
 		} else {
-			break
+			return fmt.Errorf("%w: status %d", errPermanent, resp.StatusCode)
 		}
 	}
 	
-	if e.config.Debug && retries > e.config.MaxRetries {
-		fmt.Printf("Max retries exceeded for span batch\n")
+	if retries <= e.config.MaxRetries {
+		e.config.debugf("Retry time limit exceeded for span batch\n")
+		return fmt.Errorf("retry time limit of %v exceeded for span batch", e.config.MaxRetryElapsed)
 	}
+	
+	e.config.debugf("Max retries exceeded for span batch\n")
+	return fmt.Errorf("max retries exceeded for span batch")
+}
+
+// Stats returns a snapshot of the exporter's counters.
+func (e *SpanExporter) Stats() ExporterStats {
+	return e.stats.snapshot()
 }
 
 func (e *SpanExporter) Shutdown(ctx context.Context) error {
@@ -294,7 +518,7 @@ func (e *SpanExporter) Shutdown(ctx context.Context) error {
 	}
 	
 	e.flushTicker.Stop()
-	e.flush()
+	e.flush(ctx)
 	
 	done := make(chan struct{})
 	go func() {
@@ -0,0 +1,213 @@
+package lumberjack
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func newTestSpanExporter(t *testing.T, config *Config) *SpanExporter {
+	t.Helper()
+	exporter := NewSpanExporter(config)
+	t.Cleanup(func() { exporter.Shutdown(context.Background()) })
+	return exporter
+}
+
+func TestConvertSpanScrubsURLQueries(t *testing.T) {
+	config := testConfig("http://127.0.0.1:0").WithSafeQueryParams("page")
+	exporter := newTestSpanExporter(t, config)
+
+	stub := tracetest.SpanStub{
+		Name:      "GET /search",
+		StartTime: time.Now(),
+		EndTime:   time.Now(),
+		Attributes: []attribute.KeyValue{
+			attribute.String("http.url", "https://example.com/search?token=s3cr3t&page=2#top"),
+			attribute.String("http.target", "/search?api_key=abc&PAGE=3"),
+			attribute.String("db.statement", "SELECT * FROM t WHERE token=?"),
+		},
+	}
+
+	span := exporter.convertSpan(stub.Snapshot())
+
+	want := map[string]string{
+		"http.url":     "https://example.com/search?token=REDACTED&page=2#top",
+		"http.target":  "/search?api_key=REDACTED&PAGE=3",
+		"db.statement": "SELECT * FROM t WHERE token=?",
+	}
+	for key, value := range want {
+		if got := span.Attributes[key]; got != value {
+			t.Errorf("attribute %q = %q, want %q", key, got, value)
+		}
+	}
+}
+
+func TestConvertSpanRendersNonStringAttributes(t *testing.T) {
+	exporter := newTestSpanExporter(t, testConfig("http://127.0.0.1:0"))
+
+	stub := tracetest.SpanStub{
+		Attributes: []attribute.KeyValue{attribute.Int("http.status_code", 503), attribute.Bool("cache.hit", true)},
+		Events:     []sdktrace.Event{{Name: "retry", Attributes: []attribute.KeyValue{attribute.Int("attempt", 2)}}},
+		Links:      []sdktrace.Link{{Attributes: []attribute.KeyValue{attribute.Float64("weight", 0.5)}}},
+	}
+
+	span := exporter.convertSpan(stub.Snapshot())
+	if got := span.Attributes["http.status_code"]; got != "503" {
+		t.Errorf("http.status_code = %q, want %q", got, "503")
+	}
+	if got := span.Attributes["cache.hit"]; got != "true" {
+		t.Errorf("cache.hit = %q, want %q", got, "true")
+	}
+	if len(span.Events) != 1 || span.Events[0].Attributes["attempt"] != "2" {
+		t.Errorf("events = %+v, want the attempt attribute rendered as %q", span.Events, "2")
+	}
+	if len(span.Links) != 1 || span.Links[0].Attributes["weight"] != "0.5" {
+		t.Errorf("links = %+v, want the weight attribute rendered as %q", span.Links, "0.5")
+	}
+}
+
+func TestConvertSpanScrubsURLQueriesByDefault(t *testing.T) {
+	// A literal Config, not NewConfig, still scrubs
+	config := &Config{BaseURL: "http://127.0.0.1:0", BatchTimeout: time.Hour}
+	exporter := newTestSpanExporter(t, config)
+
+	stub := tracetest.SpanStub{
+		Attributes: []attribute.KeyValue{attribute.String("http.url", "https://example.com/?token=s3cr3t")},
+	}
+
+	span := exporter.convertSpan(stub.Snapshot())
+	if got := span.Attributes["http.url"]; got != "https://example.com/?token=REDACTED" {
+		t.Errorf("http.url = %q, want the query scrubbed by a zero Config", got)
+	}
+}
+
+func TestConvertSpanURLScrubbingDisabled(t *testing.T) {
+	config := testConfig("http://127.0.0.1:0").WithKeepURLQueries(true)
+	exporter := newTestSpanExporter(t, config)
+
+	stub := tracetest.SpanStub{
+		Attributes: []attribute.KeyValue{attribute.String("http.url", "https://example.com/?token=s3cr3t")},
+	}
+
+	span := exporter.convertSpan(stub.Snapshot())
+	if got := span.Attributes["http.url"]; got != "https://example.com/?token=s3cr3t" {
+		t.Errorf("http.url = %q, want it unchanged when scrubbing is disabled", got)
+	}
+}
+
+func TestSpanLinksExported(t *testing.T) {
+	server := newCaptureServer(t)
+	sdk := newSDK(testConfig(server.URL))
+
+	link, err := LinkFromTraceparent("00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01", attribute.String("messaging.operation", "publish"))
+	if err != nil {
+		t.Fatalf("LinkFromTraceparent() unexpected error = %v", err)
+	}
+	_, span := sdk.StartSpan(context.Background(), "consume", trace.WithLinks(link))
+	span.End()
+	sdk.tracerProvider.ForceFlush(context.Background())
+	sdk.Shutdown(context.Background())
+
+	spans := exportedSpans(t, server)
+	if len(spans) != 1 {
+		t.Fatalf("expected 1 exported span, got %d", len(spans))
+	}
+	links := spans[0].Links
+	if len(links) != 1 {
+		t.Fatalf("exported links = %+v, want exactly one", links)
+	}
+	if links[0].TraceID != "4bf92f3577b34da6a3ce929d0e0e4736" || links[0].SpanID != "00f067aa0ba902b7" {
+		t.Errorf("link = %s/%s, want the traceparent's ids", links[0].TraceID, links[0].SpanID)
+	}
+	if got := links[0].Attributes["messaging.operation"]; got != "publish" {
+		t.Errorf("link attribute messaging.operation = %q, want %q", got, "publish")
+	}
+}
+
+func TestLinkFromTraceparentInvalid(t *testing.T) {
+	if _, err := LinkFromTraceparent("not-a-traceparent"); !errors.Is(err, ErrTraceparentFormat) {
+		t.Errorf("LinkFromTraceparent() error = %v, want ErrTraceparentFormat", err)
+	}
+}
+
+func TestSpanExporterShutdownTwice(t *testing.T) {
+	exporter := NewSpanExporter(testConfig("http://127.0.0.1:0"))
+
+	if err := exporter.Shutdown(context.Background()); err != nil {
+		t.Fatalf("first Shutdown() error = %v", err)
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			t.Fatalf("second Shutdown() panicked: %v", r)
+		}
+	}()
+	if err := exporter.Shutdown(context.Background()); err != nil {
+		t.Errorf("second Shutdown() error = %v, want nil", err)
+	}
+}
+
+func TestSimpleSpanProcessor(t *testing.T) {
+	server := newCaptureServer(t)
+	sdk := newSDK(testConfig(server.URL).WithSpanProcessor(SimpleSpanProcessor))
+	defer sdk.Shutdown(context.Background())
+
+	_, span := sdk.StartSpan(context.Background(), "cli-command")
+	span.End()
+
+	// No flush: the span was sent synchronously by End
+	spans := exportedSpans(t, server)
+	if len(spans) != 1 || spans[0].Name != "cli-command" {
+		t.Errorf("exported spans = %+v, want cli-command right after End", spans)
+	}
+}
+
+func TestSpanInstrumentationScope(t *testing.T) {
+	server := newCaptureServer(t)
+	sdk := newSDK(testConfig(server.URL))
+
+	tracer := sdk.tracerProvider.Tracer("github.com/acme/dbdriver", trace.WithInstrumentationVersion("1.4.0"))
+	_, span := tracer.Start(context.Background(), "query")
+	span.End()
+	_, span = sdk.StartSpan(context.Background(), "handler")
+	span.End()
+	// Drain the batch span processor before the exporter shuts down
+	sdk.tracerProvider.ForceFlush(context.Background())
+	sdk.Shutdown(context.Background())
+
+	scopes := map[string][2]string{}
+	for _, span := range exportedSpans(t, server) {
+		scopes[span.Name] = [2]string{span.ScopeName, span.ScopeVersion}
+	}
+	if got := scopes["query"]; got != [2]string{"github.com/acme/dbdriver", "1.4.0"} {
+		t.Errorf("query span scope = %v, want the named tracer's name and version", got)
+	}
+	if got := scopes["handler"]; got[0] != "lumberjack" {
+		t.Errorf("handler span scope = %v, want the SDK's own tracer", got)
+	}
+}
+
+func TestConvertSpanStatusMessage(t *testing.T) {
+	exporter := newTestSpanExporter(t, testConfig("http://127.0.0.1:0"))
+
+	tests := []struct {
+		status   sdktrace.Status
+		wantCode int
+	}{
+		{sdktrace.Status{Code: codes.Error, Description: "connection refused"}, 2},
+		{sdktrace.Status{Code: codes.Ok, Description: "retried once"}, 1},
+	}
+	for _, tt := range tests {
+		stub := tracetest.SpanStub{Name: "call", Status: tt.status}
+		span := exporter.convertSpan(stub.Snapshot())
+		if span.StatusCode != tt.wantCode || span.StatusMessage != tt.status.Description {
+			t.Errorf("status = %d %q, want %d %q", span.StatusCode, span.StatusMessage, tt.wantCode, tt.status.Description)
+		}
+	}
+}
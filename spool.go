@@ -0,0 +1,238 @@
+package lumberjack
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	defaultSpoolMaxBytes       = 64 << 20
+	defaultSpoolReplayInterval = 30 * time.Second
+	spoolFileSuffix            = ".ndjson"
+)
+
+// spool persists batches that could not be delivered so they survive
+// connectivity loss. Each failed batch is written as one JSON line to its own
+// file; file names sort in creation order so replay and eviction are
+// oldest-first.
+type spool struct {
+	dir      string
+	maxBytes int64
+
+	mu  sync.Mutex
+	seq uint64
+}
+
+func newSpool(dir string, maxBytes int64) (*spool, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create spool directory: %w", err)
+	}
+	if maxBytes <= 0 {
+		maxBytes = defaultSpoolMaxBytes
+	}
+	return &spool{dir: dir, maxBytes: maxBytes}, nil
+}
+
+// newExporterSpool creates the spool for one signal under Config.SpoolDir, or
+// returns nil when spooling is disabled or the directory is unusable.
+func newExporterSpool(config *Config, signal string) *spool {
+	if config.SpoolDir == "" {
+		return nil
+	}
+	s, err := newSpool(filepath.Join(config.SpoolDir, signal), config.SpoolMaxBytes)
+	if err != nil {
+		config.debugf("Spooling disabled for %s: %v\n", signal, err)
+		return nil
+	}
+	return s
+}
+
+// store writes data to a new spool file and evicts the oldest files if the
+// spool grows beyond maxBytes.
+func (s *spool) store(data []byte) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.seq++
+	name := fmt.Sprintf("%020d-%06d%s", time.Now().UnixNano(), s.seq%1000000, spoolFileSuffix)
+	tmp := filepath.Join(s.dir, name+".tmp")
+
+	line := make([]byte, 0, len(data)+1)
+	line = append(append(line, data...), '\n')
+	if err := os.WriteFile(tmp, line, 0o644); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp, filepath.Join(s.dir, name)); err != nil {
+		os.Remove(tmp)
+		return err
+	}
+
+	return s.evictLocked()
+}
+
+// files lists spool files oldest-first along with their sizes.
+func (s *spool) files() ([]string, []int64, error) {
+	entries, err := os.ReadDir(s.dir)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var names []string
+	sizes := make(map[string]int64)
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), spoolFileSuffix) {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil {
+			continue
+		}
+		names = append(names, entry.Name())
+		sizes[entry.Name()] = info.Size()
+	}
+	sort.Strings(names)
+
+	ordered := make([]int64, len(names))
+	for i, name := range names {
+		ordered[i] = sizes[name]
+	}
+	return names, ordered, nil
+}
+
+func (s *spool) evictLocked() error {
+	names, sizes, err := s.files()
+	if err != nil {
+		return err
+	}
+
+	var total int64
+	for _, size := range sizes {
+		total += size
+	}
+	for i := 0; total > s.maxBytes && i < len(names); i++ {
+		if err := os.Remove(filepath.Join(s.dir, names[i])); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+		total -= sizes[i]
+	}
+	return nil
+}
+
+// replay re-sends spooled batches oldest-first, deleting each file once all
+// of its lines were delivered. A line that fails permanently (e.g. a 4xx) is
+// dropped so it can't block the batches behind it. Any other failure stops
+// replay so ordering is preserved and the endpoint isn't hammered while it
+// is still down; the file is first rewritten without the lines already
+// delivered, so they aren't sent again on the next attempt.
+func (s *spool) replay(send func([]byte) error) error {
+	s.mu.Lock()
+	names, _, err := s.files()
+	s.mu.Unlock()
+	if err != nil {
+		return err
+	}
+
+	for _, name := range names {
+		path := filepath.Join(s.dir, name)
+		content, err := os.ReadFile(path)
+		if errors.Is(err, os.ErrNotExist) {
+			continue // evicted concurrently
+		}
+		if err != nil {
+			return err
+		}
+
+		var lines [][]byte
+		for _, line := range bytes.Split(content, []byte{'\n'}) {
+			if len(line) > 0 {
+				lines = append(lines, line)
+			}
+		}
+		for i, line := range lines {
+			if err := send(line); err != nil && !errors.Is(err, errPermanent) {
+				if i > 0 {
+					s.rewrite(path, lines[i:])
+				}
+				return err
+			}
+		}
+
+		s.mu.Lock()
+		os.Remove(path)
+		s.mu.Unlock()
+	}
+	return nil
+}
+
+// rewrite replaces the spool file at path with lines, unless it has been
+// evicted in the meantime.
+func (s *spool) rewrite(path string, lines [][]byte) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, err := os.Stat(path); err != nil {
+		return err
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, append(bytes.Join(lines, []byte{'\n'}), '\n'), 0o644); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		os.Remove(tmp)
+		return err
+	}
+	return nil
+}
+
+// run replays the spool every interval until stopCh is closed.
+func (s *spool) run(interval time.Duration, stopCh <-chan struct{}, send func([]byte) error) {
+	if interval <= 0 {
+		interval = defaultSpoolReplayInterval
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			s.replay(send)
+		case <-stopCh:
+			return
+		}
+	}
+}
+
+// storeFailed spools a batch that sendWithRetry gave up on, reporting
+// whether it was stored. Permanent failures (e.g. a 400 for a malformed
+// payload) are not spooled since replaying them can never succeed.
+func (s *spool) storeFailed(config *Config, signal string, data []byte, err error) bool {
+	if s == nil || errors.Is(err, errPermanent) {
+		return false
+	}
+	if err := s.store(data); err != nil {
+		config.debugf("Failed to spool %s batch: %v\n", signal, err)
+		return false
+	}
+	return true
+}
+
+// storeFailedJSON is storeFailed for a streamed batch, which has no encoded
+// copy to spool; it is only encoded if it will actually be stored.
+func (s *spool) storeFailedJSON(config *Config, signal string, v any, err error) bool {
+	if s == nil || errors.Is(err, errPermanent) {
+		return false
+	}
+	buf, encErr := encodeJSON(v)
+	if encErr != nil {
+		config.debugf("Failed to spool %s batch: %v\n", signal, encErr)
+		return false
+	}
+	defer putJSONBuffer(buf)
+	return s.storeFailed(config, signal, buf.Bytes(), err)
+}
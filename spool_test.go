@@ -0,0 +1,174 @@
+package lumberjack
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"go.opentelemetry.io/otel/log"
+	sdklog "go.opentelemetry.io/otel/sdk/log"
+)
+
+func TestSpoolReplaysAfterOutage(t *testing.T) {
+	// Reserve an address, then close it so the endpoint is "down"
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+	addr := listener.Addr().String()
+	listener.Close()
+
+	spoolDir := t.TempDir()
+	config := testConfig("http://" + addr).WithSpoolDir(spoolDir)
+	config.BatchSize = 1
+	config.MaxRetries = 0
+	config.SpoolReplayInterval = 50 * time.Millisecond
+	exporter := NewLogsExporter(config)
+	defer exporter.Shutdown(context.Background())
+
+	if err := exporter.Export(context.Background(), []*sdklog.Record{newTestRecord("offline log", log.SeverityInfo)}); err != nil {
+		t.Fatalf("Export() unexpected error = %v", err)
+	}
+
+	files, _ := filepath.Glob(filepath.Join(spoolDir, "logs", "*.ndjson"))
+	if len(files) != 1 {
+		t.Fatalf("expected 1 spooled batch, found %d", len(files))
+	}
+
+	// Bring the endpoint back on the same address
+	received := make(chan LogRequest, 1)
+	listener, err = net.Listen("tcp", addr)
+	if err != nil {
+		t.Fatalf("failed to re-listen on %s: %v", addr, err)
+	}
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req LogRequest
+		body, _ := io.ReadAll(r.Body)
+		json.Unmarshal(body, &req)
+		w.WriteHeader(http.StatusOK)
+		received <- req
+	}))
+	server.Listener = listener
+	server.Start()
+	defer server.Close()
+
+	select {
+	case req := <-received:
+		if len(req.Logs) != 1 || req.Logs[0].Msg != "offline log" {
+			t.Errorf("replayed request = %+v, want the spooled log", req)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatalf("spooled batch was not replayed")
+	}
+
+	// The spool file is removed once delivered
+	deadline := time.Now().Add(time.Second)
+	for time.Now().Before(deadline) {
+		if files, _ := filepath.Glob(filepath.Join(spoolDir, "logs", "*.ndjson")); len(files) == 0 {
+			return
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Errorf("spool file was not removed after replay")
+}
+
+func TestSpoolEvictsOldest(t *testing.T) {
+	s, err := newSpool(t.TempDir(), 25)
+	if err != nil {
+		t.Fatalf("newSpool() unexpected error = %v", err)
+	}
+
+	for _, batch := range []string{`{"batch":1}`, `{"batch":2}`, `{"batch":3}`} {
+		if err := s.store([]byte(batch)); err != nil {
+			t.Fatalf("store() unexpected error = %v", err)
+		}
+	}
+
+	var replayed []string
+	if err := s.replay(func(data []byte) error {
+		replayed = append(replayed, string(data))
+		return nil
+	}); err != nil {
+		t.Fatalf("replay() unexpected error = %v", err)
+	}
+
+	// Each file is 12 bytes, so only the two newest fit in 25 bytes
+	if len(replayed) != 2 || replayed[0] != `{"batch":2}` || replayed[1] != `{"batch":3}` {
+		t.Errorf("replayed = %v, want the two newest batches in order", replayed)
+	}
+	if entries, _ := os.ReadDir(s.dir); len(entries) != 0 {
+		t.Errorf("spool still holds %d files after a successful replay", len(entries))
+	}
+}
+
+func TestSpoolReplaySkipsPermanentFailures(t *testing.T) {
+	s, err := newSpool(t.TempDir(), 0)
+	if err != nil {
+		t.Fatalf("newSpool() unexpected error = %v", err)
+	}
+	for _, batch := range []string{`{"batch":1}`, `{"batch":2}`} {
+		if err := s.store([]byte(batch)); err != nil {
+			t.Fatalf("store() unexpected error = %v", err)
+		}
+	}
+
+	var replayed []string
+	err = s.replay(func(data []byte) error {
+		replayed = append(replayed, string(data))
+		if string(data) == `{"batch":1}` {
+			return fmt.Errorf("%w: status 400", errPermanent)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("replay() unexpected error = %v", err)
+	}
+	if len(replayed) != 2 || replayed[1] != `{"batch":2}` {
+		t.Errorf("replayed = %v, want the batch behind the rejected one delivered", replayed)
+	}
+	if entries, _ := os.ReadDir(s.dir); len(entries) != 0 {
+		t.Errorf("spool still holds %d files, want the rejected batch dropped", len(entries))
+	}
+}
+
+func TestSpoolReplayResumesWithinFile(t *testing.T) {
+	s, err := newSpool(t.TempDir(), 0)
+	if err != nil {
+		t.Fatalf("newSpool() unexpected error = %v", err)
+	}
+	if err := s.store([]byte("{\"batch\":1}\n{\"batch\":2}\n{\"batch\":3}")); err != nil {
+		t.Fatalf("store() unexpected error = %v", err)
+	}
+
+	var replayed []string
+	down := true
+	send := func(data []byte) error {
+		if down && string(data) == `{"batch":2}` {
+			return errors.New("connection refused")
+		}
+		replayed = append(replayed, string(data))
+		return nil
+	}
+	if err := s.replay(send); err == nil {
+		t.Fatal("replay() succeeded while the endpoint was down")
+	}
+	down = false
+	if err := s.replay(send); err != nil {
+		t.Fatalf("replay() unexpected error = %v", err)
+	}
+
+	want := []string{`{"batch":1}`, `{"batch":2}`, `{"batch":3}`}
+	if !reflect.DeepEqual(replayed, want) {
+		t.Errorf("replayed = %v, want each line delivered exactly once: %v", replayed, want)
+	}
+}
@@ -0,0 +1,178 @@
+//go:build !windows && !plan9
+
+package lumberjack
+
+import (
+	"context"
+	"log/syslog"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/otel/log"
+	sdklog "go.opentelemetry.io/otel/sdk/log"
+)
+
+const (
+	// syslogDialTimeout bounds how long Export waits for a connection; a
+	// dial still in flight after that carries on in the background
+	syslogDialTimeout = 2 * time.Second
+	// syslogMinBackoff and syslogMaxBackoff bound the wait between failed
+	// connection attempts, doubling on each failure
+	syslogMinBackoff = time.Second
+	syslogMaxBackoff = time.Minute
+)
+
+// SyslogLogsExporter is a LogsExporter that forwards each log entry to a
+// syslog daemon with the LOG_USER facility, tagged with the project name.
+// Levels map to syslog severities: TRACE and DEBUG to debug, INFO to info,
+// WARN to warning, ERROR to err and FATAL to crit. If the daemon can't be
+// reached, entries are dropped and the connection is retried with backoff,
+// so syslog being down never fails or stalls logging. On platforms without
+// syslog (Windows, Plan 9) it drops every entry.
+//
+//	config.WithCustomLogsExporter(lumberjack.MultiLogsExporter(
+//		lumberjack.NewLogsExporter(config),
+//		lumberjack.NewSyslogLogsExporter(config, "", ""), // local daemon
+//	))
+type SyslogLogsExporter struct {
+	logConverter
+	network string
+	addr    string
+	tag     string
+
+	mu      sync.Mutex
+	writer  *syslog.Writer
+	closed  bool
+	dialing chan struct{} // closed when the dial in flight, if any, finishes
+	retryAt time.Time
+	backoff time.Duration
+}
+
+// NewSyslogLogsExporter returns an exporter writing to the syslog daemon at
+// addr over network ("udp", "tcp" or "unix"), or to the local daemon if
+// network is empty. It connects lazily, on the first Export.
+func NewSyslogLogsExporter(config *Config, network, addr string) *SyslogLogsExporter {
+	return &SyslogLogsExporter{
+		logConverter: newLogConverter(config),
+		network:      network,
+		addr:         addr,
+		tag:          syslogTag(config),
+	}
+}
+
+func (e *SyslogLogsExporter) Export(ctx context.Context, records []*sdklog.Record) error {
+	writer := e.connect(ctx)
+	if writer == nil {
+		e.config.debugf("Syslog unavailable, dropping %d log entries\n", len(records))
+		return nil
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.writer != writer {
+		// Shut down, or the connection failed, while we waited for it
+		return nil
+	}
+	for _, record := range records {
+		if err := e.write(record.Severity(), formatSyslogMessage(e.convertRecordToEntry(record))); err != nil {
+			e.config.debugf("Failed to write to syslog: %v\n", err)
+			e.writer.Close()
+			e.writer = nil
+			e.scheduleRetryLocked()
+			return nil
+		}
+	}
+	return nil
+}
+
+// connect returns the current connection, starting a dial if there is none
+// and the backoff has passed. It waits up to syslogDialTimeout, or until ctx
+// is done, for the dial, and returns nil if there is still no connection.
+func (e *SyslogLogsExporter) connect(ctx context.Context) *syslog.Writer {
+	e.mu.Lock()
+	if e.closed || e.writer != nil {
+		writer := e.writer
+		e.mu.Unlock()
+		return writer
+	}
+	done := e.dialing
+	if done == nil {
+		if time.Now().Before(e.retryAt) {
+			e.mu.Unlock()
+			return nil
+		}
+		done = make(chan struct{})
+		e.dialing = done
+		go e.dial(done)
+	}
+	e.mu.Unlock()
+
+	timer := time.NewTimer(syslogDialTimeout)
+	defer timer.Stop()
+	select {
+	case <-done:
+	case <-timer.C:
+	case <-ctx.Done():
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.writer
+}
+
+// dial connects to the daemon without holding mu, then installs the
+// connection, or schedules the next attempt if it failed.
+func (e *SyslogLogsExporter) dial(done chan struct{}) {
+	writer, err := syslog.Dial(e.network, e.addr, syslog.LOG_USER|syslog.LOG_INFO, e.tag)
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	defer close(done)
+	e.dialing = nil
+	if err != nil {
+		e.config.debugf("Failed to connect to syslog: %v\n", err)
+		e.scheduleRetryLocked()
+		return
+	}
+	if e.closed {
+		writer.Close()
+		return
+	}
+	e.writer = writer
+	e.backoff = 0
+}
+
+// scheduleRetryLocked delays the next connection attempt, doubling the delay
+// after each consecutive failure. Must be called with mu held.
+func (e *SyslogLogsExporter) scheduleRetryLocked() {
+	e.backoff = min(max(2*e.backoff, syslogMinBackoff), syslogMaxBackoff)
+	e.retryAt = time.Now().Add(e.backoff)
+}
+
+// write sends msg with the syslog severity matching sev.
+func (e *SyslogLogsExporter) write(sev log.Severity, msg string) error {
+	switch {
+	case sev >= log.SeverityFatal:
+		return e.writer.Crit(msg)
+	case sev >= log.SeverityError:
+		return e.writer.Err(msg)
+	case sev >= log.SeverityWarn:
+		return e.writer.Warning(msg)
+	case sev >= log.SeverityInfo:
+		return e.writer.Info(msg)
+	default:
+		return e.writer.Debug(msg)
+	}
+}
+
+func (e *SyslogLogsExporter) Shutdown(ctx context.Context) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.closed = true
+	if e.writer == nil {
+		return nil
+	}
+	err := e.writer.Close()
+	e.writer = nil
+	return err
+}
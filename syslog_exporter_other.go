@@ -0,0 +1,34 @@
+//go:build windows || plan9
+
+package lumberjack
+
+import (
+	"context"
+	"sync"
+
+	sdklog "go.opentelemetry.io/otel/sdk/log"
+)
+
+// SyslogLogsExporter drops every entry on this platform, which has no
+// syslog; see the Unix implementation for details.
+type SyslogLogsExporter struct {
+	config *Config
+	warned sync.Once
+}
+
+// NewSyslogLogsExporter returns an exporter that drops every entry, as syslog
+// is unavailable on this platform.
+func NewSyslogLogsExporter(config *Config, network, addr string) *SyslogLogsExporter {
+	return &SyslogLogsExporter{config: config}
+}
+
+func (e *SyslogLogsExporter) Export(ctx context.Context, records []*sdklog.Record) error {
+	e.warned.Do(func() {
+		e.config.warnf("syslog is not supported on this platform; SyslogLogsExporter drops all logs\n")
+	})
+	return nil
+}
+
+func (e *SyslogLogsExporter) Shutdown(ctx context.Context) error {
+	return nil
+}
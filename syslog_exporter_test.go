@@ -0,0 +1,79 @@
+//go:build !windows && !plan9
+
+package lumberjack
+
+import (
+	"context"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"go.opentelemetry.io/otel/log"
+	sdklog "go.opentelemetry.io/otel/sdk/log"
+)
+
+func TestSyslogLogsExporter(t *testing.T) {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Skipf("no UDP listener: %v", err)
+	}
+	defer conn.Close()
+
+	config := testConfig("")
+	config.WithCustomLogsExporter(NewSyslogLogsExporter(config, "udp", conn.LocalAddr().String()))
+	sdk := newSDK(config)
+	defer sdk.Shutdown(context.Background())
+
+	sdk.Logger().Error("payment failed", "order_id", "A-1", "reason", "card declined")
+
+	conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	buf := make([]byte, 4096)
+	n, _, err := conn.ReadFrom(buf)
+	if err != nil {
+		t.Fatalf("no syslog message received: %v", err)
+	}
+	msg := string(buf[:n])
+
+	// LOG_USER (1) * 8 + LOG_ERR (3)
+	if !strings.HasPrefix(msg, "<11>") {
+		t.Errorf("message %q, want priority <11> for an ERROR log", msg)
+	}
+	if !strings.Contains(msg, " test[") {
+		t.Errorf("message %q, want it tagged with the project name", msg)
+	}
+	if want := `payment failed order_id=A-1 reason="card declined"`; !strings.Contains(msg, want) {
+		t.Errorf("message %q, want it to contain %q", msg, want)
+	}
+}
+
+func TestSyslogLogsExporterUnavailable(t *testing.T) {
+	exporter := NewSyslogLogsExporter(testConfig(""), "tcp", "127.0.0.1:1")
+	defer exporter.Shutdown(context.Background())
+
+	record := newTestRecord("dropped", log.SeverityInfo)
+	if err := exporter.Export(context.Background(), []*sdklog.Record{record}); err != nil {
+		t.Errorf("Export with syslog unavailable = %v, want nil", err)
+	}
+}
+
+func TestSyslogLogsExporterBacksOffReconnects(t *testing.T) {
+	exporter := NewSyslogLogsExporter(testConfig(""), "tcp", "127.0.0.1:1")
+	defer exporter.Shutdown(context.Background())
+
+	records := []*sdklog.Record{newTestRecord("dropped", log.SeverityInfo)}
+	for i := 0; i < 3; i++ {
+		if err := exporter.Export(context.Background(), records); err != nil {
+			t.Fatalf("Export with syslog unavailable = %v, want nil", err)
+		}
+	}
+
+	exporter.mu.Lock()
+	defer exporter.mu.Unlock()
+	if exporter.backoff != syslogMinBackoff {
+		t.Errorf("backoff = %v, want a single failed dial (%v) while backing off", exporter.backoff, syslogMinBackoff)
+	}
+	if !exporter.retryAt.After(time.Now()) {
+		t.Errorf("retryAt = %v, want the next dial scheduled in the future", exporter.retryAt)
+	}
+}
@@ -0,0 +1,52 @@
+package lumberjack
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// defaultSyslogTag tags syslog messages when the config has no project name.
+const defaultSyslogTag = "lumberjack"
+
+func syslogTag(config *Config) string {
+	if config.ProjectName != "" {
+		return config.ProjectName
+	}
+	return defaultSyslogTag
+}
+
+// formatSyslogMessage renders entry as its message followed by its props,
+// trace ID and span ID as key=value pairs in key order, quoting values that
+// contain spaces, quotes or equals signs.
+func formatSyslogMessage(entry LogEntry) string {
+	keys := make([]string, 0, len(entry.Props))
+	for key := range entry.Props {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var b strings.Builder
+	b.WriteString(entry.Msg)
+	for _, key := range keys {
+		writeSyslogPair(&b, key, fmt.Sprint(entry.Props[key]))
+	}
+	if entry.Tid != "" {
+		writeSyslogPair(&b, "trace_id", entry.Tid)
+	}
+	if entry.Sid != "" {
+		writeSyslogPair(&b, "span_id", entry.Sid)
+	}
+	return b.String()
+}
+
+func writeSyslogPair(b *strings.Builder, key, value string) {
+	b.WriteByte(' ')
+	b.WriteString(key)
+	b.WriteByte('=')
+	if value == "" || strings.ContainsAny(value, " \t\n\"=") {
+		value = strconv.Quote(value)
+	}
+	b.WriteString(value)
+}
@@ -0,0 +1,76 @@
+package lumberjack
+
+import "context"
+
+// projectRoute is the Lumberjack project, and the API key for it, that a log
+// entry is sent to in place of the configured ones.
+type projectRoute struct {
+	name string
+	key  string
+}
+
+type projectKey struct{}
+
+// requestRouteKey carries the project route of an outgoing log request to
+// apiKeySource. It is set for every request so a route on the context that
+// triggered a flush never leaks into other tenants' batches.
+type requestRouteKey struct{}
+
+// ContextWithProject returns a copy of ctx whose logs are sent to project
+// name with key, instead of Config.ProjectName and Config.APIKey, for hosts
+// that log on behalf of several tenants. Entries for different projects are
+// batched separately. An empty key uses the configured key (or
+// TokenProvider). Batches for a context project that fail to send are
+// dropped rather than spooled, as a replay would send them with the wrong
+// key.
+func ContextWithProject(ctx context.Context, name, key string) context.Context {
+	return context.WithValue(ctx, projectKey{}, &projectRoute{name: name, key: key})
+}
+
+// projectFromContext returns the route set with ContextWithProject, or nil.
+func projectFromContext(ctx context.Context) *projectRoute {
+	if ctx == nil {
+		return nil
+	}
+	route, _ := ctx.Value(projectKey{}).(*projectRoute)
+	return route
+}
+
+// projectBatch is the entries of a batch bound for one project; a nil route
+// is the configured project.
+type projectBatch struct {
+	route   *projectRoute
+	entries []LogEntry
+}
+
+// groupByProject splits entries by project, keeping their order within each
+// project and ordering projects by first appearance.
+func groupByProject(entries []LogEntry) []projectBatch {
+	routed := false
+	for i := range entries {
+		if entries[i].project != nil {
+			routed = true
+			break
+		}
+	}
+	if !routed {
+		return []projectBatch{{entries: entries}}
+	}
+
+	var batches []projectBatch
+	index := make(map[projectRoute]int)
+	for _, entry := range entries {
+		var route projectRoute
+		if entry.project != nil {
+			route = *entry.project
+		}
+		i, ok := index[route]
+		if !ok {
+			i = len(batches)
+			index[route] = i
+			batches = append(batches, projectBatch{route: entry.project})
+		}
+		batches[i].entries = append(batches[i].entries, entry)
+	}
+	return batches
+}
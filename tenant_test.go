@@ -0,0 +1,92 @@
+package lumberjack
+
+import (
+	"context"
+	"encoding/json"
+	"slices"
+	"testing"
+
+	"go.opentelemetry.io/otel/log"
+	sdklog "go.opentelemetry.io/otel/sdk/log"
+)
+
+func TestContextWithProject(t *testing.T) {
+	server := newCaptureServer(t)
+	exporter := NewLogsExporter(testConfig(server.URL))
+	defer exporter.Shutdown(context.Background())
+
+	acme := ContextWithProject(context.Background(), "acme", "acme-key")
+	globex := ContextWithProject(context.Background(), "globex", "globex-key")
+	for _, export := range []struct {
+		ctx context.Context
+		msg string
+	}{
+		{acme, "acme order"},
+		{globex, "globex order"},
+		{acme, "acme refund"},
+		{context.Background(), "host log"},
+	} {
+		if err := exporter.Export(export.ctx, []*sdklog.Record{newTestRecord(export.msg, log.SeverityInfo)}); err != nil {
+			t.Fatalf("Export: %v", err)
+		}
+	}
+	if err := exporter.ForceFlush(context.Background()); err != nil {
+		t.Fatalf("ForceFlush: %v", err)
+	}
+
+	requests := server.requestsFor("/logs/batch")
+	if len(requests) != 3 {
+		t.Fatalf("got %d requests, want one per project", len(requests))
+	}
+	want := map[string]struct {
+		key  string
+		msgs []string
+	}{
+		"acme":   {"Bearer acme-key", []string{"acme order", "acme refund"}},
+		"globex": {"Bearer globex-key", []string{"globex order"}},
+		"test":   {"Bearer test-key", []string{"host log"}},
+	}
+	for _, req := range requests {
+		var body LogRequest
+		if err := json.Unmarshal(req.Body, &body); err != nil {
+			t.Fatalf("decoding request: %v", err)
+		}
+		w, ok := want[body.ProjectName]
+		if !ok {
+			t.Errorf("unexpected project_name %q", body.ProjectName)
+			continue
+		}
+		delete(want, body.ProjectName)
+		if got := req.Header.Get("Authorization"); got != w.key {
+			t.Errorf("project %q sent with key %q, want %q", body.ProjectName, got, w.key)
+		}
+		var msgs []string
+		for _, entry := range body.Logs {
+			msgs = append(msgs, entry.Msg)
+		}
+		if !slices.Equal(msgs, w.msgs) {
+			t.Errorf("project %q got %v, want %v", body.ProjectName, msgs, w.msgs)
+		}
+	}
+	if len(want) != 0 {
+		t.Errorf("no request for projects %v", want)
+	}
+}
+
+func TestContextWithProjectDefaultsKey(t *testing.T) {
+	server := newCaptureServer(t)
+	exporter := NewLogsExporter(testConfig(server.URL))
+	defer exporter.Shutdown(context.Background())
+
+	exporter.sendBatch(context.Background(), []LogEntry{
+		{Msg: "tenant log", project: &projectRoute{name: "acme"}},
+	})
+
+	reqs := server.decodeLogRequests(t)
+	if len(reqs) != 1 || reqs[0].ProjectName != "acme" {
+		t.Fatalf("requests = %+v, want one for project acme", reqs)
+	}
+	if got := server.requestsFor("/logs/batch")[0].Header.Get("Authorization"); got != "Bearer test-key" {
+		t.Errorf("Authorization = %q, want the configured key", got)
+	}
+}
@@ -0,0 +1,153 @@
+package lumberjack
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+// captureServer is a stub Lumberjack endpoint that records every request body
+// it receives, keyed by path.
+type captureServer struct {
+	*httptest.Server
+
+	mu       sync.Mutex
+	requests []capturedRequest
+	received chan struct{}
+	status   int
+}
+
+type capturedRequest struct {
+	Path   string
+	Header http.Header
+	Body   []byte
+}
+
+func newCaptureServer(t *testing.T) *captureServer {
+	t.Helper()
+	cs := &captureServer{
+		received: make(chan struct{}, 1024),
+		status:   http.StatusOK,
+	}
+	cs.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		cs.mu.Lock()
+		cs.requests = append(cs.requests, capturedRequest{Path: r.URL.Path, Header: r.Header.Clone(), Body: body})
+		status := cs.status
+		cs.mu.Unlock()
+		w.WriteHeader(status)
+		cs.received <- struct{}{}
+	}))
+	t.Cleanup(cs.Close)
+	return cs
+}
+
+func (cs *captureServer) setStatus(status int) {
+	cs.mu.Lock()
+	cs.status = status
+	cs.mu.Unlock()
+}
+
+func (cs *captureServer) requestsFor(path string) []capturedRequest {
+	cs.mu.Lock()
+	defer cs.mu.Unlock()
+	var out []capturedRequest
+	for _, r := range cs.requests {
+		if r.Path == path {
+			out = append(out, r)
+		}
+	}
+	return out
+}
+
+// waitForRequest blocks until the server has received a request or the
+// timeout elapses.
+func (cs *captureServer) waitForRequest(t *testing.T, timeout time.Duration) {
+	t.Helper()
+	select {
+	case <-cs.received:
+	case <-time.After(timeout):
+		t.Fatalf("no request received within %v", timeout)
+	}
+}
+
+// decodeLogRequests decodes every /logs/batch body the server received.
+func (cs *captureServer) decodeLogRequests(t *testing.T) []LogRequest {
+	t.Helper()
+	var out []LogRequest
+	for _, r := range cs.requestsFor("/logs/batch") {
+		var req LogRequest
+		if err := json.Unmarshal(r.Body, &req); err != nil {
+			t.Fatalf("failed to decode log request: %v", err)
+		}
+		out = append(out, req)
+	}
+	return out
+}
+
+// exportedSpans decodes every span the server received.
+func exportedSpans(t *testing.T, server *captureServer) []InternalSpan {
+	t.Helper()
+	var spans []InternalSpan
+	for _, r := range server.requestsFor("/spans/batch") {
+		var req SpanBatchRequest
+		if err := json.Unmarshal(r.Body, &req); err != nil {
+			t.Fatalf("failed to decode span request: %v", err)
+		}
+		spans = append(spans, req.Payload.Spans...)
+	}
+	return spans
+}
+
+// testConfig returns a config pointed at url that never touches global slog
+// state and never flushes on the batch ticker during a test.
+func testConfig(url string) *Config {
+	config := NewConfig().
+		WithAPIKey("test-key").
+		WithBaseURL(url).
+		WithProjectName("test").
+		WithReplaceSlog(false)
+	config.BatchTimeout = time.Hour
+	return config
+}
+
+// recordingHandler is a slog.Handler that keeps every record it handles.
+type recordingHandler struct {
+	mu      sync.Mutex
+	records []slog.Record
+}
+
+func (h *recordingHandler) Enabled(context.Context, slog.Level) bool { return true }
+
+func (h *recordingHandler) Handle(_ context.Context, r slog.Record) error {
+	h.mu.Lock()
+	h.records = append(h.records, r.Clone())
+	h.mu.Unlock()
+	return nil
+}
+
+func (h *recordingHandler) WithAttrs([]slog.Attr) slog.Handler { return h }
+
+func (h *recordingHandler) WithGroup(string) slog.Handler { return h }
+
+func (h *recordingHandler) all() []slog.Record {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return append([]slog.Record(nil), h.records...)
+}
+
+// recordAttrs flattens the attributes of r into a map for easy assertions.
+func recordAttrs(r slog.Record) map[string]slog.Value {
+	attrs := make(map[string]slog.Value)
+	r.Attrs(func(a slog.Attr) bool {
+		attrs[a.Key] = a.Value
+		return true
+	})
+	return attrs
+}
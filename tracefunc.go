@@ -0,0 +1,71 @@
+package lumberjack
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// TraceFunc runs fn inside a span named name. If fn returns an error it is
+// recorded on the span as an exception event and the span status is set to
+// Error. The error is returned unchanged.
+//
+//	err := sdk.TraceFunc(ctx, "charge-card", func(ctx context.Context) error {
+//		return payments.Charge(ctx, order)
+//	})
+func (s *SDK) TraceFunc(ctx context.Context, name string, fn func(context.Context) error, opts ...trace.SpanStartOption) error {
+	_, err := TraceFuncWithResult(s, ctx, name, func(ctx context.Context) (struct{}, error) {
+		return struct{}{}, fn(ctx)
+	}, opts...)
+	return err
+}
+
+// TraceFuncWithResult is TraceFunc for functions that also return a value. It
+// takes the SDK as an argument because methods cannot have type parameters;
+// pass lumberjack.Get() to use the global SDK.
+//
+//	user, err := lumberjack.TraceFuncWithResult(sdk, ctx, "load-user", func(ctx context.Context) (*User, error) {
+//		return db.LoadUser(ctx, id)
+//	})
+func TraceFuncWithResult[T any](s *SDK, ctx context.Context, name string, fn func(context.Context) (T, error), opts ...trace.SpanStartOption) (T, error) {
+	ctx, span := s.StartSpan(ctx, name, opts...)
+	defer span.End()
+
+	result, err := fn(ctx)
+	if err != nil {
+		recordSpanError(span, err)
+	}
+	return result, err
+}
+
+// TraceFunc runs fn inside a span using the global SDK.
+func TraceFunc(ctx context.Context, name string, fn func(context.Context) error, opts ...trace.SpanStartOption) error {
+	return Get().TraceFunc(ctx, name, fn, opts...)
+}
+
+// RecordError records err on the span in ctx as an exception event and sets
+// the span status to Error. It does nothing if err is nil or ctx has no
+// recording span.
+//
+//	if err := payments.Charge(ctx, order); err != nil {
+//		lumberjack.RecordError(ctx, err, trace.WithAttributes(attribute.String("order.id", order.ID)))
+//		return err
+//	}
+func RecordError(ctx context.Context, err error, opts ...trace.EventOption) {
+	span := trace.SpanFromContext(ctx)
+	if err == nil || !span.IsRecording() {
+		return
+	}
+	recordSpanError(span, err, opts...)
+}
+
+// SetSpanError is RecordError without event options.
+func SetSpanError(ctx context.Context, err error) {
+	RecordError(ctx, err)
+}
+
+func recordSpanError(span trace.Span, err error, opts ...trace.EventOption) {
+	span.RecordError(err, opts...)
+	span.SetStatus(codes.Error, err.Error())
+}
@@ -0,0 +1,110 @@
+package lumberjack
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestTraceFunc(t *testing.T) {
+	sdk, spans := newTracingTestSDK(t)
+	wantErr := errors.New("card declined")
+
+	var innerSpan trace.SpanContext
+	err := sdk.TraceFunc(context.Background(), "charge-card", func(ctx context.Context) error {
+		innerSpan = trace.SpanContextFromContext(ctx)
+		return wantErr
+	})
+	if err != wantErr {
+		t.Errorf("TraceFunc() error = %v, want %v", err, wantErr)
+	}
+
+	sdk.tracerProvider.ForceFlush(context.Background())
+	got := spans.GetSpans()
+	if len(got) != 1 {
+		t.Fatalf("expected 1 exported span, got %d", len(got))
+	}
+	span := got[0]
+	if span.Name != "charge-card" {
+		t.Errorf("span name = %q, want %q", span.Name, "charge-card")
+	}
+	if span.SpanContext.SpanID() != innerSpan.SpanID() {
+		t.Errorf("fn did not run inside the span")
+	}
+	if span.Status.Code != codes.Error || span.Status.Description != "card declined" {
+		t.Errorf("span status = %+v, want Error with the error message", span.Status)
+	}
+	if len(span.Events) != 1 || span.Events[0].Name != "exception" {
+		t.Fatalf("span events = %+v, want one exception event", span.Events)
+	}
+	for _, attr := range span.Events[0].Attributes {
+		if attr.Key == "exception.message" && attr.Value.AsString() != "card declined" {
+			t.Errorf("exception.message = %q, want %q", attr.Value.AsString(), "card declined")
+		}
+	}
+}
+
+func TestTraceFuncWithResult(t *testing.T) {
+	sdk, spans := newTracingTestSDK(t)
+
+	got, err := TraceFuncWithResult(sdk, context.Background(), "load-user", func(ctx context.Context) (string, error) {
+		return "ada", nil
+	})
+	if err != nil || got != "ada" {
+		t.Errorf("TraceFuncWithResult() = %q, %v; want %q, nil", got, err, "ada")
+	}
+
+	sdk.tracerProvider.ForceFlush(context.Background())
+	exported := spans.GetSpans()
+	if len(exported) != 1 {
+		t.Fatalf("expected 1 exported span, got %d", len(exported))
+	}
+	if exported[0].Status.Code != codes.Unset || len(exported[0].Events) != 0 {
+		t.Errorf("successful call recorded status %+v and events %+v", exported[0].Status, exported[0].Events)
+	}
+}
+
+func TestRecordError(t *testing.T) {
+	sdk, spans := newTracingTestSDK(t)
+
+	ctx, span := sdk.StartSpan(context.Background(), "charge-card")
+	RecordError(ctx, errors.New("card declined"), trace.WithAttributes(attribute.String("order.id", "42")))
+	span.End()
+
+	ctx, span = sdk.StartSpan(context.Background(), "refund")
+	SetSpanError(ctx, errors.New("refund failed"))
+	span.End()
+
+	// No span, or no error: nothing to record and nothing to panic about
+	RecordError(context.Background(), errors.New("orphan"))
+	SetSpanError(ctx, nil)
+
+	sdk.tracerProvider.ForceFlush(context.Background())
+	got := spans.GetSpans()
+	if len(got) != 2 {
+		t.Fatalf("expected 2 exported spans, got %d", len(got))
+	}
+	for i, want := range []string{"card declined", "refund failed"} {
+		span := got[i]
+		if span.Status.Code != codes.Error || span.Status.Description != want {
+			t.Errorf("%s: span status = %+v, want Error %q", span.Name, span.Status, want)
+		}
+		if len(span.Events) != 1 || span.Events[0].Name != "exception" {
+			t.Fatalf("%s: span events = %+v, want one exception event", span.Name, span.Events)
+		}
+	}
+
+	var orderID string
+	for _, attr := range got[0].Events[0].Attributes {
+		if attr.Key == "order.id" {
+			orderID = attr.Value.AsString()
+		}
+	}
+	if orderID != "42" {
+		t.Errorf("exception event order.id = %q, want the event option attribute %q", orderID, "42")
+	}
+}
@@ -0,0 +1,60 @@
+package lumberjack
+
+import (
+	"net/http"
+
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.17.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Transport wraps base so that every outgoing request runs inside a client
+// span whose context is injected as traceparent and tracestate headers (and
+// baggage, if any). The response status is recorded on the span, which ends
+// when the response headers arrive; 4xx and 5xx responses and transport
+// errors mark it as an error. A nil base uses http.DefaultTransport:
+//
+//	client := &http.Client{Transport: sdk.Transport(http.DefaultTransport)}
+func (s *SDK) Transport(base http.RoundTripper) http.RoundTripper {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return &tracingTransport{sdk: s, base: base}
+}
+
+// Transport wraps base with the global SDK's tracing transport.
+func Transport(base http.RoundTripper) http.RoundTripper {
+	return Get().Transport(base)
+}
+
+type tracingTransport struct {
+	sdk  *SDK
+	base http.RoundTripper
+}
+
+func (t *tracingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	ctx, span := t.sdk.StartSpan(req.Context(), req.Method,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			semconv.HTTPMethod(req.Method),
+			semconv.HTTPURL(req.URL.String()),
+			semconv.NetPeerName(req.URL.Hostname()),
+		),
+	)
+	defer span.End()
+
+	// A RoundTripper must not modify the caller's request
+	req = req.Clone(ctx)
+	InjectIntoHeader(ctx, req.Header)
+
+	resp, err := t.base.RoundTrip(req)
+	if err != nil {
+		recordSpanError(span, err)
+		return nil, err
+	}
+	span.SetAttributes(semconv.HTTPStatusCode(resp.StatusCode))
+	if resp.StatusCode >= http.StatusBadRequest {
+		span.SetStatus(codes.Error, http.StatusText(resp.StatusCode))
+	}
+	return resp, nil
+}
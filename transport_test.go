@@ -0,0 +1,129 @@
+package lumberjack
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestTransport(t *testing.T) {
+	sdk, spans := newTracingTestSDK(t)
+
+	var received string
+	downstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received = r.Header.Get("traceparent")
+		if r.URL.Path == "/missing" {
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer downstream.Close()
+	client := &http.Client{Transport: sdk.Transport(nil)}
+
+	tests := []struct {
+		path       string
+		wantStatus codes.Code
+	}{
+		{"/items", codes.Unset},
+		{"/missing", codes.Error},
+	}
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			spans.Reset()
+			received = ""
+
+			ctx, parent := sdk.StartSpan(context.Background(), "checkout")
+			req, err := http.NewRequestWithContext(ctx, http.MethodGet, downstream.URL+tt.path, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			resp, err := client.Do(req)
+			if err != nil {
+				t.Fatalf("client.Do() unexpected error = %v", err)
+			}
+			resp.Body.Close()
+			parent.End()
+			if req.Header.Get("traceparent") != "" {
+				t.Error("Transport modified the caller's request headers")
+			}
+
+			sdk.tracerProvider.ForceFlush(context.Background())
+			var clientSpan *trace.SpanContext
+			for _, span := range spans.GetSpans() {
+				if span.SpanKind == trace.SpanKindClient {
+					spanCtx := span.SpanContext
+					clientSpan = &spanCtx
+					if span.Parent.SpanID() != parent.SpanContext().SpanID() {
+						t.Errorf("client span parent = %s, want the caller's span %s", span.Parent.SpanID(), parent.SpanContext().SpanID())
+					}
+					if span.Status.Code != tt.wantStatus {
+						t.Errorf("client span status = %v, want %v", span.Status.Code, tt.wantStatus)
+					}
+				}
+			}
+			if clientSpan == nil {
+				t.Fatal("no client span was exported")
+			}
+
+			spanCtx, err := parseTraceparent(received)
+			if err != nil {
+				t.Fatalf("downstream received traceparent %q: %v", received, err)
+			}
+			if spanCtx.TraceID() != clientSpan.TraceID() || spanCtx.SpanID() != clientSpan.SpanID() {
+				t.Errorf("downstream traceparent = %q, want the client span %s/%s", received, clientSpan.TraceID(), clientSpan.SpanID())
+			}
+		})
+	}
+}
+
+func TestTransportExportsStatusCode(t *testing.T) {
+	server := newCaptureServer(t)
+	sdk := newSDK(testConfig(server.URL))
+
+	downstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer downstream.Close()
+	client := &http.Client{Transport: sdk.Transport(nil)}
+
+	resp, err := client.Get(downstream.URL + "/missing")
+	if err != nil {
+		t.Fatalf("client.Get() unexpected error = %v", err)
+	}
+	resp.Body.Close()
+	sdk.Shutdown(context.Background())
+
+	var found bool
+	for _, span := range exportedSpans(t, server) {
+		if span.Kind != int(trace.SpanKindClient) {
+			continue
+		}
+		found = true
+		if got := span.Attributes["http.status_code"]; got != "404" {
+			t.Errorf("http.status_code = %q, want %q", got, "404")
+		}
+	}
+	if !found {
+		t.Fatal("no client span was exported")
+	}
+}
+
+func TestTransportError(t *testing.T) {
+	sdk, spans := newTracingTestSDK(t)
+	client := &http.Client{Transport: sdk.Transport(nil)}
+
+	downstream := httptest.NewServer(http.NotFoundHandler())
+	downstream.Close()
+	if _, err := client.Get(downstream.URL); err == nil {
+		t.Fatal("client.Get() to a closed server unexpectedly succeeded")
+	}
+
+	sdk.tracerProvider.ForceFlush(context.Background())
+	got := spans.GetSpans()
+	if len(got) != 1 || got[0].Status.Code != codes.Error || len(got[0].Events) == 0 {
+		t.Errorf("exported spans = %+v, want one client span with an error status and event", got)
+	}
+}
@@ -0,0 +1,42 @@
+package lumberjack
+
+import (
+	"fmt"
+	"unicode/utf8"
+)
+
+// truncatedKey is the prop set on log entries whose message or attribute
+// values were shortened.
+const truncatedKey = "truncated"
+
+// truncateString shortens s to at most max bytes, cutting on a rune boundary
+// and appending a marker with the number of bytes dropped. max <= 0 means
+// unlimited.
+func truncateString(s string, max int) (string, bool) {
+	if max <= 0 || len(s) <= max {
+		return s, false
+	}
+	cut := max
+	for cut > 0 && !utf8.RuneStart(s[cut]) {
+		cut--
+	}
+	return fmt.Sprintf("%s…(truncated %d bytes)", s[:cut], len(s)-cut), true
+}
+
+// truncateProps shortens string values in props to at most max bytes and
+// reports whether any were shortened.
+func truncateProps(props map[string]interface{}, max int) bool {
+	if max <= 0 {
+		return false
+	}
+	truncated := false
+	for key, value := range props {
+		if s, ok := value.(string); ok {
+			if short, cut := truncateString(s, max); cut {
+				props[key] = short
+				truncated = true
+			}
+		}
+	}
+	return truncated
+}
@@ -0,0 +1,66 @@
+package lumberjack
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestTruncateString(t *testing.T) {
+	tests := []struct {
+		name    string
+		in      string
+		max     int
+		want    string
+		wantCut bool
+	}{
+		{name: "unlimited", in: "hello", max: 0, want: "hello"},
+		{name: "at limit", in: "hello", max: 5, want: "hello"},
+		{name: "over limit", in: "hello world", max: 5, want: "hello…(truncated 6 bytes)", wantCut: true},
+		{name: "rune boundary", in: "héllo", max: 2, want: "h…(truncated 5 bytes)", wantCut: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, cut := truncateString(tt.in, tt.max)
+			if got != tt.want || cut != tt.wantCut {
+				t.Errorf("truncateString(%q, %d) = %q, %v, want %q, %v", tt.in, tt.max, got, cut, tt.want, tt.wantCut)
+			}
+		})
+	}
+}
+
+func TestLogTruncation(t *testing.T) {
+	server := newCaptureServer(t)
+	config := testConfig(server.URL).WithMaxMessageBytes(10).WithMaxAttrValueBytes(4)
+	sdk := newSDK(config)
+
+	sdk.Logger().Info(strings.Repeat("m", 10), "short", "abcd", "long", "abcdef")
+	sdk.Logger().Info(strings.Repeat("m", 11))
+	sdk.Shutdown(context.Background())
+
+	requests := server.decodeLogRequests(t)
+	if len(requests) != 1 || len(requests[0].Logs) != 2 {
+		t.Fatalf("expected two exported logs, got %+v", requests)
+	}
+	attrs, msg := requests[0].Logs[0], requests[0].Logs[1]
+
+	if attrs.Msg != strings.Repeat("m", 10) {
+		t.Errorf("message at the limit = %q, want it unchanged", attrs.Msg)
+	}
+	if attrs.Props["short"] != "abcd" {
+		t.Errorf("props[short] = %v, want it unchanged", attrs.Props["short"])
+	}
+	if attrs.Props["long"] != "abcd…(truncated 2 bytes)" {
+		t.Errorf("props[long] = %v, want it truncated", attrs.Props["long"])
+	}
+	if attrs.Props[truncatedKey] != "true" {
+		t.Errorf("props[truncated] = %v, want true", attrs.Props[truncatedKey])
+	}
+
+	if msg.Msg != strings.Repeat("m", 10)+"…(truncated 1 bytes)" {
+		t.Errorf("message over the limit = %q, want it truncated", msg.Msg)
+	}
+	if msg.Props[truncatedKey] != "true" {
+		t.Errorf("props[truncated] = %v, want true", msg.Props[truncatedKey])
+	}
+}
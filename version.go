@@ -0,0 +1,48 @@
+package lumberjack
+
+import (
+	"runtime/debug"
+	"strings"
+	"sync"
+)
+
+// Version is the version of this SDK, reported in the default User-Agent and
+// as sdk_semver in export requests.
+const Version = "2.0.0"
+
+// sdkVersion is sent as sdk_version in export requests: the major version of
+// Version, which identifies the payload schema.
+const sdkVersion = 2
+
+// modulePath is this SDK's module path, looked up in the binary's build info.
+const modulePath = "github.com/TreebeardHQ/go-sdk"
+
+// sdkRelease returns the version reported as sdk_semver: the SDK's module
+// version from the binary's build info when it was built as a tagged
+// dependency, or Version otherwise (e.g. in development builds).
+var sdkRelease = sync.OnceValue(func() string {
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		return Version
+	}
+	for _, dep := range info.Deps {
+		if dep.Path != modulePath {
+			continue
+		}
+		version := dep.Version
+		if dep.Replace != nil {
+			version = dep.Replace.Version
+		}
+		if version == "" || version == "(devel)" {
+			break
+		}
+		return strings.TrimPrefix(version, "v")
+	}
+	return Version
+})
+
+// defaultUserAgent is sent with every export request unless Config.UserAgent
+// overrides it.
+func defaultUserAgent() string {
+	return "lumberjack-go/" + sdkRelease()
+}
@@ -0,0 +1,41 @@
+package lumberjack
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+)
+
+func TestSDKVersionInRequests(t *testing.T) {
+	server := newCaptureServer(t)
+	sdk := newSDK(testConfig(server.URL))
+
+	ctx, span := sdk.StartSpan(context.Background(), "checkout")
+	sdk.Logger().InfoContext(ctx, "order placed")
+	span.End()
+	counter, err := sdk.Meter().Int64Counter("orders.placed")
+	if err != nil {
+		t.Fatalf("Int64Counter() unexpected error = %v", err)
+	}
+	counter.Add(ctx, 1)
+	collectMetrics(t, sdk, server)
+	sdk.Shutdown(context.Background())
+
+	for _, path := range []string{"/logs/batch", "/spans/batch", "/metrics/batch"} {
+		requests := server.requestsFor(path)
+		if len(requests) == 0 {
+			t.Errorf("no request sent to %s", path)
+			continue
+		}
+		var body struct {
+			SdkVersion int    `json:"sdk_version"`
+			SdkSemver  string `json:"sdk_semver"`
+		}
+		if err := json.Unmarshal(requests[0].Body, &body); err != nil {
+			t.Fatalf("decoding %s request: %v", path, err)
+		}
+		if body.SdkVersion != sdkVersion || body.SdkSemver != Version {
+			t.Errorf("%s sdk_version/sdk_semver = %d/%q, want %d/%q", path, body.SdkVersion, body.SdkSemver, sdkVersion, Version)
+		}
+	}
+}